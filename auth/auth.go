@@ -0,0 +1,122 @@
+// Package auth verifies JWTs of players and keeps principal of request in its context.
+// Tokens are signed by HMAC or RSA keys, which are configured locally, subject of token is player id.
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// Principal is who has made request. Player principal has id of player from token, principal of API key has its id.
+type Principal struct {
+	PlayerID string
+	Role     string
+	KeyID    string
+}
+
+// Key returns key of principal for rate limits: id of API key or id of player, it is empty for unknown principal
+func (p Principal) Key() string {
+	switch {
+	case p.KeyID != "":
+		return "key:" + p.KeyID
+	case p.PlayerID != "":
+		return "player:" + p.PlayerID
+	}
+	return ""
+}
+
+// roleRanks orders roles of API keys, role can use routes and methods of its rank and lower ones
+var roleRanks = map[string]int{entity.RoleReadOnly: 1, entity.RoleClient: 2, entity.RoleAdmin: 3}
+
+// Allows returns whether API key with role can use route or method, which needs role needed
+func Allows(role, needed string) bool {
+	return roleRanks[role] >= roleRanks[needed]
+}
+
+// CheckPlayer returns forbidden error, if ctx carries principal of player with other id than id
+func CheckPlayer(ctx context.Context, id string) error {
+	p, ok := FromContext(ctx)
+	if !ok || p.PlayerID == "" || p.PlayerID == id {
+		return nil
+	}
+	return errors.Error{Code: errors.ForbiddenError, Message: "authorize: player can use only own id, id: " + p.PlayerID, Info: id}
+}
+
+// CheckJoin returns forbidden error, if ctx carries principal of player, who joins tournament as other player
+// or joins team. Team join pays deposit from balances of other members, so players cannot join teams.
+func CheckJoin(ctx context.Context, playerID, teamID string) error {
+	p, ok := FromContext(ctx)
+	if ok && p.PlayerID != "" && teamID != "" {
+		return errors.Error{Code: errors.ForbiddenError, Message: "authorize: player cannot join team, id: " + p.PlayerID, Info: teamID}
+	}
+	return CheckPlayer(ctx, playerID)
+}
+
+// BearerToken returns token of authorization header, it is empty, if header has no bearer token
+func BearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+// principalKey is context key of principal
+type principalKey struct{}
+
+// NewContext returns copy of ctx, which carries principal p
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns principal, which ctx carries, ok is false, if ctx has no principal
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Verifier verifies tokens signed by HMAC key with HS256, HS384 or HS512 or by private pair of RSA key with
+// RS256, RS384 or RS512. Token is accepted only by algorithm of key, which is set. Token must have expiry,
+// subject and audience, which contains Audience. Verifier without Audience accepts no tokens.
+type Verifier struct {
+	HMACKey  []byte
+	RSAKey   *rsa.PublicKey
+	Audience string
+}
+
+// Verify returns player principal of token, error has unauthorized code, if token is not valid
+func (v Verifier) Verify(token string) (Principal, error) {
+	var methods []string
+	if len(v.HMACKey) > 0 {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if v.RSAKey != nil {
+		methods = append(methods, "RS256", "RS384", "RS512")
+	}
+	if v.Audience == "" {
+		return Principal{}, errors.Error{Code: errors.UnauthorizedError, Message: "verify token: audience is not set"}
+	}
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, v.key, jwt.WithValidMethods(methods), jwt.WithExpirationRequired(), jwt.WithAudience(v.Audience))
+	if err != nil {
+		return Principal{}, errors.Error{Code: errors.UnauthorizedError, Message: "verify token: token is not valid", Info: err.Error()}
+	}
+	if claims.Subject == "" {
+		return Principal{}, errors.Error{Code: errors.UnauthorizedError, Message: "verify token: token has no subject"}
+	}
+	return Principal{PlayerID: claims.Subject, Role: entity.RolePlayer}, nil
+}
+
+// key returns key, which verifies signature of token
+func (v Verifier) key(t *jwt.Token) (interface{}, error) {
+	if strings.HasPrefix(t.Method.Alg(), "RS") {
+		return v.RSAKey, nil
+	}
+	return v.HMACKey, nil
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	secret := []byte("secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	v := Verifier{HMACKey: secret, RSAKey: &rsaKey.PublicKey, Audience: "tournament"}
+	claims := func(subject, audience string, expires time.Time) jwt.RegisteredClaims {
+		c := jwt.RegisteredClaims{Subject: subject, Audience: jwt.ClaimStrings{audience}}
+		if !expires.IsZero() {
+			c.ExpiresAt = jwt.NewNumericDate(expires)
+		}
+		return c
+	}
+	sign := func(method jwt.SigningMethod, key interface{}, c jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(method, c).SignedString(key)
+		assert.Nil(t, err)
+		return token
+	}
+	hour := time.Now().Add(time.Hour)
+	tt := []struct {
+		name          string
+		verifier      Verifier
+		token         string
+		expectedError bool
+	}{
+		{name: "hmac", verifier: v, token: sign(jwt.SigningMethodHS256, secret, claims("player", "tournament", hour))},
+		{name: "rsa", verifier: v, token: sign(jwt.SigningMethodRS256, rsaKey, claims("player", "tournament", hour))},
+		{name: "hmac sha512", verifier: v, token: sign(jwt.SigningMethodHS512, secret, claims("player", "tournament", hour))},
+		{name: "verifier without audience", verifier: Verifier{HMACKey: secret}, token: sign(jwt.SigningMethodHS256, secret, claims("player", "", hour)), expectedError: true},
+		{name: "wrong hmac key", verifier: v, token: sign(jwt.SigningMethodHS256, []byte("other"), claims("player", "tournament", hour)), expectedError: true},
+		{name: "wrong rsa key", verifier: v, token: sign(jwt.SigningMethodRS256, otherKey, claims("player", "tournament", hour)), expectedError: true},
+		{name: "rsa without rsa key", verifier: Verifier{HMACKey: secret, Audience: "tournament"}, token: sign(jwt.SigningMethodRS256, rsaKey, claims("player", "tournament", hour)), expectedError: true},
+		{name: "hmac without hmac key", verifier: Verifier{RSAKey: &rsaKey.PublicKey, Audience: "tournament"}, token: sign(jwt.SigningMethodHS256, secret, claims("player", "tournament", hour)), expectedError: true},
+		{name: "none", verifier: v, token: sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims("player", "tournament", hour)), expectedError: true},
+		{name: "expired", verifier: v, token: sign(jwt.SigningMethodHS256, secret, claims("player", "tournament", time.Now().Add(-time.Hour))), expectedError: true},
+		{name: "without expiry", verifier: v, token: sign(jwt.SigningMethodHS256, secret, claims("player", "tournament", time.Time{})), expectedError: true},
+		{name: "wrong audience", verifier: v, token: sign(jwt.SigningMethodHS256, secret, claims("player", "other", hour)), expectedError: true},
+		{name: "without subject", verifier: v, token: sign(jwt.SigningMethodHS256, secret, claims("", "tournament", hour)), expectedError: true},
+		{name: "not token", verifier: v, token: "token", expectedError: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := tc.verifier.Verify(tc.token)
+			if tc.expectedError {
+				if assert.NotNil(t, err) {
+					assert.Equal(t, errors.UnauthorizedError, errors.Transform(err).Code)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, Principal{PlayerID: "player", Role: entity.RolePlayer}, p)
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+	p := Principal{PlayerID: "player", Role: entity.RolePlayer}
+	got, ok := FromContext(NewContext(context.Background(), p))
+	assert.True(t, ok)
+	assert.Equal(t, p, got)
+}
+
+func TestCheckJoin(t *testing.T) {
+	player := NewContext(context.Background(), Principal{PlayerID: "player", Role: entity.RolePlayer})
+	client := NewContext(context.Background(), Principal{KeyID: "key", Role: entity.RoleClient})
+	tt := []struct {
+		name          string
+		ctx           context.Context
+		playerID      string
+		teamID        string
+		expectedError error
+	}{
+		{name: "player joins as self", ctx: player, playerID: "player"},
+		{name: "player joins as other", ctx: player, playerID: "other",
+			expectedError: errors.Error{Code: errors.ForbiddenError, Message: "authorize: player can use only own id, id: player", Info: "other"}},
+		{name: "player joins team", ctx: player, playerID: "player", teamID: "team",
+			expectedError: errors.Error{Code: errors.ForbiddenError, Message: "authorize: player cannot join team, id: player", Info: "team"}},
+		{name: "api key joins team", ctx: client, teamID: "team"},
+		{name: "without principal", ctx: context.Background(), playerID: "other"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedError, CheckJoin(tc.ctx, tc.playerID, tc.teamID))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// AccountDB is an interface for database, that used to controll player accounts
+type AccountDB interface {
+	CreateAccount(account entity.Account) error
+	GetAccount(id string) (entity.Account, error)
+	SetAccountStatus(id, status string) error
+	CloseAccount(id string, withdraw bool) (entity.Closure, error)
+	ListAccounts(filter entity.PlayerFilter) ([]entity.Account, error)
+}
+
+// Register controlls registering player account, registered account is active
+func (g Game) Register(id, name string, metadata map[string]string) (entity.Account, error) {
+	if id == "" {
+		return entity.Account{}, errors.Error{Code: errors.NotFoundError, Message: "register: id must be not nil"}
+	}
+	if name == "" {
+		return entity.Account{}, errors.Error{Code: errors.NotFoundError, Message: "register: name must be not nil, id: " + id}
+	}
+	account := entity.Account{
+		ID:       id,
+		Name:     name,
+		Created:  time.Now().UTC().Truncate(time.Second),
+		Metadata: metadata,
+		Status:   entity.StatusActive,
+	}
+	err := g.DB.CreateAccount(account)
+	if err != nil {
+		return entity.Account{}, err
+	}
+	return account, nil
+}
+
+// Account returns player account
+func (g Game) Account(id string) (entity.Account, error) {
+	if id == "" {
+		return entity.Account{}, errors.Error{Code: errors.NotFoundError, Message: "account: id must be not nil"}
+	}
+	return g.DB.GetAccount(id)
+}
+
+// Suspend controlls suspending active account, suspended player cannot fund, spend points or join tournaments
+func (g Game) Suspend(id string) (entity.Account, error) {
+	return g.setStatus("suspend", id, entity.StatusActive, entity.StatusSuspended)
+}
+
+// Reinstate controlls making suspended account active again
+func (g Game) Reinstate(id string) (entity.Account, error) {
+	return g.setStatus("reinstate", id, entity.StatusSuspended, entity.StatusActive)
+}
+
+// CloseAccount controlls closing player account. Player with entries in open tournaments is withdrawn from them
+// with deposits returned, if withdraw is set, otherwise closing is refused. Remaining balances are paid out
+// and account is marked closed, so its history is kept.
+func (g Game) CloseAccount(id string, withdraw bool) (entity.Closure, error) {
+	if id == "" {
+		return entity.Closure{}, errors.Error{Code: errors.NotFoundError, Message: "close account: id must be not nil"}
+	}
+	account, err := g.DB.GetAccount(id)
+	if err == nil && account.Status == entity.StatusClosed {
+		return entity.Closure{}, errors.Error{Code: errors.InactiveAccountError, Message: "close account: account is closed, id: " + id}
+	}
+	if err != nil && errors.Transform(err).Code != errors.NotFoundError {
+		return entity.Closure{}, err
+	}
+	closure, err := g.DB.CloseAccount(id, withdraw)
+	if err != nil {
+		return closure, err
+	}
+	var events []entity.Event
+	for _, p := range closure.Payouts {
+		events = append(events, balanceChanged(id, p.Currency, -p.Points))
+	}
+	g.publish(events...)
+	return closure, nil
+}
+
+// setStatus changes account status, if account has status from
+func (g Game) setStatus(op, id, from, to string) (entity.Account, error) {
+	if id == "" {
+		return entity.Account{}, errors.Error{Code: errors.NotFoundError, Message: op + ": id must be not nil"}
+	}
+	account, err := g.DB.GetAccount(id)
+	if err != nil {
+		return entity.Account{}, err
+	}
+	if account.Status != from {
+		return entity.Account{}, errors.Error{Code: errors.InactiveAccountError, Message: op + ": account is " + account.Status + ", id: " + id}
+	}
+	err = g.DB.SetAccountStatus(id, to)
+	if err != nil {
+		return entity.Account{}, err
+	}
+	account.Status = to
+	return account, nil
+}
+
+// checkAccount returns error of operation op, if player is not registered or player account is suspended or closed
+func (g Game) checkAccount(op, id string) error {
+	account, err := g.DB.GetAccount(id)
+	if err != nil {
+		err := errors.Transform(err)
+		if err.Code == errors.NotFoundError {
+			return errors.Error{Code: errors.NotFoundError, Message: op + ": player is not registered, id: " + id}
+		}
+		return err
+	}
+	if account.Status != entity.StatusActive {
+		return errors.Error{Code: errors.InactiveAccountError, Message: op + ": account is " + account.Status + ", id: " + id}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// BatchDB is an interface for database, that used to apply batches of operations
+type BatchDB interface {
+	// ApplyBatch applies every operation in one transaction, nothing is applied, if one of them fails.
+	// Index of failed operation is returned with its error, it is -1, if batch has failed as whole.
+	ApplyBatch(ops []entity.Operation) (int, error)
+	// ApplyEach applies every operation independently and returns their errors in order of operations,
+	// error of applied operation is nil. Error is returned, if batch has failed as whole.
+	ApplyEach(ops []entity.Operation) ([]error, error)
+}
+
+// MaxBatchSize is the most number of operations in one batch
+const MaxBatchSize = 10000
+
+// Batch controlls applying batch of fund, take and join operations. Atomic batch is applied as whole or not at all,
+// otherwise every operation is applied independently. Operations are checked like single ones, spending of
+// previous operations of batch is counted in player limits.
+func (g Game) Batch(ops []entity.Operation, atomic bool) (entity.BatchResult, error) {
+	if len(ops) == 0 {
+		return entity.BatchResult{}, errors.Error{Code: errors.InvalidBatchError, Message: "batch: batch must have operations"}
+	}
+	if len(ops) > MaxBatchSize {
+		return entity.BatchResult{}, errors.Error{Code: errors.InvalidBatchError, Message: "batch: batch cannot have more than " + strconv.Itoa(MaxBatchSize) + " operations"}
+	}
+	result := entity.BatchResult{Atomic: atomic, Results: make([]entity.OperationResult, len(ops))}
+	var (
+		valid   []entity.Operation
+		indexes []int
+		spends  []int
+		pending = make(map[string]int)
+	)
+	for i, op := range ops {
+		op.Currency = currencyOrDefault(op.Currency)
+		spent, err := g.checkOperation(op, pending[op.PlayerID])
+		if err != nil {
+			fail(&result, i, err)
+			if atomic {
+				return abort(result), nil
+			}
+			continue
+		}
+		pending[op.PlayerID] += spent
+		valid = append(valid, op)
+		indexes = append(indexes, i)
+		spends = append(spends, spent)
+	}
+	errs := make([]error, len(valid))
+	if atomic {
+		n, err := g.DB.ApplyBatch(valid)
+		if err != nil {
+			if n < 0 {
+				return entity.BatchResult{}, err
+			}
+			fail(&result, indexes[n], operationError(valid[n], err))
+			return abort(result), nil
+		}
+	} else if len(valid) > 0 {
+		var err error
+		errs, err = g.DB.ApplyEach(valid)
+		if err != nil {
+			return entity.BatchResult{}, err
+		}
+	}
+	var events []entity.Event
+	for k, op := range valid {
+		if errs[k] != nil {
+			fail(&result, indexes[k], operationError(op, errs[k]))
+			continue
+		}
+		result.Results[indexes[k]].Status = entity.OperationApplied
+		result.Applied++
+		switch op.Type {
+		case entity.OpFund:
+			events = append(events, balanceChanged(op.PlayerID, op.Currency, op.Points))
+		case entity.OpTake:
+			events = append(events, balanceChanged(op.PlayerID, op.Currency, -op.Points))
+		case entity.OpJoin:
+			events = append(events, entity.Event{Type: entity.EventPlayerJoined, TournamentID: op.TournamentID, PlayerID: op.PlayerID})
+			if g.Events == nil {
+				continue
+			}
+			if currency, err := g.DB.GetCurrency(op.TournamentID); err == nil {
+				paid := balanceChanged(op.PlayerID, currency, -spends[k])
+				paid.TournamentID = op.TournamentID
+				events = append(events, paid)
+			}
+		}
+	}
+	g.publish(events...)
+	return result, nil
+}
+
+// checkOperation returns points, which operation spends, and error of operation of player, who is going to spend
+// pending points by previous operations of batch
+func (g Game) checkOperation(op entity.Operation, pending int) (int, error) {
+	switch op.Type {
+	case entity.OpFund:
+		return 0, g.checkFund(op.PlayerID, op.Points, 0)
+	case entity.OpTake:
+		return op.Points, g.checkTake(op.PlayerID, op.Points, pending)
+	case entity.OpJoin:
+		return g.checkJoin(op.TournamentID, op.PlayerID, pending)
+	}
+	return 0, errors.Error{Code: errors.InvalidBatchError, Message: "batch: unknown operation type, id: " + op.PlayerID, Info: op.Type}
+}
+
+// operationError returns error of operation, which database has returned
+func operationError(op entity.Operation, err error) error {
+	if op.Type == entity.OpTake {
+		return takeError(err)
+	}
+	return err
+}
+
+// fail marks operation i of batch as failed with error err
+func fail(result *entity.BatchResult, i int, err error) {
+	e := errors.Transform(err)
+	result.Results[i] = entity.OperationResult{Status: entity.OperationFailed, Error: &e}
+	result.Failed++
+}
+
+// abort marks every operation of atomic batch, which has not failed, as aborted
+func abort(result entity.BatchResult) entity.BatchResult {
+	for i := range result.Results {
+		if result.Results[i].Status != entity.OperationFailed {
+			result.Results[i].Status = entity.OperationAborted
+		}
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package controller
 
 import (
 	"math/rand"
+	"strconv"
 	"time"
 
 	"github.com/dmitriyomelyusik/Tournament/entity"
@@ -11,19 +12,31 @@ import (
 
 // PlayerDB is an interface for database, that used to controll player activity methods
 type PlayerDB interface {
-	GetPlayer(id string) (entity.Player, error)
-	CreatePlayer(id string, points int) (entity.Player, error)
-	UpdatePlayer(id string, dif int) error
+	GetPlayer(id, currency string) (entity.Player, error)
+	GetBalances(id string) ([]entity.Player, error)
+	CreatePlayer(id, currency string, points int) (entity.Player, error)
+	UpdatePlayer(id, currency string, dif int) error
+	TransferPoints(from, to, currency string, points int) error
 }
 
 // TourDB is an interface for database, that used to controll tournament activity methods
 type TourDB interface {
-	CreateTournament(id string, deposit int) error
+	CreateTournament(id, currency string, deposit, maxEntries int) error
 	GetTournamentState(id string) (bool, error)
 	GetWinner(id string) (entity.Winners, error)
 	CloseTournament(id string) error
 	GetParticipants(id string) ([]string, error)
+	GetEntries(id string) ([]entity.Entry, error)
+	GetMaxEntries(id string) (int, error)
+	GetDeposit(id string) (int, error)
+	GetCurrency(id string) (string, error)
 	SetTournamentWinner(id string, winner entity.Winner) error
+	CreateSatellite(id string, deposit int, targetID string, seats int) error
+	GetSatellite(id string) (entity.Satellite, error)
+	SetSatelliteWinners(id string, ranking []string, seats int) error
+	ListTournaments(filter entity.TourFilter) ([]entity.Tournament, error)
+	GetTournament(id string) (entity.Tournament, error)
+	GetHistory(playerID string) ([]entity.Participation, error)
 }
 
 // Database is an interface for database, that uses tournament and player database interfaces
@@ -31,93 +44,333 @@ type TourDB interface {
 type Database interface {
 	PlayerDB
 	TourDB
+	TeamDB
+	HoldDB
+	LotDB
+	PromoDB
+	LimitDB
+	AccountDB
+	WebhookDB
+	BatchDB
+	KeyDB
 	UpdateTourAndPlayer(tourID string, playerID string) error
 }
 
-// Game is a struct which methods controlls activity within database interface
+// Publisher publishes domain events
+type Publisher interface {
+	Publish(e entity.Event)
+}
+
+// Game is a struct which methods controlls activity within database interface.
+// Events are published after changes are saved, if publisher is set.
 type Game struct {
-	DB Database
+	DB     Database
+	Events Publisher
+}
+
+// publish publishes events, they are created now
+func (g Game) publish(events ...entity.Event) {
+	if g.Events == nil {
+		return
+	}
+	now := time.Now().UTC()
+	for _, e := range events {
+		e.Created = now
+		g.Events.Publish(e)
+	}
+}
+
+// balanceChanged returns event of changing player balance by points
+func balanceChanged(playerID, currency string, points int) entity.Event {
+	return entity.Event{Type: entity.EventBalanceChanged, PlayerID: playerID, Currency: currency, Points: points}
+}
+
+// currencyOrDefault returns default currency, if currency is not set
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return entity.DefaultCurrency
+	}
+	return currency
+}
+
+// Fund controlls funding player in currency, player gets balance in currency on first funding.
+// Funded points expire after expiresIn, if it is set, otherwise they never expire.
+func (g Game) Fund(id, currency string, points int, expiresIn time.Duration) (entity.Player, error) {
+	err := g.checkFund(id, points, expiresIn)
+	if err != nil {
+		return entity.Player{}, err
+	}
+	currency = currencyOrDefault(currency)
+	var player entity.Player
+	if expiresIn > 0 {
+		now := time.Now().UTC().Truncate(time.Second)
+		player, err = g.DB.FundLot(entity.Lot{PlayerID: id, Currency: currency, Points: points, Created: now, Expires: now.Add(expiresIn)})
+	} else if _, err = g.DB.GetPlayer(id, currency); err != nil {
+		player, err = g.DB.CreatePlayer(id, currency, points)
+	} else {
+		err = g.DB.UpdatePlayer(id, currency, points)
+	}
+	if err != nil {
+		return player, err
+	}
+	g.publish(balanceChanged(id, currency, points))
+	return player, nil
 }
 
-// Fund controlls funding player
-func (g Game) Fund(id string, points int) (entity.Player, error) {
+// checkFund returns error of funding player with points, which expire after expiresIn
+func (g Game) checkFund(id string, points int, expiresIn time.Duration) error {
 	if points < 0 {
-		return entity.Player{}, errors.Error{Code: errors.NegativePointsNumberError, Message: "fund: cannot fund negative number of points"}
+		return errors.Error{Code: errors.NegativePointsNumberError, Message: "fund: cannot fund negative number of points"}
+	}
+	if expiresIn < 0 {
+		return errors.Error{Code: errors.NegativeTTLError, Message: "fund: points must expire after positive time, id: " + id}
 	}
 	if id == "" {
-		return entity.Player{}, errors.Error{Code: errors.NotFoundError, Message: "fund: id must be not nil"}
+		return errors.Error{Code: errors.NotFoundError, Message: "fund: id must be not nil"}
+	}
+	return g.checkAccount("fund", id)
+}
+
+// Take controlls taking points in currency
+func (g Game) Take(id, currency string, points int) error {
+	err := g.checkTake(id, points, 0)
+	if err != nil {
+		return err
 	}
-	_, err := g.DB.GetPlayer(id)
+	err = g.DB.UpdatePlayer(id, currencyOrDefault(currency), -1*points)
 	if err != nil {
-		return g.DB.CreatePlayer(id, points)
+		return takeError(err)
 	}
-	return entity.Player{}, g.DB.UpdatePlayer(id, points)
+	g.publish(balanceChanged(id, currencyOrDefault(currency), -points))
+	return nil
 }
 
-// Take controlls taking points
-func (g Game) Take(id string, points int) error {
+// checkTake returns error of taking points from player, who is going to spend pending points more
+func (g Game) checkTake(id string, points, pending int) error {
 	if points < 0 {
 		return errors.Error{Code: errors.NegativePointsNumberError, Message: "take: cannot take negative number of points"}
 	}
 	if id == "" {
 		return errors.Error{Code: errors.NotFoundError, Message: "take: id must be not nil"}
 	}
-	err := g.DB.UpdatePlayer(id, -1*points)
+	err := g.checkAccount("take", id)
+	if err != nil {
+		return err
+	}
+	return g.checkSpending("take", id, pending+points)
+}
+
+// takeError returns error of taking points, which database has returned
+func takeError(err error) error {
+	e := errors.Transform(err)
+	if e.Code == errors.NegativePointsNumberError {
+		e.Message = "take: cannot take points, player doesn't have enough points"
+	}
+	return e
+}
+
+// Transfer controlls sending points in currency from one player to another
+func (g Game) Transfer(from, to, currency string, points int) error {
+	if points <= 0 {
+		return errors.Error{Code: errors.NegativePointsNumberError, Message: "transfer: cannot transfer not positive number of points"}
+	}
+	if from == "" || to == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "transfer: id must be not nil"}
+	}
+	if from == to {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "transfer: cannot transfer points to the same player, id: " + from}
+	}
+	for _, id := range []string{from, to} {
+		err := g.checkAccount("transfer", id)
+		if err != nil {
+			return err
+		}
+	}
+	err := g.checkExclusion("transfer", from)
+	if err != nil {
+		return err
+	}
+	currency = currencyOrDefault(currency)
+	err = g.DB.TransferPoints(from, to, currency, points)
 	if err != nil {
 		err := errors.Transform(err)
 		if err.Code == errors.NegativePointsNumberError {
-			err.Message = "take: cannot take points, player doesn't have enough points"
-			return err
+			err.Message = "transfer: cannot transfer points, player doesn't have enough points, id: " + from
 		}
 		return err
 	}
+	g.publish(balanceChanged(from, currency, -points), balanceChanged(to, currency, points))
 	return nil
 }
 
-// Balance controlls getting actual player balance
-func (g Game) Balance(id string) (entity.Player, error) {
+// Balance controlls getting actual player balance in currency, available points do not include held ones
+func (g Game) Balance(id, currency string) (entity.Balance, error) {
 	if id == "" {
-		return entity.Player{}, errors.Error{Code: errors.NotFoundError, Message: "balance: id must be not nil"}
+		return entity.Balance{}, errors.Error{Code: errors.NotFoundError, Message: "balance: id must be not nil"}
+	}
+	currency = currencyOrDefault(currency)
+	p, err := g.DB.GetPlayer(id, currency)
+	if err != nil {
+		return entity.Balance{}, err
+	}
+	holds, err := g.DB.GetHolds(id, currency)
+	if err != nil {
+		return entity.Balance{}, err
+	}
+	lots, err := g.DB.GetExpiringLots(id, currency)
+	if err != nil {
+		return entity.Balance{}, err
 	}
-	return g.DB.GetPlayer(id)
+	b := entity.Balance{ID: p.ID, Points: p.Points, Currency: currency, Available: p.Points, Holds: holds, Expiring: lots}
+	for _, h := range holds {
+		b.Available -= h.Points
+	}
+	return b, nil
 }
 
-// AnnounceTournament controlls announcing tournament
-func (g Game) AnnounceTournament(id string, deposit int) error {
+// AnnounceTournament controlls announcing tournament with deposit in currency,
+// every player can join it up to maxEntries times
+func (g Game) AnnounceTournament(id, currency string, deposit, maxEntries int) error {
 	if deposit <= 0 {
 		return errors.Error{Code: errors.NegativeDepositError, Message: "announce: cannot create tournament with not positive deposite, id: " + id}
 	}
+	if maxEntries <= 0 {
+		return errors.Error{Code: errors.NegativeEntriesError, Message: "announce: cannot create tournament with not positive max entries number, id: " + id}
+	}
 	if id == "" {
 		return errors.Error{Code: errors.NotFoundError, Message: "announce: id must be not nil"}
 	}
-	return g.DB.CreateTournament(id, deposit)
+	return g.DB.CreateTournament(id, currencyOrDefault(currency), deposit, maxEntries)
+}
+
+// AnnounceSatellite controlls announcing satellite tournament, which awards seats in target tournament
+func (g Game) AnnounceSatellite(id string, deposit int, targetID string, seats int) error {
+	if deposit <= 0 {
+		return errors.Error{Code: errors.NegativeDepositError, Message: "announce satellite: cannot create tournament with not positive deposite, id: " + id}
+	}
+	if seats <= 0 {
+		return errors.Error{Code: errors.NegativeSeatsError, Message: "announce satellite: cannot create satellite with not positive number of seats, id: " + id}
+	}
+	if id == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "announce satellite: id must be not nil"}
+	}
+	if targetID == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "announce satellite: target id must be not nil"}
+	}
+	isOpen, err := g.DB.GetTournamentState(targetID)
+	if err != nil {
+		return err
+	}
+	if !isOpen {
+		return errors.Error{Code: errors.ClosedTournamentError, Message: "announce satellite: cannot award seats in closed tournament, targetID: " + targetID}
+	}
+	isTeam, err := g.DB.IsTeamTournament(targetID)
+	if err != nil {
+		return err
+	}
+	if isTeam {
+		return errors.Error{Code: errors.TeamTournamentError, Message: "announce satellite: cannot award seats of players in team tournament, targetID: " + targetID}
+	}
+	return g.DB.CreateSatellite(id, deposit, targetID, seats)
 }
 
 // JoinTournament controlls joining player to tournament
 func (g Game) JoinTournament(tourID, playerID string) error {
+	deposit, err := g.checkJoin(tourID, playerID, 0)
+	if err != nil {
+		return err
+	}
+	err = g.DB.UpdateTourAndPlayer(tourID, playerID)
+	if err != nil {
+		return err
+	}
+	events := []entity.Event{{Type: entity.EventPlayerJoined, TournamentID: tourID, PlayerID: playerID}}
+	if g.Events != nil {
+		if currency, err := g.DB.GetCurrency(tourID); err == nil {
+			paid := balanceChanged(playerID, currency, -deposit)
+			paid.TournamentID = tourID
+			events = append(events, paid)
+		}
+	}
+	g.publish(events...)
+	return nil
+}
+
+// checkJoin returns deposit of tournament and error of joining player, who is going to spend pending points more, into it
+func (g Game) checkJoin(tourID, playerID string, pending int) (int, error) {
 	if tourID == "" {
-		return errors.Error{Code: errors.NotFoundError, Message: "join tournament: tournament id must be not nil"}
+		return 0, errors.Error{Code: errors.NotFoundError, Message: "join tournament: tournament id must be not nil"}
 	}
 	if playerID == "" {
-		return errors.Error{Code: errors.NotFoundError, Message: "join tournament: player id must be not nil"}
+		return 0, errors.Error{Code: errors.NotFoundError, Message: "join tournament: player id must be not nil"}
 	}
 	isOpen, err := g.DB.GetTournamentState(tourID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if !isOpen {
-		return errors.Error{Code: errors.ClosedTournamentError, Message: "join tournament: cannot join to closed tournament, tourID: " + tourID}
+		return 0, errors.Error{Code: errors.ClosedTournamentError, Message: "join tournament: cannot join to closed tournament, tourID: " + tourID}
 	}
-	p, err := g.DB.GetParticipants(tourID)
+	isTeam, err := g.DB.IsTeamTournament(tourID)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if isTeam {
+		return 0, errors.Error{Code: errors.TeamTournamentError, Message: "join tournament: players cannot join team tournament alone, tourID: " + tourID}
+	}
+	entries, err := g.DB.GetEntries(tourID)
+	if err != nil {
+		return 0, err
 	}
-	for i := range p {
-		if p[i] == playerID {
-			return errors.Error{Code: errors.DuplicatedIDError, Message: "join tournament: cannot join to one tournament twice, playerID: " + playerID}
+	maxEntries, err := g.DB.GetMaxEntries(tourID)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for i := range entries {
+		if entries[i].PlayerID == playerID {
+			n++
 		}
 	}
-	return g.DB.UpdateTourAndPlayer(tourID, playerID)
+	if n >= maxEntries {
+		if maxEntries == 1 {
+			return 0, errors.Error{Code: errors.DuplicatedIDError, Message: "join tournament: cannot join to one tournament twice, playerID: " + playerID}
+		}
+		return 0, errors.Error{Code: errors.DuplicatedIDError, Message: "join tournament: player has used all " + strconv.Itoa(maxEntries) + " entries, playerID: " + playerID}
+	}
+	err = g.checkAccount("join tournament", playerID)
+	if err != nil {
+		return 0, err
+	}
+	deposit, err := g.DB.GetDeposit(tourID)
+	if err != nil {
+		return 0, err
+	}
+	err = g.checkSpending("join tournament", playerID, pending+deposit)
+	if err != nil {
+		return 0, err
+	}
+	return deposit, nil
+}
+
+// Tournament returns tournament with its entries, prize, state and winners
+func (g Game) Tournament(id string) (entity.Tournament, error) {
+	if id == "" {
+		return entity.Tournament{}, errors.Error{Code: errors.NotFoundError, Message: "tournament: id must be not nil"}
+	}
+	return g.DB.GetTournament(id)
+}
+
+// History returns every tournament, which player has participated in, with points paid, placing and prize
+func (g Game) History(playerID string) (entity.History, error) {
+	if playerID == "" {
+		return entity.History{}, errors.Error{Code: errors.NotFoundError, Message: "history: player id must be not nil"}
+	}
+	tours, err := g.DB.GetHistory(playerID)
+	if err != nil {
+		return entity.History{}, err
+	}
+	return entity.History{PlayerID: playerID, Tournaments: tours}, nil
 }
 
 // Results controls getting results from tournament
@@ -131,34 +384,140 @@ func (g Game) Results(tourID string) (entity.Winners, error) {
 		return entity.Winners{}, err
 	}
 	if isOpen {
-		err = g.DB.CloseTournament(tourID)
+		err = closeTournament(g, tourID)
 		if err != nil {
 			return entity.Winners{}, err
 		}
-		winner, err := chooseWinner(g, tourID)
-		if err != nil {
-			return entity.Winners{}, err
+	}
+	winners, err := g.DB.GetWinner(tourID)
+	if err != nil {
+		return winners, err
+	}
+	if isOpen {
+		publishResults(g, tourID, winners)
+	}
+	return winners, nil
+}
+
+// closeTournament closes open tournament and sets its winners
+func closeTournament(g Game, tourID string) error {
+	sat, err := g.DB.GetSatellite(tourID)
+	if err != nil {
+		return err
+	}
+	if sat.TargetID != "" {
+		return resultSatellite(g, tourID, sat)
+	}
+	isTeam, err := g.DB.IsTeamTournament(tourID)
+	if err != nil {
+		return err
+	}
+	err = g.DB.CloseTournament(tourID)
+	if err != nil {
+		return err
+	}
+	if isTeam {
+		return resultTeams(g, tourID)
+	}
+	winner, err := chooseWinner(g, tourID)
+	if err != nil {
+		return err
+	}
+	return g.DB.SetTournamentWinner(tourID, winner)
+}
+
+// publishResults publishes closing of tournament, its winners and their prizes
+func publishResults(g Game, tourID string, winners entity.Winners) {
+	if g.Events == nil {
+		return
+	}
+	events := []entity.Event{{Type: entity.EventTournamentClosed, TournamentID: tourID}}
+	for _, w := range winners.Winners {
+		events = append(events, entity.Event{Type: entity.EventWinnerChosen, TournamentID: tourID, PlayerID: w.ID, TeamID: w.Team, Points: w.Prize})
+	}
+	currency, err := g.DB.GetCurrency(tourID)
+	if err == nil {
+		for _, w := range winners.Winners {
+			if w.Prize > 0 {
+				prize := balanceChanged(w.ID, currency, w.Prize)
+				prize.TournamentID = tourID
+				events = append(events, prize)
+			}
 		}
-		err = g.DB.SetTournamentWinner(tourID, winner)
-		if err != nil {
-			return entity.Winners{}, err
+	}
+	g.publish(events...)
+}
+
+// resultSatellite closes satellite and registers its best placed players into target tournament.
+// Players are placed by their best entry, players, who have already joined target tournament,
+// are placed after the others and cannot get a seat.
+func resultSatellite(g Game, tourID string, sat entity.Satellite) error {
+	isOpen, err := g.DB.GetTournamentState(sat.TargetID)
+	if err != nil {
+		return err
+	}
+	if !isOpen {
+		return errors.Error{Code: errors.ClosedTournamentError, Message: "results: cannot award seats in closed tournament, targetID: " + sat.TargetID}
+	}
+	err = g.DB.CloseTournament(tourID)
+	if err != nil {
+		return err
+	}
+	entries, err := g.DB.GetEntries(tourID)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return errors.Error{Code: errors.NoneParticipantsError, Message: "cannot choose winner: tournament has no participants, id: " + tourID}
+	}
+	registered, err := g.DB.GetParticipants(sat.TargetID)
+	if err != nil {
+		return err
+	}
+	placed := make(map[string]bool, len(entries))
+	inTarget := make(map[string]bool, len(registered))
+	for _, id := range registered {
+		inTarget[id] = true
+	}
+	rand.Seed(time.Now().UnixNano())
+	var ranking, rest []string
+	for _, i := range rand.Perm(len(entries)) {
+		id := entries[i].PlayerID
+		if placed[id] {
+			continue
+		}
+		placed[id] = true
+		if inTarget[id] {
+			rest = append(rest, id)
+			continue
 		}
+		ranking = append(ranking, id)
 	}
-	return g.DB.GetWinner(tourID)
+	seats := sat.Seats
+	if seats > len(ranking) {
+		seats = len(ranking)
+	}
+	return g.DB.SetSatelliteWinners(tourID, append(ranking, rest...), seats)
 }
 
+// chooseWinner chooses winning entry, so player with several entries has more chances to win
 func chooseWinner(g Game, tourID string) (entity.Winner, error) {
-	p, err := g.DB.GetParticipants(tourID)
+	entries, err := g.DB.GetEntries(tourID)
 	if err != nil {
 		return entity.Winner{}, err
 	}
-	if len(p) == 0 {
+	if len(entries) == 0 {
 		return entity.Winner{}, errors.Error{Code: errors.NoneParticipantsError, Message: "cannot choose winner: tournament has no participants, id: " + tourID}
 	}
+	currency, err := g.DB.GetCurrency(tourID)
+	if err != nil {
+		return entity.Winner{}, err
+	}
 	rand.Seed(time.Now().UnixNano())
-	win, err := g.DB.GetPlayer(p[rand.Intn(len(p))])
+	e := entries[rand.Intn(len(entries))]
+	win, err := g.DB.GetPlayer(e.PlayerID, currency)
 	if err != nil {
 		return entity.Winner{}, err
 	}
-	return entity.Winner{ID: win.ID, Points: win.Points}, nil
+	return entity.Winner{ID: win.ID, Points: win.Points, Entry: e.Number}, nil
 }
@@ -2,9 +2,13 @@ package controller
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
@@ -18,6 +22,12 @@ var (
 func TestMain(m *testing.M) {
 	db = &MockDatabase{}
 	g.DB = db
+	// only players of limits tests have responsible play limits
+	db.On("GetLimits", mock.MatchedBy(func(id string) bool { return !strings.HasPrefix(id, "limits_") })).
+		Return(entity.Limits{}, errors.Error{Code: errors.NotFoundError})
+	// players of other than accounts tests are registered and active
+	db.On("GetAccount", mock.MatchedBy(func(id string) bool { return !strings.HasPrefix(id, "accounts_") })).
+		Return(entity.Account{Status: entity.StatusActive}, nil)
 	code := m.Run()
 	os.Exit(code)
 }
@@ -28,16 +38,25 @@ func TestController_Fund(t *testing.T) {
 		{ID: "fund_not_found", Points: 100},
 		{ID: "fund_negative_points", Points: -100},
 	}
-	db.On("GetPlayer", players[0].ID).Return(players[0], nil)
-	db.On("GetPlayer", players[1].ID).Return(entity.Player{}, errors.Error{Code: errors.NotFoundError})
+	db.On("GetPlayer", players[0].ID, entity.DefaultCurrency).Return(players[0], nil)
+	db.On("GetPlayer", players[1].ID, entity.DefaultCurrency).Return(entity.Player{}, errors.Error{Code: errors.NotFoundError})
 
-	db.On("UpdatePlayer", players[0].ID, players[0].Points).Return(nil)
+	db.On("UpdatePlayer", players[0].ID, entity.DefaultCurrency, players[0].Points).Return(nil)
 
-	db.On("CreatePlayer", players[1].ID, players[0].Points).Return(players[1], nil)
+	db.On("CreatePlayer", players[1].ID, entity.DefaultCurrency, players[0].Points).Return(players[1], nil)
+
+	db.On("GetPlayer", players[0].ID, "coins").Return(entity.Player{}, errors.Error{Code: errors.NotFoundError})
+	db.On("FundLot", mock.MatchedBy(func(l entity.Lot) bool {
+		return l.PlayerID == players[0].ID && l.Expires.Sub(l.Created) == 24*time.Hour
+	})).
+		Return(entity.Player{ID: players[0].ID, Points: 2 * players[0].Points, Currency: entity.DefaultCurrency}, nil)
+	db.On("CreatePlayer", players[0].ID, "coins", players[0].Points).Return(entity.Player{ID: players[0].ID, Points: players[0].Points, Currency: "coins"}, nil)
 	tt := []struct {
 		name           string
 		playerID       string
+		currency       string
 		fund           int
+		expiresIn      time.Duration
 		expectedPlayer entity.Player
 		expectedError  error
 	}{
@@ -55,6 +74,30 @@ func TestController_Fund(t *testing.T) {
 			expectedPlayer: players[1],
 			expectedError:  nil,
 		},
+		{
+			name:           "fund: create currency balance",
+			playerID:       players[0].ID,
+			currency:       "coins",
+			fund:           players[0].Points,
+			expectedPlayer: entity.Player{ID: players[0].ID, Points: players[0].Points, Currency: "coins"},
+			expectedError:  nil,
+		},
+		{
+			name:           "fund: expiring points",
+			playerID:       players[0].ID,
+			fund:           players[0].Points,
+			expiresIn:      24 * time.Hour,
+			expectedPlayer: entity.Player{ID: players[0].ID, Points: 2 * players[0].Points, Currency: entity.DefaultCurrency},
+			expectedError:  nil,
+		},
+		{
+			name:           "fund: negative expiry",
+			playerID:       players[0].ID,
+			fund:           players[0].Points,
+			expiresIn:      -time.Hour,
+			expectedPlayer: entity.Player{},
+			expectedError:  errors.Error{Code: errors.NegativeTTLError, Message: "fund: points must expire after positive time, id: " + players[0].ID},
+		},
 		{
 			name:           "fund: negative points number",
 			playerID:       players[2].ID,
@@ -72,7 +115,7 @@ func TestController_Fund(t *testing.T) {
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			p, err := g.Fund(tc.playerID, tc.fund)
+			p, err := g.Fund(tc.playerID, tc.currency, tc.fund, tc.expiresIn)
 			assert.Equal(t, tc.expectedPlayer, p)
 			assert.Equal(t, tc.expectedError, err)
 		})
@@ -85,9 +128,9 @@ func TestController_Take(t *testing.T) {
 		{ID: "take_negative_points", Points: -100},
 		{ID: "take_not_found", Points: 0},
 	}
-	db.On("UpdatePlayer", players[0].ID, -1*players[0].Points).Return(nil)
-	db.On("UpdatePlayer", players[1].ID, 2*players[1].Points).Return(errors.Error{Code: errors.NegativePointsNumberError})
-	db.On("UpdatePlayer", players[2].ID, -1*players[2].Points).Return(errors.Error{Code: errors.NotFoundError})
+	db.On("UpdatePlayer", players[0].ID, entity.DefaultCurrency, -1*players[0].Points).Return(nil)
+	db.On("UpdatePlayer", players[1].ID, entity.DefaultCurrency, 2*players[1].Points).Return(errors.Error{Code: errors.NegativePointsNumberError})
+	db.On("UpdatePlayer", players[2].ID, entity.DefaultCurrency, -1*players[2].Points).Return(errors.Error{Code: errors.NotFoundError})
 	tt := []struct {
 		name          string
 		playerID      string
@@ -127,7 +170,74 @@ func TestController_Take(t *testing.T) {
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			err := g.Take(tc.playerID, tc.take)
+			err := g.Take(tc.playerID, "", tc.take)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestController_Transfer(t *testing.T) {
+	players := []entity.Player{
+		{ID: "transfer_1", Points: 100},
+		{ID: "transfer_2", Points: 100},
+		{ID: "transfer_not_found", Points: 0},
+	}
+	db.On("TransferPoints", players[0].ID, players[1].ID, entity.DefaultCurrency, 50).Return(nil)
+	db.On("TransferPoints", players[0].ID, players[1].ID, entity.DefaultCurrency, 500).Return(errors.Error{Code: errors.NegativePointsNumberError})
+	db.On("TransferPoints", players[0].ID, players[2].ID, entity.DefaultCurrency, 50).Return(errors.Error{Code: errors.NotFoundError})
+	tt := []struct {
+		name          string
+		from          string
+		to            string
+		points        int
+		expectedError error
+	}{
+		{
+			name:          "transfer: ok",
+			from:          players[0].ID,
+			to:            players[1].ID,
+			points:        50,
+			expectedError: nil,
+		},
+		{
+			name:          "transfer: not enough points",
+			from:          players[0].ID,
+			to:            players[1].ID,
+			points:        500,
+			expectedError: errors.Error{Code: errors.NegativePointsNumberError, Message: "transfer: cannot transfer points, player doesn't have enough points, id: " + players[0].ID},
+		},
+		{
+			name:          "transfer: not positive points",
+			from:          players[0].ID,
+			to:            players[1].ID,
+			points:        0,
+			expectedError: errors.Error{Code: errors.NegativePointsNumberError, Message: "transfer: cannot transfer not positive number of points"},
+		},
+		{
+			name:          "transfer: empty id",
+			from:          players[0].ID,
+			to:            "",
+			points:        50,
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "transfer: id must be not nil"},
+		},
+		{
+			name:          "transfer: same player",
+			from:          players[0].ID,
+			to:            players[0].ID,
+			points:        50,
+			expectedError: errors.Error{Code: errors.DuplicatedIDError, Message: "transfer: cannot transfer points to the same player, id: " + players[0].ID},
+		},
+		{
+			name:          "transfer: not existing player",
+			from:          players[0].ID,
+			to:            players[2].ID,
+			points:        50,
+			expectedError: errors.Error{Code: errors.NotFoundError},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := g.Transfer(tc.from, tc.to, "", tc.points)
 			assert.Equal(t, tc.expectedError, err)
 		})
 	}
@@ -136,70 +246,623 @@ func TestController_Take(t *testing.T) {
 func TestController_Balance(t *testing.T) {
 	players := []entity.Player{
 		{ID: "balance_id", Points: 100},
+		{ID: "balance_held", Points: 100},
 	}
-	db.On("GetPlayer", players[0].ID).Return(players[0], nil)
+	holds := []entity.Hold{
+		{ID: "balance_hold", PlayerID: players[1].ID, Points: 30},
+	}
+	lots := []entity.Lot{
+		{ID: "1", PlayerID: players[1].ID, Points: 50, Expires: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	db.On("GetPlayer", players[0].ID, entity.DefaultCurrency).Return(players[0], nil)
+	db.On("GetHolds", players[0].ID, entity.DefaultCurrency).Return(nil, nil)
+	db.On("GetPlayer", players[1].ID, entity.DefaultCurrency).Return(players[1], nil)
+	db.On("GetHolds", players[1].ID, entity.DefaultCurrency).Return(holds, nil)
+	db.On("GetExpiringLots", players[0].ID, entity.DefaultCurrency).Return(nil, nil)
+	db.On("GetExpiringLots", players[1].ID, entity.DefaultCurrency).Return(lots, nil)
 	tt := []struct {
-		name           string
-		playerID       string
-		expectedPlayer entity.Player
-		expectedError  error
+		name            string
+		playerID        string
+		expectedBalance entity.Balance
+		expectedError   error
 	}{
 		{
-			name:           "balance: ok",
-			playerID:       players[0].ID,
-			expectedPlayer: players[0],
-			expectedError:  nil,
+			name:            "balance: ok",
+			playerID:        players[0].ID,
+			expectedBalance: entity.Balance{ID: players[0].ID, Points: 100, Currency: entity.DefaultCurrency, Available: 100},
+			expectedError:   nil,
 		},
 		{
-			name:           "balance: empty id",
-			playerID:       "",
-			expectedPlayer: entity.Player{},
-			expectedError:  errors.Error{Code: errors.NotFoundError, Message: "balance: id must be not nil"},
+			name:            "balance: held points",
+			playerID:        players[1].ID,
+			expectedBalance: entity.Balance{ID: players[1].ID, Points: 100, Currency: entity.DefaultCurrency, Available: 70, Holds: holds, Expiring: lots},
+			expectedError:   nil,
+		},
+		{
+			name:            "balance: empty id",
+			playerID:        "",
+			expectedBalance: entity.Balance{},
+			expectedError:   errors.Error{Code: errors.NotFoundError, Message: "balance: id must be not nil"},
 		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			p, err := g.Balance(tc.playerID)
-			assert.Equal(t, tc.expectedPlayer, p)
+			b, err := g.Balance(tc.playerID, "")
+			assert.Equal(t, tc.expectedBalance, b)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestController_Hold(t *testing.T) {
+	db.On("CreateHold", mock.MatchedBy(func(h entity.Hold) bool { return h.ID == "hold_ok" })).Return(nil)
+	db.On("CreateHold", mock.MatchedBy(func(h entity.Hold) bool { return h.ID == "hold_not_enough" })).Return(errors.Error{Code: errors.NegativePointsNumberError})
+	tt := []struct {
+		name          string
+		id            string
+		playerID      string
+		points        int
+		ttl           time.Duration
+		expectedError error
+	}{
+		{
+			name:          "hold: ok",
+			id:            "hold_ok",
+			playerID:      "hold_player",
+			points:        50,
+			ttl:           time.Minute,
+			expectedError: nil,
+		},
+		{
+			name:          "hold: not enough points",
+			id:            "hold_not_enough",
+			playerID:      "hold_player",
+			points:        500,
+			ttl:           time.Minute,
+			expectedError: errors.Error{Code: errors.NegativePointsNumberError, Message: "hold: cannot hold points, player doesn't have enough available points"},
+		},
+		{
+			name:          "hold: not positive points",
+			id:            "hold_ok",
+			playerID:      "hold_player",
+			points:        0,
+			ttl:           time.Minute,
+			expectedError: errors.Error{Code: errors.NegativePointsNumberError, Message: "hold: cannot hold not positive number of points"},
+		},
+		{
+			name:          "hold: not positive ttl",
+			id:            "hold_ok",
+			playerID:      "hold_player",
+			points:        50,
+			ttl:           0,
+			expectedError: errors.Error{Code: errors.NegativeTTLError, Message: "hold: hold must have positive ttl, id: hold_ok"},
+		},
+		{
+			name:          "hold: empty player id",
+			id:            "hold_ok",
+			playerID:      "",
+			points:        50,
+			ttl:           time.Minute,
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "hold: player id must be not nil"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := g.Hold(tc.id, tc.playerID, "", tc.points, tc.ttl)
+			assert.Equal(t, tc.expectedError, err)
+			if err == nil {
+				assert.Equal(t, tc.points, h.Points)
+				assert.True(t, h.Expires.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestController_CaptureRelease(t *testing.T) {
+	db.On("CaptureHold", "capture_ok").Return(entity.Hold{ID: "capture_ok", PlayerID: "capture_player", Points: 50, Currency: entity.DefaultCurrency}, nil)
+	db.On("CaptureHold", "capture_expired").Return(entity.Hold{}, errors.Error{Code: errors.NotFoundError})
+	db.On("ReleaseHold", "release_ok").Return(nil)
+	assert.Nil(t, g.Capture("capture_ok"))
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError}, g.Capture("capture_expired"))
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "capture: id must be not nil"}, g.Capture(""))
+	assert.Nil(t, g.Release("release_ok"))
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "release: id must be not nil"}, g.Release(""))
+}
+
+func TestController_CreatePromo(t *testing.T) {
+	db.On("CreatePromo", mock.MatchedBy(func(p entity.Promo) bool { return p.Code == "promo_ok" })).Return(nil)
+	db.On("CreatePromo", mock.MatchedBy(func(p entity.Promo) bool { return p.Code == "promo_ticket" })).Return(nil)
+	db.On("IsTeamTournament", "promo_tour").Return(false, nil)
+	db.On("IsTeamTournament", "promo_team_tour").Return(true, nil)
+	validFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tt := []struct {
+		name          string
+		promo         entity.Promo
+		expectedError error
+	}{
+		{
+			name:          "create promo: ok",
+			promo:         entity.Promo{Code: "promo_ok", Points: 100},
+			expectedError: nil,
+		},
+		{
+			name:          "create promo: ticket",
+			promo:         entity.Promo{Code: "promo_ticket", TournamentID: "promo_tour", MaxUses: 10},
+			expectedError: nil,
+		},
+		{
+			name:          "create promo: empty code",
+			promo:         entity.Promo{Points: 100},
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "create promo: code must be not nil"},
+		},
+		{
+			name:          "create promo: negative points",
+			promo:         entity.Promo{Code: "promo_ok", Points: -100},
+			expectedError: errors.Error{Code: errors.NegativePointsNumberError, Message: "create promo: cannot grant negative number of points, code: promo_ok"},
+		},
+		{
+			name:          "create promo: no reward",
+			promo:         entity.Promo{Code: "promo_ok"},
+			expectedError: errors.Error{Code: errors.InvalidPromoError, Message: "create promo: promo must grant points or ticket, code: promo_ok"},
+		},
+		{
+			name:          "create promo: negative limit",
+			promo:         entity.Promo{Code: "promo_ok", Points: 100, MaxUses: -1},
+			expectedError: errors.Error{Code: errors.InvalidPromoError, Message: "create promo: usage limits must be not negative, code: promo_ok"},
+		},
+		{
+			name:          "create promo: invalid window",
+			promo:         entity.Promo{Code: "promo_ok", Points: 100, ValidFrom: validFrom, ValidTo: validFrom},
+			expectedError: errors.Error{Code: errors.InvalidPromoError, Message: "create promo: promo must be valid to time after valid from, code: promo_ok"},
+		},
+		{
+			name:          "create promo: team tournament ticket",
+			promo:         entity.Promo{Code: "promo_ok", TournamentID: "promo_team_tour"},
+			expectedError: errors.Error{Code: errors.TeamTournamentError, Message: "create promo: cannot grant ticket into team tournament, id: promo_team_tour"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := g.CreatePromo(tc.promo)
 			assert.Equal(t, tc.expectedError, err)
+			if err == nil {
+				assert.Equal(t, 1, p.MaxPerPlayer)
+				assert.Equal(t, entity.DefaultCurrency, p.Currency)
+				assert.False(t, p.ValidFrom.IsZero())
+			}
+		})
+	}
+}
+
+func TestController_Redeem(t *testing.T) {
+	red := entity.Redemption{Code: "redeem_ok", PlayerID: "redeem_player", Use: 1, Points: 100, Currency: entity.DefaultCurrency}
+	db.On("RedeemPromo", "redeem_ok", "redeem_player", mock.AnythingOfType("time.Time")).Return(red, nil)
+	db.On("RedeemPromo", "redeem_expired", "redeem_player", mock.AnythingOfType("time.Time")).Return(entity.Redemption{}, errors.Error{Code: errors.PromoUnavailableError})
+	r, err := g.Redeem("redeem_ok", "redeem_player")
+	assert.Nil(t, err)
+	assert.Equal(t, red, r)
+	_, err = g.Redeem("redeem_expired", "redeem_player")
+	assert.Equal(t, errors.Error{Code: errors.PromoUnavailableError}, err)
+	_, err = g.Redeem("", "redeem_player")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "redeem: code must be not nil"}, err)
+	_, err = g.Redeem("redeem_ok", "")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "redeem: player id must be not nil"}, err)
+	db.On("GetAccount", "accounts_redeem").Return(entity.Account{ID: "accounts_redeem", Status: entity.StatusSuspended}, nil)
+	_, err = g.Redeem("redeem_ok", "accounts_redeem")
+	assert.Equal(t, errors.Error{Code: errors.InactiveAccountError, Message: "redeem: account is suspended, id: accounts_redeem"}, err)
+}
+
+func TestController_Limits(t *testing.T) {
+	excludedUntil := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	db.On("GetLimits", "limits_new").Return(entity.Limits{}, errors.Error{Code: errors.NotFoundError})
+	db.On("GetLimits", "limits_set").Return(entity.Limits{PlayerID: "limits_set", Daily: 100, Weekly: 500}, nil)
+	db.On("GetLimits", "limits_pending").Return(entity.Limits{PlayerID: "limits_pending", Daily: 100, Pending: &entity.PendingLimits{Daily: 200, Applies: time.Now().Add(-time.Minute)}}, nil)
+	db.On("GetLimits", "limits_excluded").Return(entity.Limits{PlayerID: "limits_excluded", ExcludedUntil: excludedUntil}, nil)
+	db.On("SetLimits", mock.AnythingOfType("entity.Limits")).Return(nil)
+
+	limits, err := g.SetLimits("limits_new", 100, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Limits{PlayerID: "limits_new", Daily: 100}, limits)
+
+	// lowered daily limit is set at once, raised weekly one waits for cooling-off period
+	limits, err = g.SetLimits("limits_set", 50, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, limits.Daily)
+	assert.Equal(t, 500, limits.Weekly)
+	require.NotNil(t, limits.Pending)
+	assert.Equal(t, 1000, limits.Pending.Weekly)
+	assert.True(t, limits.Pending.Applies.After(time.Now().Add(limitsCoolingOff-time.Minute)))
+
+	limits, err = g.SetLimits("limits_set", 0, 500)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, limits.Daily)
+	require.NotNil(t, limits.Pending)
+	assert.Equal(t, 0, limits.Pending.Daily)
+
+	limits, err = g.Limits("limits_pending")
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Limits{PlayerID: "limits_pending", Daily: 200}, limits)
+
+	_, err = g.SetLimits("limits_new", -1, 0)
+	assert.Equal(t, errors.Error{Code: errors.NegativePointsNumberError, Message: "set limits: limits must be not negative, id: limits_new"}, err)
+	_, err = g.SetLimits("", 100, 0)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "set limits: player id must be not nil"}, err)
+
+	// exclusion cannot be shortened
+	limits, err = g.SelfExclude("limits_excluded", 24*time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, excludedUntil, limits.ExcludedUntil)
+	limits, err = g.SelfExclude("limits_new", 24*time.Hour)
+	assert.Nil(t, err)
+	assert.True(t, limits.ExcludedUntil.After(time.Now()))
+	_, err = g.SelfExclude("limits_new", 0)
+	assert.Equal(t, errors.Error{Code: errors.NegativeTTLError, Message: "self exclude: exclusion must have positive period, id: limits_new"}, err)
+}
+
+func TestController_SpendingLimits(t *testing.T) {
+	db.On("GetLimits", "limits_daily").Return(entity.Limits{PlayerID: "limits_daily", Daily: 100, Weekly: 1000}, nil)
+	db.On("GetLimits", "limits_excluded_player").Return(entity.Limits{PlayerID: "limits_excluded_player", ExcludedUntil: time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	db.On("GetSpent", "limits_daily", mock.AnythingOfType("time.Time")).Return(80, nil)
+	db.On("UpdatePlayer", "limits_daily", entity.DefaultCurrency, -20).Return(nil)
+	db.On("GetTournamentState", "limits_tournament").Return(true, nil)
+	db.On("IsTeamTournament", "limits_tournament").Return(false, nil)
+	db.On("GetEntries", "limits_tournament").Return(nil, nil)
+	db.On("GetMaxEntries", "limits_tournament").Return(1, nil)
+	db.On("GetDeposit", "limits_tournament").Return(50, nil)
+	// captain of team has no limits, other member has daily limit
+	team := entity.Team{ID: "limits_team", Captain: "limits_captain", Members: []entity.TeamMember{{PlayerID: "limits_captain", Share: 40}, {PlayerID: "limits_daily", Share: 60}}}
+	db.On("GetLimits", "limits_captain").Return(entity.Limits{}, errors.Error{Code: errors.NotFoundError})
+	db.On("GetTournamentState", "limits_team_tournament").Return(true, nil)
+	db.On("IsTeamTournament", "limits_team_tournament").Return(true, nil)
+	db.On("GetDeposit", "limits_team_tournament").Return(50, nil)
+	db.On("GetTeam", team.ID).Return(team, nil)
+	db.On("GetTeams", "limits_team_tournament").Return(nil, nil)
+	db.On("UpdateTourAndTeam", "limits_team_tournament", team.ID, true).Return(nil)
+	tt := []struct {
+		name          string
+		spend         func() error
+		expectedError error
+	}{
+		{
+			name:          "take: within limits",
+			spend:         func() error { return g.Take("limits_daily", "", 20) },
+			expectedError: nil,
+		},
+		{
+			name:          "take: daily limit exceeded",
+			spend:         func() error { return g.Take("limits_daily", "", 30) },
+			expectedError: errors.Error{Code: errors.LimitExceededError, Message: "take: player would exceed daily limit of 100 points, spent: 80, id: limits_daily"},
+		},
+		{
+			name:          "join tournament: daily limit exceeded",
+			spend:         func() error { return g.JoinTournament("limits_tournament", "limits_daily") },
+			expectedError: errors.Error{Code: errors.LimitExceededError, Message: "join tournament: player would exceed daily limit of 100 points, spent: 80, id: limits_daily"},
+		},
+		{
+			name:          "join team: member daily limit exceeded",
+			spend:         func() error { return g.JoinTeam("limits_team_tournament", team.ID, false) },
+			expectedError: errors.Error{Code: errors.LimitExceededError, Message: "join team: player would exceed daily limit of 100 points, spent: 80, id: limits_daily"},
+		},
+		{
+			name:          "join team: captain pays",
+			spend:         func() error { return g.JoinTeam("limits_team_tournament", team.ID, true) },
+			expectedError: nil,
+		},
+		{
+			name:          "take: self-excluded",
+			spend:         func() error { return g.Take("limits_excluded_player", "", 20) },
+			expectedError: errors.Error{Code: errors.SelfExcludedError, Message: "take: player is self-excluded until 2100-01-01T00:00:00Z, id: limits_excluded_player"},
+		},
+		{
+			name:          "transfer: self-excluded",
+			spend:         func() error { return g.Transfer("limits_excluded_player", "limits_daily", "", 20) },
+			expectedError: errors.Error{Code: errors.SelfExcludedError, Message: "transfer: player is self-excluded until 2100-01-01T00:00:00Z, id: limits_excluded_player"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedError, tc.spend())
 		})
 	}
 }
 
+func TestController_Accounts(t *testing.T) {
+	db.On("CreateAccount", mock.MatchedBy(func(a entity.Account) bool { return a.ID == "accounts_new" })).Return(nil)
+	db.On("CreateAccount", mock.MatchedBy(func(a entity.Account) bool { return a.ID == "accounts_active" })).Return(errors.Error{Code: errors.DuplicatedIDError})
+	db.On("GetAccount", "accounts_active").Return(entity.Account{ID: "accounts_active", Name: "Active", Status: entity.StatusActive}, nil)
+	db.On("GetAccount", "accounts_suspended").Return(entity.Account{ID: "accounts_suspended", Name: "Suspended", Status: entity.StatusSuspended}, nil)
+	db.On("GetAccount", "accounts_closed").Return(entity.Account{ID: "accounts_closed", Name: "Closed", Status: entity.StatusClosed}, nil)
+	db.On("SetAccountStatus", "accounts_active", entity.StatusSuspended).Return(nil)
+	db.On("SetAccountStatus", "accounts_suspended", entity.StatusActive).Return(nil)
+
+	account, err := g.Register("accounts_new", "New", map[string]string{"country": "DE"})
+	assert.Nil(t, err)
+	assert.Equal(t, entity.StatusActive, account.Status)
+	assert.Equal(t, map[string]string{"country": "DE"}, account.Metadata)
+	assert.False(t, account.Created.IsZero())
+	_, err = g.Register("accounts_active", "Active", nil)
+	assert.Equal(t, errors.Error{Code: errors.DuplicatedIDError}, err)
+	_, err = g.Register("accounts_new", "", nil)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "register: name must be not nil, id: accounts_new"}, err)
+
+	account, err = g.Suspend("accounts_active")
+	assert.Nil(t, err)
+	assert.Equal(t, entity.StatusSuspended, account.Status)
+	account, err = g.Reinstate("accounts_suspended")
+	assert.Nil(t, err)
+	assert.Equal(t, entity.StatusActive, account.Status)
+	_, err = g.Reinstate("accounts_closed")
+	assert.Equal(t, errors.Error{Code: errors.InactiveAccountError, Message: "reinstate: account is closed, id: accounts_closed"}, err)
+	_, err = g.Suspend("")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "suspend: id must be not nil"}, err)
+
+	_, err = g.Fund("accounts_suspended", "", 100, 0)
+	assert.Equal(t, errors.Error{Code: errors.InactiveAccountError, Message: "fund: account is suspended, id: accounts_suspended"}, err)
+	err = g.Take("accounts_closed", "", 100)
+	assert.Equal(t, errors.Error{Code: errors.InactiveAccountError, Message: "take: account is closed, id: accounts_closed"}, err)
+	db.On("GetTournamentState", "accounts_tournament").Return(true, nil)
+	db.On("IsTeamTournament", "accounts_tournament").Return(false, nil)
+	db.On("GetEntries", "accounts_tournament").Return(nil, nil)
+	db.On("GetMaxEntries", "accounts_tournament").Return(1, nil)
+	err = g.JoinTournament("accounts_tournament", "accounts_suspended")
+	assert.Equal(t, errors.Error{Code: errors.InactiveAccountError, Message: "join tournament: account is suspended, id: accounts_suspended"}, err)
+
+	// players must be registered, so suspended player cannot use fresh id
+	db.On("GetAccount", "accounts_unregistered").Return(entity.Account{}, errors.Error{Code: errors.NotFoundError, Message: "get account: cannot find account, id accounts_unregistered"})
+	_, err = g.Fund("accounts_unregistered", "", 100, 0)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "fund: player is not registered, id: accounts_unregistered"}, err)
+	err = g.Take("accounts_unregistered", "", 100)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "take: player is not registered, id: accounts_unregistered"}, err)
+	err = g.JoinTournament("accounts_tournament", "accounts_unregistered")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "join tournament: player is not registered, id: accounts_unregistered"}, err)
+}
+
+func TestController_CloseAccount(t *testing.T) {
+	closure := entity.Closure{PlayerID: "accounts_active", Withdrawn: []string{"close_tournament"}, Payouts: []entity.Player{{ID: "accounts_active", Points: 150, Currency: entity.DefaultCurrency}}}
+	db.On("CloseAccount", "accounts_active", true).Return(closure, nil)
+	db.On("CloseAccount", "accounts_active", false).Return(entity.Closure{}, errors.Error{Code: errors.OpenEntriesError})
+	db.On("CloseAccount", "close_unregistered", false).Return(entity.Closure{PlayerID: "close_unregistered", Payouts: []entity.Player{}}, nil)
+	got, err := g.CloseAccount("accounts_active", true)
+	assert.Nil(t, err)
+	assert.Equal(t, closure, got)
+	_, err = g.CloseAccount("accounts_active", false)
+	assert.Equal(t, errors.Error{Code: errors.OpenEntriesError}, err)
+	_, err = g.CloseAccount("close_unregistered", false)
+	assert.Nil(t, err)
+	_, err = g.CloseAccount("accounts_closed", true)
+	assert.Equal(t, errors.Error{Code: errors.InactiveAccountError, Message: "close account: account is closed, id: accounts_closed"}, err)
+	_, err = g.CloseAccount("", true)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "close account: id must be not nil"}, err)
+}
+
+func TestController_ListPlayers(t *testing.T) {
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	accounts := []entity.Account{
+		{ID: "list_1", Name: "A", Created: created, Status: entity.StatusActive},
+		{ID: "list_2", Name: "B", Created: created, Status: entity.StatusActive},
+		{ID: "list_3", Name: "C", Created: created, Status: entity.StatusActive},
+	}
+	db.On("ListAccounts", entity.PlayerFilter{Status: entity.StatusActive, Sort: "created", Limit: 3}).Return(accounts, nil)
+	after := &entity.Cursor{Value: created.Format(time.RFC3339Nano), ID: "list_2"}
+	db.On("ListAccounts", entity.PlayerFilter{Status: entity.StatusActive, Sort: "created", After: after, Limit: 3}).Return(accounts[2:], nil)
+
+	page, err := g.ListPlayers(entity.PlayerFilter{Status: entity.StatusActive, Sort: "created", Limit: 2}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, accounts[:2], page.Players)
+	require.NotEmpty(t, page.Next)
+	page, err = g.ListPlayers(entity.PlayerFilter{Status: entity.StatusActive, Sort: "created", Limit: 2}, page.Next)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.PlayerPage{Players: accounts[2:]}, page)
+
+	_, err = g.ListPlayers(entity.PlayerFilter{Sort: "points"}, "")
+	assert.Equal(t, errors.Error{Code: errors.InvalidFilterError, Message: "list players: cannot sort players by points"}, err)
+	_, err = g.ListPlayers(entity.PlayerFilter{Status: "deleted"}, "")
+	assert.Equal(t, errors.Error{Code: errors.InvalidFilterError, Message: "list players: unknown status deleted"}, err)
+	_, err = g.ListPlayers(entity.PlayerFilter{}, "not_cursor")
+	assert.Equal(t, errors.Error{Code: errors.InvalidFilterError, Message: "list players: invalid cursor not_cursor"}, err)
+	cursor := encodeCursor(entity.Cursor{Value: "list_1", ID: "list_1"})
+	_, err = g.ListPlayers(entity.PlayerFilter{Sort: "created"}, cursor)
+	assert.Equal(t, errors.Error{Code: errors.InvalidFilterError, Message: "list players: invalid cursor " + cursor}, err)
+}
+
+func TestController_ListTournaments(t *testing.T) {
+	tours := []entity.Tournament{{ID: "list_tour_1", Deposit: 100}, {ID: "list_tour_2", Deposit: 200}}
+	db.On("ListTournaments", entity.TourFilter{Status: "open", MinDeposit: 100, ParticipantID: "list_player", Sort: "deposit", Desc: true, Limit: defaultPageLimit + 1}).Return(tours, nil)
+	db.On("ListTournaments", entity.TourFilter{Sort: "deposit", After: &entity.Cursor{Value: "100", ID: "list_tour_1"}, Limit: 2}).Return(tours[1:], nil)
+	db.On("ListTournaments", entity.TourFilter{Sort: "deposit", Limit: 2}).Return(tours, nil)
+
+	page, err := g.ListTournaments(entity.TourFilter{Status: "open", MinDeposit: 100, ParticipantID: "list_player", Sort: "deposit", Desc: true}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, entity.TourPage{Tournaments: tours}, page)
+	page, err = g.ListTournaments(entity.TourFilter{Sort: "deposit", Limit: 1}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, tours[:1], page.Tournaments)
+	page, err = g.ListTournaments(entity.TourFilter{Sort: "deposit", Limit: 1}, page.Next)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.TourPage{Tournaments: tours[1:]}, page)
+
+	_, err = g.ListTournaments(entity.TourFilter{MinDeposit: 200, MaxDeposit: 100}, "")
+	assert.Equal(t, errors.Error{Code: errors.InvalidFilterError, Message: "list tournaments: invalid deposit range 200-100"}, err)
+	_, err = g.ListTournaments(entity.TourFilter{Limit: -1}, "")
+	assert.Equal(t, errors.Error{Code: errors.InvalidFilterError, Message: "list tournaments: limit must be not negative"}, err)
+	cursor := encodeCursor(entity.Cursor{Value: "deposit", ID: "list_tour_1"})
+	_, err = g.ListTournaments(entity.TourFilter{Sort: "deposit"}, cursor)
+	assert.Equal(t, errors.Error{Code: errors.InvalidFilterError, Message: "list tournaments: invalid cursor " + cursor}, err)
+}
+
+func TestController_Tournament(t *testing.T) {
+	closed := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	tour := entity.Tournament{ID: "details_tour", Deposit: 100, Prize: 200, Participants: []string{"details_player"},
+		Entries: []entity.Entry{{PlayerID: "details_player", Number: 1}, {PlayerID: "details_player", Number: 2}},
+		Winner:  entity.Winner{ID: "details_player", Points: 300, Prize: 200, Entry: 2}, MaxEntries: 2, Closed: &closed}
+	db.On("GetTournament", tour.ID).Return(tour, nil)
+	db.On("GetTournament", "details_fake").Return(entity.Tournament{}, errors.Error{Code: errors.NotFoundError})
+	got, err := g.Tournament(tour.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, tour, got)
+	_, err = g.Tournament("details_fake")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError}, err)
+	_, err = g.Tournament("")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "tournament: id must be not nil"}, err)
+}
+
+func TestController_History(t *testing.T) {
+	joined := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tours := []entity.Participation{
+		{TournamentID: "history_open", PlayerID: "history_player", Currency: entity.DefaultCurrency, IsOpen: true, Paid: 200, Joined: joined.Add(time.Hour)},
+		{TournamentID: "history_won", PlayerID: "history_player", Currency: entity.DefaultCurrency, Paid: 100, Placing: 1, Prize: 300, Joined: joined},
+	}
+	db.On("GetHistory", "history_player").Return(tours, nil)
+	db.On("GetHistory", "history_error").Return(nil, errors.Error{Code: errors.UnexpectedError})
+	history, err := g.History("history_player")
+	assert.Nil(t, err)
+	assert.Equal(t, entity.History{PlayerID: "history_player", Tournaments: tours}, history)
+	_, err = g.History("history_error")
+	assert.Equal(t, errors.Error{Code: errors.UnexpectedError}, err)
+	_, err = g.History("")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "history: player id must be not nil"}, err)
+}
+
 func TestController_Announce(t *testing.T) {
 	tournaments := []entity.Tournament{
 		{ID: "announce_ok", Deposit: 100},
 		{ID: "announce_negative_deposit", Deposit: -100},
 	}
-	db.On("CreateTournament", tournaments[0].ID, tournaments[0].Deposit).Return(nil)
+	db.On("CreateTournament", tournaments[0].ID, entity.DefaultCurrency, tournaments[0].Deposit, 1).Return(nil)
+	db.On("CreateTournament", tournaments[0].ID, entity.DefaultCurrency, tournaments[0].Deposit, 3).Return(nil)
 	tt := []struct {
 		name          string
 		tourID        string
 		deposit       int
+		maxEntries    int
 		expectedError error
 	}{
 		{
 			name:          "announce: ok",
 			tourID:        tournaments[0].ID,
 			deposit:       tournaments[0].Deposit,
+			maxEntries:    1,
+			expectedError: nil,
+		},
+		{
+			name:          "announce: re-entry",
+			tourID:        tournaments[0].ID,
+			deposit:       tournaments[0].Deposit,
+			maxEntries:    3,
 			expectedError: nil,
 		},
 		{
 			name:          "announce: negative deposit",
 			tourID:        tournaments[1].ID,
 			deposit:       tournaments[1].Deposit,
+			maxEntries:    1,
 			expectedError: errors.Error{Code: errors.NegativeDepositError, Message: "announce: cannot create tournament with not positive deposite, id: " + tournaments[1].ID},
 		},
+		{
+			name:          "announce: not positive max entries",
+			tourID:        tournaments[0].ID,
+			deposit:       tournaments[0].Deposit,
+			maxEntries:    0,
+			expectedError: errors.Error{Code: errors.NegativeEntriesError, Message: "announce: cannot create tournament with not positive max entries number, id: " + tournaments[0].ID},
+		},
 		{
 			name:          "announce: empty id",
 			tourID:        "",
 			deposit:       1,
+			maxEntries:    1,
 			expectedError: errors.Error{Code: errors.NotFoundError, Message: "announce: id must be not nil"},
 		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			err := g.AnnounceTournament(tc.tourID, tc.deposit)
+			err := g.AnnounceTournament(tc.tourID, "", tc.deposit, tc.maxEntries)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestController_AnnounceSatellite(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "satellite_target_open", IsOpen: true},
+		{ID: "satellite_target_closed", IsOpen: false},
+		{ID: "satellite_ok", Deposit: 10, Satellite: entity.Satellite{TargetID: "satellite_target_open", Seats: 2}},
+		{ID: "satellite_target_team", IsOpen: true, IsTeam: true},
+	}
+	db.On("GetTournamentState", tournaments[0].ID).Return(tournaments[0].IsOpen, nil)
+	db.On("GetTournamentState", tournaments[1].ID).Return(tournaments[1].IsOpen, nil)
+	db.On("GetTournamentState", tournaments[3].ID).Return(tournaments[3].IsOpen, nil)
+	db.On("IsTeamTournament", tournaments[0].ID).Return(tournaments[0].IsTeam, nil)
+	db.On("IsTeamTournament", tournaments[3].ID).Return(tournaments[3].IsTeam, nil)
+	db.On("GetTournamentState", "satellite_target_not_found").Return(false, errors.Error{Code: errors.NotFoundError})
+	db.On("CreateSatellite", tournaments[2].ID, tournaments[2].Deposit, tournaments[0].ID, tournaments[2].Satellite.Seats).Return(nil)
+	tt := []struct {
+		name          string
+		tourID        string
+		deposit       int
+		targetID      string
+		seats         int
+		expectedError error
+	}{
+		{
+			name:          "announce satellite: ok",
+			tourID:        tournaments[2].ID,
+			deposit:       tournaments[2].Deposit,
+			targetID:      tournaments[0].ID,
+			seats:         tournaments[2].Satellite.Seats,
+			expectedError: nil,
+		},
+		{
+			name:          "announce satellite: negative deposit",
+			tourID:        tournaments[2].ID,
+			deposit:       -1,
+			targetID:      tournaments[0].ID,
+			seats:         1,
+			expectedError: errors.Error{Code: errors.NegativeDepositError, Message: "announce satellite: cannot create tournament with not positive deposite, id: " + tournaments[2].ID},
+		},
+		{
+			name:          "announce satellite: no seats",
+			tourID:        tournaments[2].ID,
+			deposit:       1,
+			targetID:      tournaments[0].ID,
+			seats:         0,
+			expectedError: errors.Error{Code: errors.NegativeSeatsError, Message: "announce satellite: cannot create satellite with not positive number of seats, id: " + tournaments[2].ID},
+		},
+		{
+			name:          "announce satellite: empty target id",
+			tourID:        tournaments[2].ID,
+			deposit:       1,
+			targetID:      "",
+			seats:         1,
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "announce satellite: target id must be not nil"},
+		},
+		{
+			name:          "announce satellite: target not found",
+			tourID:        tournaments[2].ID,
+			deposit:       1,
+			targetID:      "satellite_target_not_found",
+			seats:         1,
+			expectedError: errors.Error{Code: errors.NotFoundError},
+		},
+		{
+			name:          "announce satellite: closed target",
+			tourID:        tournaments[2].ID,
+			deposit:       1,
+			targetID:      tournaments[1].ID,
+			seats:         1,
+			expectedError: errors.Error{Code: errors.ClosedTournamentError, Message: "announce satellite: cannot award seats in closed tournament, targetID: " + tournaments[1].ID},
+		},
+		{
+			name:          "announce satellite: team target",
+			tourID:        tournaments[2].ID,
+			deposit:       1,
+			targetID:      tournaments[3].ID,
+			seats:         1,
+			expectedError: errors.Error{Code: errors.TeamTournamentError, Message: "announce satellite: cannot award seats of players in team tournament, targetID: " + tournaments[3].ID},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := g.AnnounceSatellite(tc.tourID, tc.deposit, tc.targetID, tc.seats)
 			assert.Equal(t, tc.expectedError, err)
 		})
 	}
@@ -216,18 +879,34 @@ func TestController_Join(t *testing.T) {
 		{ID: "join_closed_tournament", Deposit: 15, IsOpen: false},
 		{ID: "join_getparticipants_error", Deposit: 20, IsOpen: true},
 		{ID: "join_duplicate", Deposit: 33, IsOpen: true},
+		{ID: "join_reentry", Deposit: 10, IsOpen: true, MaxEntries: 2},
 	}
 	db.On("GetTournamentState", tournaments[0].ID).Return(tournaments[0].IsOpen, nil)
 	db.On("GetTournamentState", tournaments[1].ID).Return(false, errors.Error{Code: errors.NotFoundError})
 	db.On("GetTournamentState", tournaments[2].ID).Return(tournaments[2].IsOpen, nil)
 	db.On("GetTournamentState", tournaments[3].ID).Return(tournaments[3].IsOpen, nil)
 	db.On("GetTournamentState", tournaments[4].ID).Return(tournaments[4].IsOpen, nil)
+	db.On("GetTournamentState", tournaments[5].ID).Return(tournaments[5].IsOpen, nil)
+
+	for _, i := range []int{0, 3, 4, 5} {
+		db.On("IsTeamTournament", tournaments[i].ID).Return(false, nil)
+	}
+	db.On("IsTeamTournament", "join_team_tournament").Return(true, nil)
+	db.On("GetTournamentState", "join_team_tournament").Return(true, nil)
 
-	db.On("GetParticipants", tournaments[0].ID).Return(nil, nil)
-	db.On("GetParticipants", tournaments[3].ID).Return(nil, errors.Error{Code: errors.NotFoundError})
-	db.On("GetParticipants", tournaments[4].ID).Return([]string{players[1].ID}, nil)
+	db.On("GetEntries", tournaments[0].ID).Return(nil, nil)
+	db.On("GetEntries", tournaments[3].ID).Return(nil, errors.Error{Code: errors.NotFoundError})
+	db.On("GetEntries", tournaments[4].ID).Return([]entity.Entry{{PlayerID: players[1].ID, Number: 1}}, nil)
+	db.On("GetEntries", tournaments[5].ID).Return([]entity.Entry{{PlayerID: players[0].ID, Number: 1}, {PlayerID: players[1].ID, Number: 1}, {PlayerID: players[1].ID, Number: 2}}, nil)
+
+	db.On("GetMaxEntries", tournaments[0].ID).Return(1, nil)
+	db.On("GetMaxEntries", tournaments[4].ID).Return(1, nil)
+	db.On("GetMaxEntries", tournaments[5].ID).Return(tournaments[5].MaxEntries, nil)
+	db.On("GetDeposit", tournaments[0].ID).Return(tournaments[0].Deposit, nil)
+	db.On("GetDeposit", tournaments[5].ID).Return(tournaments[5].Deposit, nil)
 
 	db.On("UpdateTourAndPlayer", tournaments[0].ID, players[0].ID).Return(nil)
+	db.On("UpdateTourAndPlayer", tournaments[5].ID, players[0].ID).Return(nil)
 	tt := []struct {
 		name          string
 		tourID        string
@@ -265,7 +944,7 @@ func TestController_Join(t *testing.T) {
 			expectedError: errors.Error{Code: errors.ClosedTournamentError, Message: "join tournament: cannot join to closed tournament, tourID: " + tournaments[2].ID},
 		},
 		{
-			name:          "join: get participants error",
+			name:          "join: get entries error",
 			tourID:        tournaments[3].ID,
 			playerID:      players[0].ID,
 			expectedError: errors.Error{Code: errors.NotFoundError},
@@ -276,6 +955,24 @@ func TestController_Join(t *testing.T) {
 			playerID:      players[1].ID,
 			expectedError: errors.Error{Code: errors.DuplicatedIDError, Message: "join tournament: cannot join to one tournament twice, playerID: " + players[1].ID},
 		},
+		{
+			name:          "join: re-entry",
+			tourID:        tournaments[5].ID,
+			playerID:      players[0].ID,
+			expectedError: nil,
+		},
+		{
+			name:          "join: all entries used",
+			tourID:        tournaments[5].ID,
+			playerID:      players[1].ID,
+			expectedError: errors.Error{Code: errors.DuplicatedIDError, Message: "join tournament: player has used all 2 entries, playerID: " + players[1].ID},
+		},
+		{
+			name:          "join: team tournament",
+			tourID:        "join_team_tournament",
+			playerID:      players[0].ID,
+			expectedError: errors.Error{Code: errors.TeamTournamentError, Message: "join tournament: players cannot join team tournament alone, tourID: join_team_tournament"},
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -291,14 +988,14 @@ func TestController_Result(t *testing.T) {
 		{ID: "result_not_existing", Points: 0},
 	}
 	winners := []entity.Winner{
-		{ID: "result_ok", Points: 100},
+		{ID: "result_ok", Points: 100, Entry: 1},
 	}
 	tournaments := []entity.Tournament{
 		{ID: "result_ok", Deposit: 100, IsOpen: true, Participants: []string{players[0].ID}, Prize: 100},
 		{ID: "result_closed_tournament", Deposit: 50, IsOpen: false},
 		{ID: "result_not_found", Deposit: 50, IsOpen: false},
 		{ID: "result_failed_to_close", Deposit: 50, IsOpen: true},
-		{ID: "result_failed_to_get_participants", Deposit: 50, IsOpen: true},
+		{ID: "result_failed_to_get_entries", Deposit: 50, IsOpen: true},
 		{ID: "result_empty_participants", Deposit: 50, IsOpen: true},
 		{ID: "result_not_existing_player", Deposit: 50, IsOpen: true, Participants: []string{players[1].ID}},
 		{ID: "result_failed_to_set_winner", Deposit: 50, IsOpen: true, Participants: []string{players[0].ID}},
@@ -312,6 +1009,11 @@ func TestController_Result(t *testing.T) {
 	db.On("GetTournamentState", tournaments[6].ID).Return(tournaments[6].IsOpen, nil)
 	db.On("GetTournamentState", tournaments[7].ID).Return(tournaments[7].IsOpen, nil)
 
+	for _, i := range []int{0, 3, 4, 5, 6, 7} {
+		db.On("GetSatellite", tournaments[i].ID).Return(entity.Satellite{}, nil)
+		db.On("IsTeamTournament", tournaments[i].ID).Return(false, nil)
+	}
+
 	db.On("CloseTournament", tournaments[0].ID).Return(nil)
 	db.On("CloseTournament", tournaments[3].ID).Return(errors.Error{Code: errors.NotFoundError})
 	db.On("CloseTournament", tournaments[4].ID).Return(nil)
@@ -319,14 +1021,18 @@ func TestController_Result(t *testing.T) {
 	db.On("CloseTournament", tournaments[6].ID).Return(nil)
 	db.On("CloseTournament", tournaments[7].ID).Return(nil)
 
-	db.On("GetParticipants", tournaments[0].ID).Return(tournaments[0].Participants, nil)
-	db.On("GetParticipants", tournaments[4].ID).Return(nil, errors.Error{Code: errors.NotFoundError})
-	db.On("GetParticipants", tournaments[5].ID).Return(nil, nil)
-	db.On("GetParticipants", tournaments[6].ID).Return(tournaments[6].Participants, nil)
-	db.On("GetParticipants", tournaments[7].ID).Return(tournaments[7].Participants, nil)
+	db.On("GetEntries", tournaments[0].ID).Return([]entity.Entry{{PlayerID: players[0].ID, Number: 1}}, nil)
+	db.On("GetEntries", tournaments[4].ID).Return(nil, errors.Error{Code: errors.NotFoundError})
+	db.On("GetEntries", tournaments[5].ID).Return(nil, nil)
+	db.On("GetEntries", tournaments[6].ID).Return([]entity.Entry{{PlayerID: players[1].ID, Number: 1}}, nil)
+	db.On("GetEntries", tournaments[7].ID).Return([]entity.Entry{{PlayerID: players[0].ID, Number: 1}}, nil)
 
-	db.On("GetPlayer", players[0].ID).Return(players[0], nil)
-	db.On("GetPlayer", players[1].ID).Return(entity.Player{}, errors.Error{Code: errors.NotFoundError})
+	for _, i := range []int{0, 6, 7} {
+		db.On("GetCurrency", tournaments[i].ID).Return(entity.DefaultCurrency, nil)
+	}
+
+	db.On("GetPlayer", players[0].ID, entity.DefaultCurrency).Return(players[0], nil)
+	db.On("GetPlayer", players[1].ID, entity.DefaultCurrency).Return(entity.Player{}, errors.Error{Code: errors.NotFoundError})
 
 	db.On("SetTournamentWinner", tournaments[0].ID, winners[0]).Return(nil)
 	db.On("SetTournamentWinner", tournaments[7].ID, winners[0]).Return(errors.Error{Code: errors.NotFoundError})
@@ -371,7 +1077,7 @@ func TestController_Result(t *testing.T) {
 			expectedError:   errors.Error{Code: errors.NotFoundError},
 		},
 		{
-			name:            "result: failed to get participants",
+			name:            "result: failed to get entries",
 			tourID:          tournaments[4].ID,
 			expectedWinners: entity.Winners{},
 			expectedError:   errors.Error{Code: errors.NotFoundError},
@@ -403,3 +1109,580 @@ func TestController_Result(t *testing.T) {
 		})
 	}
 }
+
+func TestController_ResultSatellite(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "result_satellite_ok", IsOpen: true, Entries: []entity.Entry{{PlayerID: "result_satellite_1", Number: 1}, {PlayerID: "result_satellite_2", Number: 1}, {PlayerID: "result_satellite_1", Number: 2}}, Satellite: entity.Satellite{TargetID: "result_satellite_target", Seats: 3}},
+		{ID: "result_satellite_target", IsOpen: true, Participants: []string{"result_satellite_2"}},
+		{ID: "result_satellite_closed_target", IsOpen: true, Satellite: entity.Satellite{TargetID: "result_satellite_target_closed", Seats: 1}},
+		{ID: "result_satellite_target_closed", IsOpen: false},
+	}
+	winners := entity.Winners{Winners: []entity.Winner{{ID: "result_satellite_1", Prize: 100, Seat: tournaments[1].ID}}}
+	for i := range tournaments {
+		db.On("GetTournamentState", tournaments[i].ID).Return(tournaments[i].IsOpen, nil)
+		db.On("GetSatellite", tournaments[i].ID).Return(tournaments[i].Satellite, nil)
+		db.On("GetParticipants", tournaments[i].ID).Return(tournaments[i].Participants, nil)
+		db.On("GetEntries", tournaments[i].ID).Return(tournaments[i].Entries, nil)
+	}
+	db.On("CloseTournament", tournaments[0].ID).Return(nil)
+	db.On("SetSatelliteWinners", tournaments[0].ID, []string{"result_satellite_1", "result_satellite_2"}, 1).Return(nil)
+	db.On("GetWinner", tournaments[0].ID).Return(winners, nil)
+	tt := []struct {
+		name            string
+		tourID          string
+		expectedWinners entity.Winners
+		expectedError   error
+	}{
+		{
+			name:            "result satellite: ok",
+			tourID:          tournaments[0].ID,
+			expectedWinners: winners,
+			expectedError:   nil,
+		},
+		{
+			name:            "result satellite: closed target",
+			tourID:          tournaments[2].ID,
+			expectedWinners: entity.Winners{},
+			expectedError:   errors.Error{Code: errors.ClosedTournamentError, Message: "results: cannot award seats in closed tournament, targetID: " + tournaments[3].ID},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := g.Results(tc.tourID)
+			assert.Equal(t, tc.expectedWinners, w)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestController_CreateTeam(t *testing.T) {
+	players := []entity.Player{
+		{ID: "createteam_1", Points: 100},
+		{ID: "createteam_2", Points: 100},
+		{ID: "createteam_3", Points: 100},
+	}
+	for i := range players {
+		db.On("GetBalances", players[i].ID).Return([]entity.Player{players[i]}, nil)
+	}
+	db.On("GetBalances", "createteam_not_found").Return(nil, errors.Error{Code: errors.NotFoundError})
+	teams := []entity.Team{
+		{ID: "createteam_ok", Captain: players[0].ID, Members: []entity.TeamMember{{PlayerID: players[0].ID, Share: 34}, {PlayerID: players[1].ID, Share: 33}, {PlayerID: players[2].ID, Share: 33}}},
+		{ID: "createteam_shares", Captain: players[1].ID, Members: []entity.TeamMember{{PlayerID: players[0].ID, Share: 20}, {PlayerID: players[1].ID, Share: 80}}},
+	}
+	db.On("CreateTeam", teams[0]).Return(nil)
+	db.On("CreateTeam", teams[1]).Return(nil)
+	tt := []struct {
+		name          string
+		id            string
+		captain       string
+		members       []string
+		shares        []int
+		expectedTeam  entity.Team
+		expectedError error
+	}{
+		{
+			name:          "create team: equal shares",
+			id:            teams[0].ID,
+			members:       []string{players[0].ID, players[1].ID, players[2].ID},
+			expectedTeam:  teams[0],
+			expectedError: nil,
+		},
+		{
+			name:          "create team: shares",
+			id:            teams[1].ID,
+			captain:       players[1].ID,
+			members:       []string{players[0].ID, players[1].ID},
+			shares:        []int{20, 80},
+			expectedTeam:  teams[1],
+			expectedError: nil,
+		},
+		{
+			name:          "create team: empty id",
+			members:       []string{players[0].ID},
+			expectedTeam:  entity.Team{},
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "create team: id must be not nil"},
+		},
+		{
+			name:          "create team: no members",
+			id:            "createteam_no_members",
+			expectedTeam:  entity.Team{},
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "create team: team must have members, id: createteam_no_members"},
+		},
+		{
+			name:          "create team: captain is not member",
+			id:            "createteam_captain",
+			captain:       players[2].ID,
+			members:       []string{players[0].ID, players[1].ID},
+			expectedTeam:  entity.Team{},
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "create team: captain must be team member, captain: " + players[2].ID},
+		},
+		{
+			name:          "create team: wrong shares sum",
+			id:            "createteam_sum",
+			members:       []string{players[0].ID, players[1].ID},
+			shares:        []int{50, 40},
+			expectedTeam:  entity.Team{},
+			expectedError: errors.Error{Code: errors.InvalidSplitError, Message: "create team: sum of shares must be 100, id: createteam_sum"},
+		},
+		{
+			name:          "create team: duplicated member",
+			id:            "createteam_duplicate",
+			members:       []string{players[0].ID, players[0].ID},
+			expectedTeam:  entity.Team{},
+			expectedError: errors.Error{Code: errors.DuplicatedIDError, Message: "create team: cannot add player to team twice, playerID: " + players[0].ID},
+		},
+		{
+			name:          "create team: not existing player",
+			id:            "createteam_not_found",
+			members:       []string{players[0].ID, "createteam_not_found"},
+			expectedTeam:  entity.Team{},
+			expectedError: errors.Error{Code: errors.NotFoundError},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			team, err := g.CreateTeam(tc.id, tc.captain, tc.members, tc.shares)
+			assert.Equal(t, tc.expectedTeam, team)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestController_JoinTeam(t *testing.T) {
+	teams := []entity.Team{
+		{ID: "jointeam_1", Captain: "jointeam_1", Members: []entity.TeamMember{{PlayerID: "jointeam_1", Share: 50}, {PlayerID: "jointeam_2", Share: 50}}},
+		{ID: "jointeam_2", Captain: "jointeam_3", Members: []entity.TeamMember{{PlayerID: "jointeam_3", Share: 100}}},
+		{ID: "jointeam_3", Captain: "jointeam_2", Members: []entity.TeamMember{{PlayerID: "jointeam_2", Share: 100}}},
+	}
+	tournaments := []entity.Tournament{
+		{ID: "jointeam_ok", IsOpen: true, IsTeam: true, Deposit: 100, Teams: []string{teams[1].ID}},
+		{ID: "jointeam_solo", IsOpen: true},
+		{ID: "jointeam_closed", IsOpen: false, IsTeam: true},
+		{ID: "jointeam_duplicate", IsOpen: true, IsTeam: true, Teams: []string{teams[0].ID}},
+	}
+	for i := range teams {
+		db.On("GetTeam", teams[i].ID).Return(teams[i], nil)
+	}
+	for i := range tournaments {
+		db.On("GetTournamentState", tournaments[i].ID).Return(tournaments[i].IsOpen, nil)
+		db.On("IsTeamTournament", tournaments[i].ID).Return(tournaments[i].IsTeam, nil)
+		db.On("GetTeams", tournaments[i].ID).Return(tournaments[i].Teams, nil)
+		db.On("GetDeposit", tournaments[i].ID).Return(tournaments[i].Deposit, nil)
+	}
+	db.On("UpdateTourAndTeam", tournaments[0].ID, teams[0].ID, true).Return(nil)
+	tt := []struct {
+		name          string
+		tourID        string
+		teamID        string
+		expectedError error
+	}{
+		{
+			name:          "join team: ok",
+			tourID:        tournaments[0].ID,
+			teamID:        teams[0].ID,
+			expectedError: nil,
+		},
+		{
+			name:          "join team: empty team id",
+			tourID:        tournaments[0].ID,
+			teamID:        "",
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "join team: team id must be not nil"},
+		},
+		{
+			name:          "join team: not team tournament",
+			tourID:        tournaments[1].ID,
+			teamID:        teams[0].ID,
+			expectedError: errors.Error{Code: errors.TeamTournamentError, Message: "join team: teams cannot join not team tournament, tourID: " + tournaments[1].ID},
+		},
+		{
+			name:          "join team: closed tournament",
+			tourID:        tournaments[2].ID,
+			teamID:        teams[0].ID,
+			expectedError: errors.Error{Code: errors.ClosedTournamentError, Message: "join team: cannot join to closed tournament, tourID: " + tournaments[2].ID},
+		},
+		{
+			name:          "join team: duplicated team",
+			tourID:        tournaments[3].ID,
+			teamID:        teams[0].ID,
+			expectedError: errors.Error{Code: errors.DuplicatedIDError, Message: "join team: cannot join to one tournament twice, teamID: " + teams[0].ID},
+		},
+		{
+			name:          "join team: player in other team",
+			tourID:        tournaments[3].ID,
+			teamID:        teams[2].ID,
+			expectedError: errors.Error{Code: errors.DuplicatedIDError, Message: "join team: player has already joined tournament in other team, playerID: jointeam_2"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := g.JoinTeam(tc.tourID, tc.teamID, true)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestController_ResultTeams(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "resultteam_ok", IsOpen: true, IsTeam: true, Teams: []string{"resultteam_1"}},
+		{ID: "resultteam_empty", IsOpen: true, IsTeam: true},
+	}
+	winners := entity.Winners{Winners: []entity.Winner{{ID: "resultteam_1", Prize: 50, Team: "resultteam_1"}, {ID: "resultteam_2", Prize: 50, Team: "resultteam_1"}}}
+	for i := range tournaments {
+		db.On("GetTournamentState", tournaments[i].ID).Return(tournaments[i].IsOpen, nil)
+		db.On("GetSatellite", tournaments[i].ID).Return(entity.Satellite{}, nil)
+		db.On("IsTeamTournament", tournaments[i].ID).Return(tournaments[i].IsTeam, nil)
+		db.On("CloseTournament", tournaments[i].ID).Return(nil)
+		db.On("GetTeams", tournaments[i].ID).Return(tournaments[i].Teams, nil)
+	}
+	db.On("SetTeamWinner", tournaments[0].ID, "resultteam_1").Return(nil)
+	db.On("GetWinner", tournaments[0].ID).Return(winners, nil)
+	tt := []struct {
+		name            string
+		tourID          string
+		expectedWinners entity.Winners
+		expectedError   error
+	}{
+		{
+			name:            "result teams: ok",
+			tourID:          tournaments[0].ID,
+			expectedWinners: winners,
+			expectedError:   nil,
+		},
+		{
+			name:            "result teams: no teams",
+			tourID:          tournaments[1].ID,
+			expectedWinners: entity.Winners{},
+			expectedError:   errors.Error{Code: errors.NoneParticipantsError, Message: "cannot choose winner: tournament has no teams, id: " + tournaments[1].ID},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := g.Results(tc.tourID)
+			assert.Equal(t, tc.expectedWinners, w)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestController_SweepLots(t *testing.T) {
+	sweeper := &MockDatabase{}
+	swept := make(chan struct{})
+	lots := []entity.Lot{{PlayerID: "lots_player", Currency: entity.DefaultCurrency, Points: 30}}
+	sweeper.On("ExpireLots").Return(lots, nil).Run(func(mock.Arguments) { swept <- struct{}{} }).Once()
+	sweeper.On("ExpireLots").Return(nil, nil)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	bus := &recorder{}
+	go func() {
+		Game{DB: sweeper, Events: bus}.SweepLots(time.Millisecond, stop)
+		close(done)
+	}()
+	<-swept
+	close(stop)
+	<-done
+	sweeper.AssertCalled(t, "ExpireLots")
+	if assert.Len(t, bus.events, 1) {
+		assert.Equal(t, entity.EventBalanceChanged, bus.events[0].Type)
+		assert.Equal(t, -30, bus.events[0].Points)
+	}
+}
+
+type recorder struct {
+	events []entity.Event
+}
+
+func (r *recorder) Publish(e entity.Event) {
+	r.events = append(r.events, e)
+}
+
+func TestController_Events(t *testing.T) {
+	db.On("TransferPoints", "events_1", "events_2", entity.DefaultCurrency, 50).Return(nil)
+	db.On("TransferPoints", "events_1", "events_2", entity.DefaultCurrency, 500).Return(errors.Error{Code: errors.NegativePointsNumberError})
+	db.On("UpdatePlayer", "events_1", "coins", -10).Return(nil)
+	db.On("CaptureHold", "events_hold").Return(entity.Hold{ID: "events_hold", PlayerID: "events_1", Points: 20, Currency: entity.DefaultCurrency}, nil)
+	db.On("RedeemPromo", "events_promo", "events_1", mock.AnythingOfType("time.Time")).
+		Return(func(code, playerID string, now time.Time) entity.Redemption {
+			return entity.Redemption{Code: code, PlayerID: playerID, Use: 1, Points: 100, Currency: entity.DefaultCurrency, Redeemed: now}
+		}, nil)
+	db.On("RedeemPromo", "events_used", "events_1", mock.AnythingOfType("time.Time")).
+		Return(entity.Redemption{Code: "events_used", PlayerID: "events_1", Use: 1, Points: 100, Currency: entity.DefaultCurrency, Redeemed: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	tt := []struct {
+		name           string
+		call           func(g Game) error
+		expectedEvents []entity.Event
+	}{
+		{
+			name: "events: transfer",
+			call: func(g Game) error { return g.Transfer("events_1", "events_2", "", 50) },
+			expectedEvents: []entity.Event{
+				{Type: entity.EventBalanceChanged, PlayerID: "events_1", Currency: entity.DefaultCurrency, Points: -50},
+				{Type: entity.EventBalanceChanged, PlayerID: "events_2", Currency: entity.DefaultCurrency, Points: 50},
+			},
+		},
+		{
+			name: "events: failed transfer",
+			call: func(g Game) error { return g.Transfer("events_1", "events_2", "", 500) },
+		},
+		{
+			name: "events: take",
+			call: func(g Game) error { return g.Take("events_1", "coins", 10) },
+			expectedEvents: []entity.Event{
+				{Type: entity.EventBalanceChanged, PlayerID: "events_1", Currency: "coins", Points: -10},
+			},
+		},
+		{
+			name: "events: capture",
+			call: func(g Game) error { return g.Capture("events_hold") },
+			expectedEvents: []entity.Event{
+				{Type: entity.EventBalanceChanged, PlayerID: "events_1", Currency: entity.DefaultCurrency, Points: -20},
+			},
+		},
+		{
+			name: "events: redeem",
+			call: func(g Game) error {
+				_, err := g.Redeem("events_promo", "events_1")
+				return err
+			},
+			expectedEvents: []entity.Event{
+				{Type: entity.EventBalanceChanged, PlayerID: "events_1", Currency: entity.DefaultCurrency, Points: 100},
+			},
+		},
+		{
+			name: "events: repeated redeem",
+			call: func(g Game) error {
+				_, err := g.Redeem("events_used", "events_1")
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &recorder{}
+			_ = tc.call(Game{DB: db, Events: rec})
+			for i := range rec.events {
+				assert.False(t, rec.events[i].Created.IsZero())
+				rec.events[i].Created = time.Time{}
+			}
+			assert.Equal(t, tc.expectedEvents, rec.events)
+		})
+	}
+}
+
+func TestController_Webhooks(t *testing.T) {
+	db.On("CreateWebhook", mock.MatchedBy(func(w entity.Webhook) bool { return w.ID == "webhooks_new" })).Return(nil)
+	db.On("CreateWebhook", mock.MatchedBy(func(w entity.Webhook) bool { return w.ID == "webhooks_dup" })).Return(errors.Error{Code: errors.DuplicatedIDError})
+	db.On("ListWebhooks").Return([]entity.Webhook{{ID: "webhooks_new", URL: "https://example.com/hook", Events: WebhookEvents, Secret: "secret"}}, nil)
+
+	webhook, err := g.CreateWebhook(entity.Webhook{ID: "webhooks_new", URL: "https://example.com/hook",
+		Events: []string{entity.EventBalanceChanged, entity.EventBalanceChanged}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{entity.EventBalanceChanged}, webhook.Events)
+	assert.Len(t, webhook.Secret, 2*secretSize)
+	assert.False(t, webhook.Created.IsZero())
+	webhook, err = g.CreateWebhook(entity.Webhook{ID: "webhooks_new", URL: "http://example.com", Events: []string{entity.EventWinnerChosen}, Secret: "secret"})
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", webhook.Secret)
+	_, err = g.CreateWebhook(entity.Webhook{ID: "webhooks_dup", URL: "http://example.com", Events: []string{entity.EventWinnerChosen}})
+	assert.Equal(t, errors.Error{Code: errors.DuplicatedIDError}, err)
+
+	tt := []struct {
+		name          string
+		webhook       entity.Webhook
+		expectedError error
+	}{
+		{
+			name:          "create webhook: empty id",
+			webhook:       entity.Webhook{URL: "http://example.com", Events: WebhookEvents},
+			expectedError: errors.Error{Code: errors.NotFoundError, Message: "create webhook: id must be not nil"},
+		},
+		{
+			name:          "create webhook: relative url",
+			webhook:       entity.Webhook{ID: "webhooks_invalid", URL: "/hook", Events: WebhookEvents},
+			expectedError: errors.Error{Code: errors.InvalidWebhookError, Message: "create webhook: url must be absolute http or https url, id: webhooks_invalid", Info: "/hook"},
+		},
+		{
+			name:          "create webhook: not http url",
+			webhook:       entity.Webhook{ID: "webhooks_invalid", URL: "ftp://example.com", Events: WebhookEvents},
+			expectedError: errors.Error{Code: errors.InvalidWebhookError, Message: "create webhook: url must be absolute http or https url, id: webhooks_invalid", Info: "ftp://example.com"},
+		},
+		{
+			name:          "create webhook: without events",
+			webhook:       entity.Webhook{ID: "webhooks_invalid", URL: "http://example.com"},
+			expectedError: errors.Error{Code: errors.InvalidWebhookError, Message: "create webhook: webhook must get events, id: webhooks_invalid"},
+		},
+		{
+			name:          "create webhook: unknown event",
+			webhook:       entity.Webhook{ID: "webhooks_invalid", URL: "http://example.com", Events: []string{entity.EventPlayerJoined}},
+			expectedError: errors.Error{Code: errors.InvalidWebhookError, Message: "create webhook: unknown event type, id: webhooks_invalid", Info: entity.EventPlayerJoined},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := g.CreateWebhook(tc.webhook)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+
+	webhooks, err := g.Webhooks()
+	assert.Nil(t, err)
+	assert.Equal(t, []entity.Webhook{{ID: "webhooks_new", URL: "https://example.com/hook", Events: WebhookEvents}}, webhooks)
+	err = g.RetryDelivery("")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "retry delivery: id must be not nil"}, err)
+}
+
+func TestController_Batch(t *testing.T) {
+	db.On("GetLimits", "limits_batch").Return(entity.Limits{PlayerID: "limits_batch", Daily: 100}, nil)
+	db.On("GetSpent", "limits_batch", mock.AnythingOfType("time.Time")).Return(0, nil)
+	db.On("GetTournamentState", "batch_tournament").Return(true, nil)
+	db.On("IsTeamTournament", "batch_tournament").Return(false, nil)
+	db.On("GetEntries", "batch_tournament").Return(nil, nil)
+	db.On("GetMaxEntries", "batch_tournament").Return(1, nil)
+	db.On("GetDeposit", "batch_tournament").Return(50, nil)
+	db.On("GetCurrency", "batch_tournament").Return("coins", nil)
+	db.On("ApplyEach", []entity.Operation{
+		{Type: entity.OpFund, PlayerID: "batch_1", Currency: entity.DefaultCurrency, Points: 100},
+		{Type: entity.OpTake, PlayerID: "batch_1", Currency: entity.DefaultCurrency, Points: 300},
+		{Type: entity.OpTake, PlayerID: "limits_batch", Currency: entity.DefaultCurrency, Points: 60},
+	}).Return([]error{nil, errors.Error{Code: errors.NegativePointsNumberError}, nil}, nil)
+	db.On("ApplyBatch", []entity.Operation{
+		{Type: entity.OpFund, PlayerID: "batch_1", Currency: "coins", Points: 100},
+		{Type: entity.OpJoin, PlayerID: "batch_1", Currency: entity.DefaultCurrency, TournamentID: "batch_tournament"},
+	}).Return(1, errors.Error{Code: errors.DuplicatedIDError})
+	db.On("ApplyBatch", []entity.Operation{
+		{Type: entity.OpFund, PlayerID: "batch_2", Currency: "coins", Points: 100},
+		{Type: entity.OpJoin, PlayerID: "batch_2", Currency: entity.DefaultCurrency, TournamentID: "batch_tournament"},
+	}).Return(-1, nil)
+	db.On("ApplyBatch", []entity.Operation{
+		{Type: entity.OpFund, PlayerID: "batch_3", Currency: entity.DefaultCurrency, Points: 100},
+	}).Return(-1, errors.Error{Code: errors.TransactionError})
+	tt := []struct {
+		name           string
+		ops            []entity.Operation
+		atomic         bool
+		expected       entity.BatchResult
+		expectedError  error
+		expectedEvents []entity.Event
+	}{
+		{
+			name: "batch: independent operations",
+			ops: []entity.Operation{
+				{Type: entity.OpFund, PlayerID: "batch_1", Points: 100},
+				{Type: entity.OpTake, PlayerID: "batch_1", Points: 300},
+				{Type: entity.OpTake, PlayerID: "limits_batch", Points: 60},
+				{Type: entity.OpTake, PlayerID: "limits_batch", Points: 60},
+				{Type: "refund", PlayerID: "batch_1", Points: 100},
+			},
+			expected: entity.BatchResult{Applied: 2, Failed: 3, Results: []entity.OperationResult{
+				{Status: entity.OperationApplied},
+				{Status: entity.OperationFailed, Error: &errors.Error{Code: errors.NegativePointsNumberError, Message: "take: cannot take points, player doesn't have enough points"}},
+				{Status: entity.OperationApplied},
+				{Status: entity.OperationFailed, Error: &errors.Error{Code: errors.LimitExceededError, Message: "take: player would exceed daily limit of 100 points, spent: 0, id: limits_batch"}},
+				{Status: entity.OperationFailed, Error: &errors.Error{Code: errors.InvalidBatchError, Message: "batch: unknown operation type, id: batch_1", Info: "refund"}},
+			}},
+			expectedEvents: []entity.Event{
+				{Type: entity.EventBalanceChanged, PlayerID: "batch_1", Currency: entity.DefaultCurrency, Points: 100},
+				{Type: entity.EventBalanceChanged, PlayerID: "limits_batch", Currency: entity.DefaultCurrency, Points: -60},
+			},
+		},
+		{
+			name: "batch: atomic failed in database",
+			ops: []entity.Operation{
+				{Type: entity.OpFund, PlayerID: "batch_1", Currency: "coins", Points: 100},
+				{Type: entity.OpJoin, PlayerID: "batch_1", TournamentID: "batch_tournament"},
+			},
+			atomic: true,
+			expected: entity.BatchResult{Atomic: true, Failed: 1, Results: []entity.OperationResult{
+				{Status: entity.OperationAborted},
+				{Status: entity.OperationFailed, Error: &errors.Error{Code: errors.DuplicatedIDError}},
+			}},
+		},
+		{
+			name: "batch: atomic failed in check",
+			ops: []entity.Operation{
+				{Type: entity.OpTake, Points: 100},
+				{Type: entity.OpFund, PlayerID: "batch_1", Points: 100},
+			},
+			atomic: true,
+			expected: entity.BatchResult{Atomic: true, Failed: 1, Results: []entity.OperationResult{
+				{Status: entity.OperationFailed, Error: &errors.Error{Code: errors.NotFoundError, Message: "take: id must be not nil"}},
+				{Status: entity.OperationAborted},
+			}},
+		},
+		{
+			name: "batch: atomic applied",
+			ops: []entity.Operation{
+				{Type: entity.OpFund, PlayerID: "batch_2", Currency: "coins", Points: 100},
+				{Type: entity.OpJoin, PlayerID: "batch_2", TournamentID: "batch_tournament"},
+			},
+			atomic: true,
+			expected: entity.BatchResult{Atomic: true, Applied: 2, Results: []entity.OperationResult{
+				{Status: entity.OperationApplied},
+				{Status: entity.OperationApplied},
+			}},
+			expectedEvents: []entity.Event{
+				{Type: entity.EventBalanceChanged, PlayerID: "batch_2", Currency: "coins", Points: 100},
+				{Type: entity.EventPlayerJoined, TournamentID: "batch_tournament", PlayerID: "batch_2"},
+				{Type: entity.EventBalanceChanged, TournamentID: "batch_tournament", PlayerID: "batch_2", Currency: "coins", Points: -50},
+			},
+		},
+		{
+			name:          "batch: atomic failed as whole",
+			ops:           []entity.Operation{{Type: entity.OpFund, PlayerID: "batch_3", Points: 100}},
+			atomic:        true,
+			expectedError: errors.Error{Code: errors.TransactionError},
+		},
+		{
+			name:          "batch: empty",
+			expectedError: errors.Error{Code: errors.InvalidBatchError, Message: "batch: batch must have operations"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &recorder{}
+			result, err := Game{DB: db, Events: rec}.Batch(tc.ops, tc.atomic)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expected, result)
+			for i := range rec.events {
+				rec.events[i].Created = time.Time{}
+			}
+			assert.Equal(t, tc.expectedEvents, rec.events)
+		})
+	}
+}
+
+func TestController_APIKeys(t *testing.T) {
+	var created entity.APIKey
+	db.On("CreateAPIKey", mock.MatchedBy(func(k entity.APIKey) bool { return k.Name == "keys_admin" })).
+		Run(func(args mock.Arguments) { created = args.Get(0).(entity.APIKey) }).Return(nil)
+	db.On("GetAPIKey", HashKey("tk_fake")).Return(entity.APIKey{}, errors.Error{Code: errors.NotFoundError})
+	db.On("ListAPIKeys").Return([]entity.APIKey{{ID: "keys_1", Name: "keys_admin", Role: entity.RoleAdmin, Hash: "hash"}}, nil)
+	db.On("DeleteAPIKey", "keys_1").Return(nil)
+
+	_, _, err := g.CreateAPIKey("keys_admin", "root")
+	assert.Equal(t, errors.Error{Code: errors.InvalidRoleError, Message: "create api key: unknown role, name: keys_admin", Info: "root"}, err)
+	apiKey, key, err := g.CreateAPIKey("keys_admin", entity.RoleAdmin)
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(key, keyPrefix))
+	assert.Len(t, key, len(keyPrefix)+2*keySize)
+	assert.Len(t, apiKey.ID, 2*keyIDSize)
+	assert.Equal(t, HashKey(key), apiKey.Hash)
+	assert.NotContains(t, apiKey.Hash, key)
+	assert.Equal(t, apiKey, created)
+
+	db.On("GetAPIKey", HashKey(key)).Return(apiKey, nil)
+	authenticated, err := g.Authenticate(key)
+	assert.Nil(t, err)
+	assert.Equal(t, apiKey, authenticated)
+	_, err = g.Authenticate("tk_fake")
+	assert.Equal(t, errors.Error{Code: errors.UnauthorizedError, Message: "authenticate: api key is not valid"}, err)
+	_, err = g.Authenticate("")
+	assert.Equal(t, errors.Error{Code: errors.UnauthorizedError, Message: "authenticate: api key is not set"}, err)
+
+	keys, err := g.APIKeys()
+	assert.Nil(t, err)
+	assert.Equal(t, []entity.APIKey{{ID: "keys_1", Name: "keys_admin", Role: entity.RoleAdmin}}, keys)
+	assert.Nil(t, g.RevokeAPIKey("keys_1"))
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "revoke api key: id must be not nil"}, g.RevokeAPIKey(""))
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// HoldDB is an interface for database, that used to controll points reservations
+type HoldDB interface {
+	CreateHold(hold entity.Hold) error
+	GetHolds(playerID, currency string) ([]entity.Hold, error)
+	CaptureHold(id string) (entity.Hold, error)
+	ReleaseHold(id string) error
+}
+
+// Hold controlls reserving player points in currency, held points cannot be spent until hold is released or expired
+func (g Game) Hold(id, playerID, currency string, points int, ttl time.Duration) (entity.Hold, error) {
+	if points <= 0 {
+		return entity.Hold{}, errors.Error{Code: errors.NegativePointsNumberError, Message: "hold: cannot hold not positive number of points"}
+	}
+	if ttl <= 0 {
+		return entity.Hold{}, errors.Error{Code: errors.NegativeTTLError, Message: "hold: hold must have positive ttl, id: " + id}
+	}
+	if id == "" {
+		return entity.Hold{}, errors.Error{Code: errors.NotFoundError, Message: "hold: id must be not nil"}
+	}
+	if playerID == "" {
+		return entity.Hold{}, errors.Error{Code: errors.NotFoundError, Message: "hold: player id must be not nil"}
+	}
+	err := g.checkAccount("hold", playerID)
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	err = g.checkSpending("hold", playerID, points)
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	hold := entity.Hold{
+		ID:       id,
+		PlayerID: playerID,
+		Points:   points,
+		Currency: currencyOrDefault(currency),
+		Expires:  time.Now().Add(ttl).UTC().Truncate(time.Second),
+	}
+	err = g.DB.CreateHold(hold)
+	if err != nil {
+		err := errors.Transform(err)
+		if err.Code == errors.NegativePointsNumberError {
+			err.Message = "hold: cannot hold points, player doesn't have enough available points"
+		}
+		return entity.Hold{}, err
+	}
+	return hold, nil
+}
+
+// Capture controlls taking held points from player
+func (g Game) Capture(id string) error {
+	if id == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "capture: id must be not nil"}
+	}
+	hold, err := g.DB.CaptureHold(id)
+	if err != nil {
+		return err
+	}
+	g.publish(balanceChanged(hold.PlayerID, hold.Currency, -hold.Points))
+	return nil
+}
+
+// Release controlls releasing held points, so player can spend them again
+func (g Game) Release(id string) error {
+	if id == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "release: id must be not nil"}
+	}
+	return g.DB.ReleaseHold(id)
+}
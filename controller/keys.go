@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// KeyDB is an interface for database, that used to controll API keys, keys are found by their hashes
+type KeyDB interface {
+	CreateAPIKey(key entity.APIKey) error
+	GetAPIKey(hash string) (entity.APIKey, error)
+	ListAPIKeys() ([]entity.APIKey, error)
+	DeleteAPIKey(id string) error
+}
+
+// Roles are roles, which API keys can have
+var Roles = []string{entity.RoleAdmin, entity.RoleClient, entity.RoleReadOnly}
+
+// Block of sizes of random bytes of generated API key id and key
+const (
+	keyIDSize = 8
+	keySize   = 32
+)
+
+// keyPrefix is prefix of every API key, so keys can be recognized in configs and logs
+const keyPrefix = "tk_"
+
+// CreateAPIKey controlls creating API key with role, created key is returned only here, database keeps its hash
+func (g Game) CreateAPIKey(name, role string) (entity.APIKey, string, error) {
+	if !containsString(Roles, role) {
+		return entity.APIKey{}, "", errors.Error{Code: errors.InvalidRoleError, Message: "create api key: unknown role, name: " + name, Info: role}
+	}
+	id, err := randomHex(keyIDSize)
+	if err != nil {
+		return entity.APIKey{}, "", errors.Error{Code: errors.UnexpectedError, Message: "create api key: cannot generate id", Info: err.Error()}
+	}
+	secret, err := randomHex(keySize)
+	if err != nil {
+		return entity.APIKey{}, "", errors.Error{Code: errors.UnexpectedError, Message: "create api key: cannot generate key", Info: err.Error()}
+	}
+	key := keyPrefix + secret
+	apiKey := entity.APIKey{ID: id, Name: name, Role: role, Hash: HashKey(key), Created: time.Now().UTC().Truncate(time.Second)}
+	err = g.DB.CreateAPIKey(apiKey)
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+	return apiKey, key, nil
+}
+
+// Authenticate returns API key of key, error has unauthorized code, if key is not set or is not valid
+func (g Game) Authenticate(key string) (entity.APIKey, error) {
+	if key == "" {
+		return entity.APIKey{}, errors.Error{Code: errors.UnauthorizedError, Message: "authenticate: api key is not set"}
+	}
+	apiKey, err := g.DB.GetAPIKey(HashKey(key))
+	if err != nil {
+		err := errors.Transform(err)
+		if err.Code == errors.NotFoundError {
+			return entity.APIKey{}, errors.Error{Code: errors.UnauthorizedError, Message: "authenticate: api key is not valid"}
+		}
+		return entity.APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+// APIKeys returns every API key without its hash
+func (g Game) APIKeys() ([]entity.APIKey, error) {
+	keys, err := g.DB.ListAPIKeys()
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].Hash = ""
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey deletes API key, it cannot be used any more
+func (g Game) RevokeAPIKey(id string) error {
+	if id == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "revoke api key: id must be not nil"}
+	}
+	return g.DB.DeleteAPIKey(id)
+}
+
+// HashKey returns hex SHA-256 hash of API key. Keys are long random strings, so fast hash is enough to keep them secret.
+func HashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// randomHex returns hex of size random bytes
+func randomHex(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// LimitDB is an interface for database, that used to controll responsible play limits
+type LimitDB interface {
+	GetLimits(playerID string) (entity.Limits, error)
+	SetLimits(limits entity.Limits) error
+	GetSpent(playerID string, since time.Time) (int, error)
+}
+
+// limitsCoolingOff is period, after which raised or removed limits come into force
+const limitsCoolingOff = 24 * time.Hour
+
+// Limits returns limits of player, which are in force now
+func (g Game) Limits(playerID string) (entity.Limits, error) {
+	if playerID == "" {
+		return entity.Limits{}, errors.Error{Code: errors.NotFoundError, Message: "limits: player id must be not nil"}
+	}
+	return g.limits(playerID, time.Now().UTC())
+}
+
+func (g Game) limits(playerID string, now time.Time) (entity.Limits, error) {
+	limits, err := g.DB.GetLimits(playerID)
+	if err != nil {
+		err := errors.Transform(err)
+		if err.Code != errors.NotFoundError {
+			return entity.Limits{}, err
+		}
+		return entity.Limits{PlayerID: playerID}, nil
+	}
+	return limits.Effective(now), nil
+}
+
+// SetLimits controlls setting daily and weekly spend limits of player, zero limit means no limit.
+// Lowered limits come into force at once, raised or removed ones wait for cooling-off period.
+func (g Game) SetLimits(playerID string, daily, weekly int) (entity.Limits, error) {
+	if playerID == "" {
+		return entity.Limits{}, errors.Error{Code: errors.NotFoundError, Message: "set limits: player id must be not nil"}
+	}
+	if daily < 0 || weekly < 0 {
+		return entity.Limits{}, errors.Error{Code: errors.NegativePointsNumberError, Message: "set limits: limits must be not negative, id: " + playerID}
+	}
+	now := time.Now().UTC()
+	limits, err := g.limits(playerID, now)
+	if err != nil {
+		return entity.Limits{}, err
+	}
+	limits.Pending = nil
+	if raised(limits.Daily, daily) || raised(limits.Weekly, weekly) {
+		limits.Pending = &entity.PendingLimits{Daily: daily, Weekly: weekly, Applies: now.Add(limitsCoolingOff).Truncate(time.Second)}
+	}
+	if !raised(limits.Daily, daily) {
+		limits.Daily = daily
+	}
+	if !raised(limits.Weekly, weekly) {
+		limits.Weekly = weekly
+	}
+	err = g.DB.SetLimits(limits)
+	if err != nil {
+		return entity.Limits{}, err
+	}
+	return limits, nil
+}
+
+// raised returns true, if new limit lets player spend more than current one
+func raised(current, limit int) bool {
+	return current != 0 && (limit == 0 || limit > current)
+}
+
+// SelfExclude controlls self-exclusion of player, which blocks all spending for the period.
+// Exclusion cannot be shortened, so shorter period than remaining one changes nothing.
+func (g Game) SelfExclude(playerID string, period time.Duration) (entity.Limits, error) {
+	if playerID == "" {
+		return entity.Limits{}, errors.Error{Code: errors.NotFoundError, Message: "self exclude: player id must be not nil"}
+	}
+	if period <= 0 {
+		return entity.Limits{}, errors.Error{Code: errors.NegativeTTLError, Message: "self exclude: exclusion must have positive period, id: " + playerID}
+	}
+	now := time.Now().UTC()
+	limits, err := g.limits(playerID, now)
+	if err != nil {
+		return entity.Limits{}, err
+	}
+	until := now.Add(period).Truncate(time.Second)
+	if until.After(limits.ExcludedUntil) {
+		limits.ExcludedUntil = until
+	}
+	err = g.DB.SetLimits(limits)
+	if err != nil {
+		return entity.Limits{}, err
+	}
+	return limits, nil
+}
+
+// checkExclusion returns error of operation op, if player is self-excluded now
+func (g Game) checkExclusion(op, playerID string) error {
+	now := time.Now().UTC()
+	limits, err := g.limits(playerID, now)
+	if err != nil {
+		return err
+	}
+	return excluded(op, limits, now)
+}
+
+func excluded(op string, limits entity.Limits, now time.Time) error {
+	if now.Before(limits.ExcludedUntil) {
+		return errors.Error{Code: errors.SelfExcludedError, Message: op + ": player is self-excluded until " + limits.ExcludedUntil.Format(time.RFC3339) + ", id: " + limits.PlayerID}
+	}
+	return nil
+}
+
+// checkSpending returns error of operation op, if player is self-excluded or spending points would exceed their limits
+func (g Game) checkSpending(op, playerID string, points int) error {
+	now := time.Now().UTC()
+	limits, err := g.limits(playerID, now)
+	if err != nil {
+		return err
+	}
+	err = excluded(op, limits, now)
+	if err != nil {
+		return err
+	}
+	periods := []struct {
+		name   string
+		limit  int
+		length time.Duration
+	}{
+		{"daily", limits.Daily, 24 * time.Hour},
+		{"weekly", limits.Weekly, 7 * 24 * time.Hour},
+	}
+	for _, p := range periods {
+		if p.limit == 0 {
+			continue
+		}
+		spent, err := g.DB.GetSpent(playerID, now.Add(-p.length))
+		if err != nil {
+			return err
+		}
+		if spent+points > p.limit {
+			return errors.Error{Code: errors.LimitExceededError, Message: op + ": player would exceed " + p.name + " limit of " + strconv.Itoa(p.limit) + " points, spent: " + strconv.Itoa(spent) + ", id: " + playerID}
+		}
+	}
+	return nil
+}
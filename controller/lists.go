@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// Block of page sizes of lists
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// ListPlayers controlls listing players page by page, cursor is next cursor of previous page. Players, who have balance,
+// but are not registered, are listed with empty status.
+func (g Game) ListPlayers(filter entity.PlayerFilter, cursor string) (entity.PlayerPage, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = "id"
+	case "id", "name", "created":
+	default:
+		return entity.PlayerPage{}, errors.Error{Code: errors.InvalidFilterError, Message: "list players: cannot sort players by " + filter.Sort}
+	}
+	switch filter.Status {
+	case "", entity.StatusActive, entity.StatusSuspended, entity.StatusClosed:
+	default:
+		return entity.PlayerPage{}, errors.Error{Code: errors.InvalidFilterError, Message: "list players: unknown status " + filter.Status}
+	}
+	limit, err := pageLimit("list players", filter.Limit)
+	if err != nil {
+		return entity.PlayerPage{}, err
+	}
+	filter.After, err = decodeCursor("list players", cursor, filter.Sort)
+	if err != nil {
+		return entity.PlayerPage{}, err
+	}
+	// one more player is fetched to know, if there is next page
+	filter.Limit = limit + 1
+	players, err := g.DB.ListAccounts(filter)
+	if err != nil {
+		return entity.PlayerPage{}, err
+	}
+	page := entity.PlayerPage{Players: players}
+	if len(players) > limit {
+		page.Players = players[:limit]
+		last := page.Players[limit-1]
+		value := last.ID
+		switch filter.Sort {
+		case "name":
+			value = last.Name
+		case "created":
+			value = last.Created.Format(time.RFC3339Nano)
+		}
+		page.Next = encodeCursor(entity.Cursor{Value: value, ID: last.ID})
+	}
+	if page.Players == nil {
+		page.Players = []entity.Account{}
+	}
+	return page, nil
+}
+
+// ListTournaments controlls listing tournaments page by page, cursor is next cursor of previous page
+func (g Game) ListTournaments(filter entity.TourFilter, cursor string) (entity.TourPage, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = "id"
+	case "id", "deposit", "created":
+	default:
+		return entity.TourPage{}, errors.Error{Code: errors.InvalidFilterError, Message: "list tournaments: cannot sort tournaments by " + filter.Sort}
+	}
+	switch filter.Status {
+	case "", "open", "closed":
+	default:
+		return entity.TourPage{}, errors.Error{Code: errors.InvalidFilterError, Message: "list tournaments: unknown status " + filter.Status}
+	}
+	if filter.MinDeposit < 0 || filter.MaxDeposit < 0 || filter.MaxDeposit > 0 && filter.MinDeposit > filter.MaxDeposit {
+		return entity.TourPage{}, errors.Error{Code: errors.InvalidFilterError, Message: "list tournaments: invalid deposit range " +
+			strconv.Itoa(filter.MinDeposit) + "-" + strconv.Itoa(filter.MaxDeposit)}
+	}
+	limit, err := pageLimit("list tournaments", filter.Limit)
+	if err != nil {
+		return entity.TourPage{}, err
+	}
+	filter.After, err = decodeCursor("list tournaments", cursor, filter.Sort)
+	if err != nil {
+		return entity.TourPage{}, err
+	}
+	// one more tournament is fetched to know, if there is next page
+	filter.Limit = limit + 1
+	tours, err := g.DB.ListTournaments(filter)
+	if err != nil {
+		return entity.TourPage{}, err
+	}
+	page := entity.TourPage{Tournaments: tours}
+	if len(tours) > limit {
+		page.Tournaments = tours[:limit]
+		last := page.Tournaments[limit-1]
+		value := last.ID
+		switch filter.Sort {
+		case "deposit":
+			value = strconv.Itoa(last.Deposit)
+		case "created":
+			value = last.Created.Format(time.RFC3339Nano)
+		}
+		page.Next = encodeCursor(entity.Cursor{Value: value, ID: last.ID})
+	}
+	if page.Tournaments == nil {
+		page.Tournaments = []entity.Tournament{}
+	}
+	return page, nil
+}
+
+// pageLimit returns page size, zero limit means default size, too big one is cut to max size
+func pageLimit(op string, limit int) (int, error) {
+	if limit < 0 {
+		return 0, errors.Error{Code: errors.InvalidFilterError, Message: op + ": limit must be not negative"}
+	}
+	if limit == 0 {
+		return defaultPageLimit, nil
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit, nil
+	}
+	return limit, nil
+}
+
+func encodeCursor(c entity.Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns nil cursor for the first page, value of cursor must have type of sort column
+func decodeCursor(op, cursor, sort string) (*entity.Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	var c entity.Cursor
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err == nil {
+		switch sort {
+		case "created":
+			_, err = time.Parse(time.RFC3339Nano, c.Value)
+		case "deposit":
+			_, err = strconv.Atoi(c.Value)
+		}
+	}
+	if err != nil || c.ID == "" {
+		return nil, errors.Error{Code: errors.InvalidFilterError, Message: op + ": invalid cursor " + cursor}
+	}
+	return &c, nil
+}
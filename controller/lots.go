@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"log"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+)
+
+// LotDB is an interface for database, that used to controll dated lots of player points
+type LotDB interface {
+	FundLot(lot entity.Lot) (entity.Player, error)
+	GetExpiringLots(playerID, currency string) ([]entity.Lot, error)
+	ExpireLots() ([]entity.Lot, error)
+}
+
+// SweepLots expires lots past their expiry every interval until stop is closed.
+// Balance change is published for every expired lot, also for lots expired before error.
+func (g Game) SweepLots(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			lots, err := g.DB.ExpireLots()
+			events := make([]entity.Event, len(lots))
+			for i, l := range lots {
+				events[i] = balanceChanged(l.PlayerID, l.Currency, -l.Points)
+			}
+			g.publish(events...)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			if len(lots) > 0 {
+				log.Printf("sweep lots: %v lots expired", len(lots))
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package controller
 
 import entity "github.com/dmitriyomelyusik/Tournament/entity"
+import time "time"
 import mock "github.com/stretchr/testify/mock"
 
 // MockDatabase is an autogenerated mock type for the Database type
@@ -9,34 +10,654 @@ type MockDatabase struct {
 	mock.Mock
 }
 
+// ApplyBatch provides a mock function with given fields: ops
+func (_m *MockDatabase) ApplyBatch(ops []entity.Operation) (int, error) {
+	ret := _m.Called(ops)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]entity.Operation) int); ok {
+		r0 = rf(ops)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]entity.Operation) error); ok {
+		r1 = rf(ops)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApplyEach provides a mock function with given fields: ops
+func (_m *MockDatabase) ApplyEach(ops []entity.Operation) ([]error, error) {
+	ret := _m.Called(ops)
+
+	var r0 []error
+	if rf, ok := ret.Get(0).(func([]entity.Operation) []error); ok {
+		r0 = rf(ops)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]entity.Operation) error); ok {
+		r1 = rf(ops)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CaptureHold provides a mock function with given fields: id
+func (_m *MockDatabase) CaptureHold(id string) (entity.Hold, error) {
+	ret := _m.Called(id)
+
+	var r0 entity.Hold
+	if rf, ok := ret.Get(0).(func(string) entity.Hold); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Hold)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimDeliveries provides a mock function with given fields: now, lease, limit
+func (_m *MockDatabase) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.Delivery, error) {
+	ret := _m.Called(now, lease, limit)
+
+	var r0 []entity.Delivery
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) []entity.Delivery); ok {
+		r0 = rf(now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time, time.Duration, int) error); ok {
+		r1 = rf(now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CloseAccount provides a mock function with given fields: id, withdraw
+func (_m *MockDatabase) CloseAccount(id string, withdraw bool) (entity.Closure, error) {
+	ret := _m.Called(id, withdraw)
+
+	var r0 entity.Closure
+	if rf, ok := ret.Get(0).(func(string, bool) entity.Closure); ok {
+		r0 = rf(id, withdraw)
+	} else {
+		r0 = ret.Get(0).(entity.Closure)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(id, withdraw)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CloseTournament provides a mock function with given fields: id
 func (_m *MockDatabase) CloseTournament(id string) error {
 	ret := _m.Called(id)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteDelivery provides a mock function with given fields: id
+func (_m *MockDatabase) CompleteDelivery(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateAPIKey provides a mock function with given fields: key
+func (_m *MockDatabase) CreateAPIKey(key entity.APIKey) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.APIKey) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateAccount provides a mock function with given fields: account
+func (_m *MockDatabase) CreateAccount(account entity.Account) error {
+	ret := _m.Called(account)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Account) error); ok {
+		r0 = rf(account)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateHold provides a mock function with given fields: hold
+func (_m *MockDatabase) CreateHold(hold entity.Hold) error {
+	ret := _m.Called(hold)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Hold) error); ok {
+		r0 = rf(hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePlayer provides a mock function with given fields: id, currency, points
+func (_m *MockDatabase) CreatePlayer(id string, currency string, points int) (entity.Player, error) {
+	ret := _m.Called(id, currency, points)
+
+	var r0 entity.Player
+	if rf, ok := ret.Get(0).(func(string, string, int) entity.Player); ok {
+		r0 = rf(id, currency, points)
+	} else {
+		r0 = ret.Get(0).(entity.Player)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(id, currency, points)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePromo provides a mock function with given fields: promo
+func (_m *MockDatabase) CreatePromo(promo entity.Promo) error {
+	ret := _m.Called(promo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Promo) error); ok {
+		r0 = rf(promo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSatellite provides a mock function with given fields: id, deposit, targetID, seats
+func (_m *MockDatabase) CreateSatellite(id string, deposit int, targetID string, seats int) error {
+	ret := _m.Called(id, deposit, targetID, seats)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, string, int) error); ok {
+		r0 = rf(id, deposit, targetID, seats)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTeam provides a mock function with given fields: team
+func (_m *MockDatabase) CreateTeam(team entity.Team) error {
+	ret := _m.Called(team)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Team) error); ok {
+		r0 = rf(team)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTeamTournament provides a mock function with given fields: id, currency, deposit
+func (_m *MockDatabase) CreateTeamTournament(id string, currency string, deposit int) error {
+	ret := _m.Called(id, currency, deposit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(id, currency, deposit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTournament provides a mock function with given fields: id, currency, deposit, maxEntries
+func (_m *MockDatabase) CreateTournament(id string, currency string, deposit int, maxEntries int) error {
+	ret := _m.Called(id, currency, deposit, maxEntries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int, int) error); ok {
+		r0 = rf(id, currency, deposit, maxEntries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateWebhook provides a mock function with given fields: webhook
+func (_m *MockDatabase) CreateWebhook(webhook entity.Webhook) error {
+	ret := _m.Called(webhook)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Webhook) error); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAPIKey provides a mock function with given fields: id
+func (_m *MockDatabase) DeleteAPIKey(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhook provides a mock function with given fields: id
+func (_m *MockDatabase) DeleteWebhook(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireLots provides a mock function with given fields:
+func (_m *MockDatabase) ExpireLots() ([]entity.Lot, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Lot
+	if rf, ok := ret.Get(0).(func() []entity.Lot); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Lot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FailDelivery provides a mock function with given fields: delivery
+func (_m *MockDatabase) FailDelivery(delivery entity.Delivery) error {
+	ret := _m.Called(delivery)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Delivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FundLot provides a mock function with given fields: lot
+func (_m *MockDatabase) FundLot(lot entity.Lot) (entity.Player, error) {
+	ret := _m.Called(lot)
+
+	var r0 entity.Player
+	if rf, ok := ret.Get(0).(func(entity.Lot) entity.Player); ok {
+		r0 = rf(lot)
+	} else {
+		r0 = ret.Get(0).(entity.Player)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entity.Lot) error); ok {
+		r1 = rf(lot)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKey provides a mock function with given fields: hash
+func (_m *MockDatabase) GetAPIKey(hash string) (entity.APIKey, error) {
+	ret := _m.Called(hash)
+
+	var r0 entity.APIKey
+	if rf, ok := ret.Get(0).(func(string) entity.APIKey); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(entity.APIKey)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccount provides a mock function with given fields: id
+func (_m *MockDatabase) GetAccount(id string) (entity.Account, error) {
+	ret := _m.Called(id)
+
+	var r0 entity.Account
+	if rf, ok := ret.Get(0).(func(string) entity.Account); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalances provides a mock function with given fields: id
+func (_m *MockDatabase) GetBalances(id string) ([]entity.Player, error) {
+	ret := _m.Called(id)
+
+	var r0 []entity.Player
+	if rf, ok := ret.Get(0).(func(string) []entity.Player); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Player)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrency provides a mock function with given fields: id
+func (_m *MockDatabase) GetCurrency(id string) (string, error) {
+	ret := _m.Called(id)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeposit provides a mock function with given fields: id
+func (_m *MockDatabase) GetDeposit(id string) (int, error) {
+	ret := _m.Called(id)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEntries provides a mock function with given fields: id
+func (_m *MockDatabase) GetEntries(id string) ([]entity.Entry, error) {
+	ret := _m.Called(id)
+
+	var r0 []entity.Entry
+	if rf, ok := ret.Get(0).(func(string) []entity.Entry); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Entry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpiringLots provides a mock function with given fields: playerID, currency
+func (_m *MockDatabase) GetExpiringLots(playerID string, currency string) ([]entity.Lot, error) {
+	ret := _m.Called(playerID, currency)
+
+	var r0 []entity.Lot
+	if rf, ok := ret.Get(0).(func(string, string) []entity.Lot); ok {
+		r0 = rf(playerID, currency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Lot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(playerID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistory provides a mock function with given fields: playerID
+func (_m *MockDatabase) GetHistory(playerID string) ([]entity.Participation, error) {
+	ret := _m.Called(playerID)
+
+	var r0 []entity.Participation
+	if rf, ok := ret.Get(0).(func(string) []entity.Participation); ok {
+		r0 = rf(playerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Participation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHolds provides a mock function with given fields: playerID, currency
+func (_m *MockDatabase) GetHolds(playerID string, currency string) ([]entity.Hold, error) {
+	ret := _m.Called(playerID, currency)
+
+	var r0 []entity.Hold
+	if rf, ok := ret.Get(0).(func(string, string) []entity.Hold); ok {
+		r0 = rf(playerID, currency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Hold)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(playerID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLimits provides a mock function with given fields: playerID
+func (_m *MockDatabase) GetLimits(playerID string) (entity.Limits, error) {
+	ret := _m.Called(playerID)
+
+	var r0 entity.Limits
+	if rf, ok := ret.Get(0).(func(string) entity.Limits); ok {
+		r0 = rf(playerID)
+	} else {
+		r0 = ret.Get(0).(entity.Limits)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMaxEntries provides a mock function with given fields: id
+func (_m *MockDatabase) GetMaxEntries(id string) (int, error) {
+	ret := _m.Called(id)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetParticipants provides a mock function with given fields: id
+func (_m *MockDatabase) GetParticipants(id string) ([]string, error) {
+	ret := _m.Called(id)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
 		r0 = rf(id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// CreatePlayer provides a mock function with given fields: id, points
-func (_m *MockDatabase) CreatePlayer(id string, points int) (entity.Player, error) {
-	ret := _m.Called(id, points)
+// GetPlayer provides a mock function with given fields: id, currency
+func (_m *MockDatabase) GetPlayer(id string, currency string) (entity.Player, error) {
+	ret := _m.Called(id, currency)
 
 	var r0 entity.Player
-	if rf, ok := ret.Get(0).(func(string, int) entity.Player); ok {
-		r0 = rf(id, points)
+	if rf, ok := ret.Get(0).(func(string, string) entity.Player); ok {
+		r0 = rf(id, currency)
 	} else {
 		r0 = ret.Get(0).(entity.Player)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(id, points)
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(id, currency)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -44,27 +665,76 @@ func (_m *MockDatabase) CreatePlayer(id string, points int) (entity.Player, erro
 	return r0, r1
 }
 
-// CreateTournament provides a mock function with given fields: id, deposit
-func (_m *MockDatabase) CreateTournament(id string, deposit int) error {
-	ret := _m.Called(id, deposit)
+// GetSatellite provides a mock function with given fields: id
+func (_m *MockDatabase) GetSatellite(id string) (entity.Satellite, error) {
+	ret := _m.Called(id)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(id, deposit)
+	var r0 entity.Satellite
+	if rf, ok := ret.Get(0).(func(string) entity.Satellite); ok {
+		r0 = rf(id)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(entity.Satellite)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetParticipants provides a mock function with given fields: id
-func (_m *MockDatabase) GetParticipants(id string) ([]string, error) {
+// GetSpent provides a mock function with given fields: playerID, since
+func (_m *MockDatabase) GetSpent(playerID string, since time.Time) (int, error) {
+	ret := _m.Called(playerID, since)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, time.Time) int); ok {
+		r0 = rf(playerID, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(playerID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeam provides a mock function with given fields: id
+func (_m *MockDatabase) GetTeam(id string) (entity.Team, error) {
 	ret := _m.Called(id)
 
+	var r0 entity.Team
+	if rf, ok := ret.Get(0).(func(string) entity.Team); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Team)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeams provides a mock function with given fields: tourID
+func (_m *MockDatabase) GetTeams(tourID string) ([]string, error) {
+	ret := _m.Called(tourID)
+
 	var r0 []string
 	if rf, ok := ret.Get(0).(func(string) []string); ok {
-		r0 = rf(id)
+		r0 = rf(tourID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -73,7 +743,7 @@ func (_m *MockDatabase) GetParticipants(id string) ([]string, error) {
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+		r1 = rf(tourID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -81,15 +751,15 @@ func (_m *MockDatabase) GetParticipants(id string) ([]string, error) {
 	return r0, r1
 }
 
-// GetPlayer provides a mock function with given fields: id
-func (_m *MockDatabase) GetPlayer(id string) (entity.Player, error) {
+// GetTournament provides a mock function with given fields: id
+func (_m *MockDatabase) GetTournament(id string) (entity.Tournament, error) {
 	ret := _m.Called(id)
 
-	var r0 entity.Player
-	if rf, ok := ret.Get(0).(func(string) entity.Player); ok {
+	var r0 entity.Tournament
+	if rf, ok := ret.Get(0).(func(string) entity.Tournament); ok {
 		r0 = rf(id)
 	} else {
-		r0 = ret.Get(0).(entity.Player)
+		r0 = ret.Get(0).(entity.Tournament)
 	}
 
 	var r1 error
@@ -144,6 +814,247 @@ func (_m *MockDatabase) GetWinner(id string) (entity.Winners, error) {
 	return r0, r1
 }
 
+// IsTeamTournament provides a mock function with given fields: id
+func (_m *MockDatabase) IsTeamTournament(id string) (bool, error) {
+	ret := _m.Called(id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAPIKeys provides a mock function with given fields:
+func (_m *MockDatabase) ListAPIKeys() ([]entity.APIKey, error) {
+	ret := _m.Called()
+
+	var r0 []entity.APIKey
+	if rf, ok := ret.Get(0).(func() []entity.APIKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.APIKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAccounts provides a mock function with given fields: filter
+func (_m *MockDatabase) ListAccounts(filter entity.PlayerFilter) ([]entity.Account, error) {
+	ret := _m.Called(filter)
+
+	var r0 []entity.Account
+	if rf, ok := ret.Get(0).(func(entity.PlayerFilter) []entity.Account); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entity.PlayerFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeadDeliveries provides a mock function with given fields:
+func (_m *MockDatabase) ListDeadDeliveries() ([]entity.Delivery, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Delivery
+	if rf, ok := ret.Get(0).(func() []entity.Delivery); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTournaments provides a mock function with given fields: filter
+func (_m *MockDatabase) ListTournaments(filter entity.TourFilter) ([]entity.Tournament, error) {
+	ret := _m.Called(filter)
+
+	var r0 []entity.Tournament
+	if rf, ok := ret.Get(0).(func(entity.TourFilter) []entity.Tournament); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Tournament)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entity.TourFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhooks provides a mock function with given fields:
+func (_m *MockDatabase) ListWebhooks() ([]entity.Webhook, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Webhook
+	if rf, ok := ret.Get(0).(func() []entity.Webhook); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeemPromo provides a mock function with given fields: code, playerID, now
+func (_m *MockDatabase) RedeemPromo(code string, playerID string, now time.Time) (entity.Redemption, error) {
+	ret := _m.Called(code, playerID, now)
+
+	var r0 entity.Redemption
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) entity.Redemption); ok {
+		r0 = rf(code, playerID, now)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = rf(code, playerID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseHold provides a mock function with given fields: id
+func (_m *MockDatabase) ReleaseHold(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RetryDelivery provides a mock function with given fields: id
+func (_m *MockDatabase) RetryDelivery(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetAccountStatus provides a mock function with given fields: id, status
+func (_m *MockDatabase) SetAccountStatus(id string, status string) error {
+	ret := _m.Called(id, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLimits provides a mock function with given fields: limits
+func (_m *MockDatabase) SetLimits(limits entity.Limits) error {
+	ret := _m.Called(limits)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Limits) error); ok {
+		r0 = rf(limits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSatelliteWinners provides a mock function with given fields: id, ranking, seats
+func (_m *MockDatabase) SetSatelliteWinners(id string, ranking []string, seats int) error {
+	ret := _m.Called(id, ranking, seats)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string, int) error); ok {
+		r0 = rf(id, ranking, seats)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTeamWinner provides a mock function with given fields: tourID, teamID
+func (_m *MockDatabase) SetTeamWinner(tourID string, teamID string) error {
+	ret := _m.Called(tourID, teamID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(tourID, teamID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetTournamentWinner provides a mock function with given fields: id, winner
 func (_m *MockDatabase) SetTournamentWinner(id string, winner entity.Winner) error {
 	ret := _m.Called(id, winner)
@@ -158,13 +1069,27 @@ func (_m *MockDatabase) SetTournamentWinner(id string, winner entity.Winner) err
 	return r0
 }
 
-// UpdatePlayer provides a mock function with given fields: id, dif
-func (_m *MockDatabase) UpdatePlayer(id string, dif int) error {
-	ret := _m.Called(id, dif)
+// TransferPoints provides a mock function with given fields: from, to, currency, points
+func (_m *MockDatabase) TransferPoints(from string, to string, currency string, points int) error {
+	ret := _m.Called(from, to, currency, points)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, int) error); ok {
+		r0 = rf(from, to, currency, points)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePlayer provides a mock function with given fields: id, currency, dif
+func (_m *MockDatabase) UpdatePlayer(id string, currency string, dif int) error {
+	ret := _m.Called(id, currency, dif)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(id, dif)
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(id, currency, dif)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -185,3 +1110,17 @@ func (_m *MockDatabase) UpdateTourAndPlayer(tourID string, playerID string) erro
 
 	return r0
 }
+
+// UpdateTourAndTeam provides a mock function with given fields: tourID, teamID, captainPays
+func (_m *MockDatabase) UpdateTourAndTeam(tourID string, teamID string, captainPays bool) error {
+	ret := _m.Called(tourID, teamID, captainPays)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool) error); ok {
+		r0 = rf(tourID, teamID, captainPays)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
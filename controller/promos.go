@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// PromoDB is an interface for database, that used to controll promotion codes
+type PromoDB interface {
+	CreatePromo(promo entity.Promo) error
+	RedeemPromo(code, playerID string, now time.Time) (entity.Redemption, error)
+}
+
+// CreatePromo controlls creating promotion code, which grants points, ticket into tournament or both.
+// Every player can redeem code once, if max per player is not set, and code is valid from now, if valid from is not set.
+func (g Game) CreatePromo(promo entity.Promo) (entity.Promo, error) {
+	if promo.Code == "" {
+		return entity.Promo{}, errors.Error{Code: errors.NotFoundError, Message: "create promo: code must be not nil"}
+	}
+	if promo.Points < 0 {
+		return entity.Promo{}, errors.Error{Code: errors.NegativePointsNumberError, Message: "create promo: cannot grant negative number of points, code: " + promo.Code}
+	}
+	if promo.Points == 0 && promo.TournamentID == "" {
+		return entity.Promo{}, errors.Error{Code: errors.InvalidPromoError, Message: "create promo: promo must grant points or ticket, code: " + promo.Code}
+	}
+	if promo.MaxUses < 0 || promo.MaxPerPlayer < 0 {
+		return entity.Promo{}, errors.Error{Code: errors.InvalidPromoError, Message: "create promo: usage limits must be not negative, code: " + promo.Code}
+	}
+	if promo.MaxPerPlayer == 0 {
+		promo.MaxPerPlayer = 1
+	}
+	if promo.ValidFrom.IsZero() {
+		promo.ValidFrom = time.Now().UTC().Truncate(time.Second)
+	}
+	if !promo.ValidTo.IsZero() && !promo.ValidTo.After(promo.ValidFrom) {
+		return entity.Promo{}, errors.Error{Code: errors.InvalidPromoError, Message: "create promo: promo must be valid to time after valid from, code: " + promo.Code}
+	}
+	promo.Currency = currencyOrDefault(promo.Currency)
+	if promo.TournamentID != "" {
+		isTeam, err := g.DB.IsTeamTournament(promo.TournamentID)
+		if err != nil {
+			return entity.Promo{}, err
+		}
+		if isTeam {
+			return entity.Promo{}, errors.Error{Code: errors.TeamTournamentError, Message: "create promo: cannot grant ticket into team tournament, id: " + promo.TournamentID}
+		}
+	}
+	promo.Uses = 0
+	err := g.DB.CreatePromo(promo)
+	if err != nil {
+		return entity.Promo{}, err
+	}
+	return promo, nil
+}
+
+// Redeem controlls redeeming promotion code by player. Reward is granted in one operation with redemption,
+// if player has already used code as many times as they can, their last redemption is returned without reward.
+func (g Game) Redeem(code, playerID string) (entity.Redemption, error) {
+	if code == "" {
+		return entity.Redemption{}, errors.Error{Code: errors.NotFoundError, Message: "redeem: code must be not nil"}
+	}
+	if playerID == "" {
+		return entity.Redemption{}, errors.Error{Code: errors.NotFoundError, Message: "redeem: player id must be not nil"}
+	}
+	err := g.checkAccount("redeem", playerID)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+	now := time.Now().UTC()
+	red, err := g.DB.RedeemPromo(code, playerID, now)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+	// repeated redemption returns the last one, which has not granted reward now
+	if red.Points > 0 && red.Redeemed.Equal(now) {
+		g.publish(balanceChanged(playerID, red.Currency, red.Points))
+	}
+	return red, nil
+}
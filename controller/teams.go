@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// TeamDB is an interface for database, that used to controll team activity methods
+type TeamDB interface {
+	CreateTeam(team entity.Team) error
+	GetTeam(id string) (entity.Team, error)
+	CreateTeamTournament(id, currency string, deposit int) error
+	IsTeamTournament(id string) (bool, error)
+	GetTeams(tourID string) ([]string, error)
+	UpdateTourAndTeam(tourID, teamID string, captainPays bool) error
+	SetTeamWinner(tourID, teamID string) error
+}
+
+// CreateTeam controlls creating team of players. Deposits and prizes are split between members
+// by their shares in percents, if shares are not set, they are split equally.
+func (g Game) CreateTeam(id, captain string, members []string, shares []int) (entity.Team, error) {
+	if id == "" {
+		return entity.Team{}, errors.Error{Code: errors.NotFoundError, Message: "create team: id must be not nil"}
+	}
+	if len(members) == 0 {
+		return entity.Team{}, errors.Error{Code: errors.NotFoundError, Message: "create team: team must have members, id: " + id}
+	}
+	if captain == "" {
+		captain = members[0]
+	}
+	if len(shares) == 0 {
+		shares = equalShares(len(members))
+	}
+	if len(shares) != len(members) {
+		return entity.Team{}, errors.Error{Code: errors.InvalidSplitError, Message: "create team: every member must have share, id: " + id}
+	}
+	team := entity.Team{ID: id, Captain: captain}
+	var sum int
+	hasCaptain := false
+	seen := make(map[string]bool, len(members))
+	for i, m := range members {
+		if shares[i] < 0 {
+			return entity.Team{}, errors.Error{Code: errors.InvalidSplitError, Message: "create team: share cannot be negative, playerID: " + m}
+		}
+		if seen[m] {
+			return entity.Team{}, errors.Error{Code: errors.DuplicatedIDError, Message: "create team: cannot add player to team twice, playerID: " + m}
+		}
+		seen[m] = true
+		hasCaptain = hasCaptain || m == captain
+		sum += shares[i]
+		team.Members = append(team.Members, entity.TeamMember{PlayerID: m, Share: shares[i]})
+	}
+	if !hasCaptain {
+		return entity.Team{}, errors.Error{Code: errors.NotFoundError, Message: "create team: captain must be team member, captain: " + captain}
+	}
+	if sum != 100 {
+		return entity.Team{}, errors.Error{Code: errors.InvalidSplitError, Message: "create team: sum of shares must be 100, id: " + id}
+	}
+	for _, m := range members {
+		_, err := g.DB.GetBalances(m)
+		if err != nil {
+			return entity.Team{}, err
+		}
+	}
+	err := g.DB.CreateTeam(team)
+	if err != nil {
+		return entity.Team{}, err
+	}
+	return team, nil
+}
+
+func equalShares(n int) []int {
+	shares := make([]int, n)
+	for i := range shares {
+		shares[i] = 100 / n
+		if i < 100%n {
+			shares[i]++
+		}
+	}
+	return shares
+}
+
+// AnnounceTeamTournament controlls announcing tournament with deposit in currency, which only teams can join
+func (g Game) AnnounceTeamTournament(id, currency string, deposit int) error {
+	if deposit <= 0 {
+		return errors.Error{Code: errors.NegativeDepositError, Message: "announce team tournament: cannot create tournament with not positive deposite, id: " + id}
+	}
+	if id == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "announce team tournament: id must be not nil"}
+	}
+	return g.DB.CreateTeamTournament(id, currencyOrDefault(currency), deposit)
+}
+
+// JoinTeam controlls joining team to tournament. Deposit is split between team members
+// or is paid by team captain only, part of every payer is checked against their spend limits.
+func (g Game) JoinTeam(tourID, teamID string, captainPays bool) error {
+	if tourID == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "join team: tournament id must be not nil"}
+	}
+	if teamID == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "join team: team id must be not nil"}
+	}
+	isOpen, err := g.DB.GetTournamentState(tourID)
+	if err != nil {
+		return err
+	}
+	if !isOpen {
+		return errors.Error{Code: errors.ClosedTournamentError, Message: "join team: cannot join to closed tournament, tourID: " + tourID}
+	}
+	isTeam, err := g.DB.IsTeamTournament(tourID)
+	if err != nil {
+		return err
+	}
+	if !isTeam {
+		return errors.Error{Code: errors.TeamTournamentError, Message: "join team: teams cannot join not team tournament, tourID: " + tourID}
+	}
+	team, err := g.DB.GetTeam(teamID)
+	if err != nil {
+		return err
+	}
+	teams, err := g.DB.GetTeams(tourID)
+	if err != nil {
+		return err
+	}
+	deposit, err := g.DB.GetDeposit(tourID)
+	if err != nil {
+		return err
+	}
+	shares := team.Split(deposit)
+	members := make(map[string]bool, len(team.Members))
+	for i, m := range team.Members {
+		err = g.checkAccount("join team", m.PlayerID)
+		if err != nil {
+			return err
+		}
+		share := shares[i]
+		if captainPays {
+			share = 0
+			if m.PlayerID == team.Captain {
+				share = deposit
+			}
+		}
+		err = g.checkSpending("join team", m.PlayerID, share)
+		if err != nil {
+			return err
+		}
+		members[m.PlayerID] = true
+	}
+	for _, id := range teams {
+		if id == teamID {
+			return errors.Error{Code: errors.DuplicatedIDError, Message: "join team: cannot join to one tournament twice, teamID: " + teamID}
+		}
+		other, err := g.DB.GetTeam(id)
+		if err != nil {
+			return err
+		}
+		for _, m := range other.Members {
+			if members[m.PlayerID] {
+				return errors.Error{Code: errors.DuplicatedIDError, Message: "join team: player has already joined tournament in other team, playerID: " + m.PlayerID}
+			}
+		}
+	}
+	err = g.DB.UpdateTourAndTeam(tourID, teamID, captainPays)
+	if err != nil {
+		return err
+	}
+	events := make([]entity.Event, len(team.Members))
+	for i, m := range team.Members {
+		events[i] = entity.Event{Type: entity.EventPlayerJoined, TournamentID: tourID, PlayerID: m.PlayerID, TeamID: teamID}
+	}
+	g.publish(events...)
+	return nil
+}
+
+func resultTeams(g Game, tourID string) error {
+	teams, err := g.DB.GetTeams(tourID)
+	if err != nil {
+		return err
+	}
+	if len(teams) == 0 {
+		return errors.Error{Code: errors.NoneParticipantsError, Message: "cannot choose winner: tournament has no teams, id: " + tourID}
+	}
+	rand.Seed(time.Now().UnixNano())
+	return g.DB.SetTeamWinner(tourID, teams[rand.Intn(len(teams))])
+}
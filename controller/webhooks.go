@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// WebhookDB is an interface for database, that used to controll webhooks and their outbox.
+// Database writes deliveries of events to webhooks in the same transaction as the change.
+type WebhookDB interface {
+	CreateWebhook(webhook entity.Webhook) error
+	ListWebhooks() ([]entity.Webhook, error)
+	DeleteWebhook(id string) error
+	ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.Delivery, error)
+	CompleteDelivery(id string) error
+	FailDelivery(delivery entity.Delivery) error
+	ListDeadDeliveries() ([]entity.Delivery, error)
+	RetryDelivery(id string) error
+}
+
+// WebhookEvents are event types, which are written into outbox, so webhooks can subscribe to them
+var WebhookEvents = []string{entity.EventWinnerChosen, entity.EventBalanceChanged}
+
+// secretSize is number of random bytes of generated webhook secret
+const secretSize = 32
+
+// CreateWebhook controlls creating webhook, which gets events of types. Secret is generated, if it is not set.
+func (g Game) CreateWebhook(webhook entity.Webhook) (entity.Webhook, error) {
+	if webhook.ID == "" {
+		return entity.Webhook{}, errors.Error{Code: errors.NotFoundError, Message: "create webhook: id must be not nil"}
+	}
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return entity.Webhook{}, errors.Error{Code: errors.InvalidWebhookError, Message: "create webhook: url must be absolute http or https url, id: " + webhook.ID, Info: webhook.URL}
+	}
+	if len(webhook.Events) == 0 {
+		return entity.Webhook{}, errors.Error{Code: errors.InvalidWebhookError, Message: "create webhook: webhook must get events, id: " + webhook.ID}
+	}
+	var types []string
+	for _, t := range webhook.Events {
+		if !containsString(WebhookEvents, t) {
+			return entity.Webhook{}, errors.Error{Code: errors.InvalidWebhookError, Message: "create webhook: unknown event type, id: " + webhook.ID, Info: t}
+		}
+		if !containsString(types, t) {
+			types = append(types, t)
+		}
+	}
+	webhook.Events = types
+	if webhook.Secret == "" {
+		secret := make([]byte, secretSize)
+		_, err = rand.Read(secret)
+		if err != nil {
+			return entity.Webhook{}, errors.Error{Code: errors.UnexpectedError, Message: "create webhook: cannot generate secret", Info: err.Error()}
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+	webhook.Created = time.Now().UTC().Truncate(time.Second)
+	err = g.DB.CreateWebhook(webhook)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Webhooks returns every webhook without its secret
+func (g Game) Webhooks() ([]entity.Webhook, error) {
+	webhooks, err := g.DB.ListWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook controlls deleting webhook with its deliveries
+func (g Game) DeleteWebhook(id string) error {
+	if id == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "delete webhook: id must be not nil"}
+	}
+	return g.DB.DeleteWebhook(id)
+}
+
+// DeadDeliveries returns dead-letter list, deliveries, which have failed every attempt
+func (g Game) DeadDeliveries() ([]entity.Delivery, error) {
+	return g.DB.ListDeadDeliveries()
+}
+
+// RetryDelivery controlls returning dead delivery into outbox, it is attempted again from the first attempt
+func (g Game) RetryDelivery(id string) error {
+	if id == "" {
+		return errors.Error{Code: errors.NotFoundError, Message: "retry delivery: id must be not nil"}
+	}
+	return g.DB.RetryDelivery(id)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
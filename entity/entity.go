@@ -1,10 +1,124 @@
 // Package entity contains all entities, that used in application
 package entity
 
-// Player is struct for players perfomance
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// DefaultCurrency is currency of points, which are used, when currency is not set
+const DefaultCurrency = "points"
+
+// Player is struct for players perfomance, player has separate points in every currency
 type Player struct {
-	ID     string `json:"id" bson:"_id"`
-	Points int    `json:"points" bson:"points"`
+	ID       string `json:"id" bson:"_id"`
+	Points   int    `json:"points" bson:"points"`
+	Currency string `json:"currency" bson:"currency,omitempty"`
+}
+
+// Balance is player balance, available points are points, which are not held
+type Balance struct {
+	ID        string `json:"id" bson:"_id"`
+	Points    int    `json:"points" bson:"points"`
+	Currency  string `json:"currency" bson:"currency"`
+	Available int    `json:"available" bson:"available"`
+	Holds     []Hold `json:"holds,omitempty" bson:"holds,omitempty"`
+	Expiring  []Lot  `json:"expiring,omitempty" bson:"expiring,omitempty"`
+}
+
+// Block of account statuses
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusClosed    = "closed"
+)
+
+// Account is registered player profile, only active account can fund, spend points and join tournaments
+type Account struct {
+	ID       string            `json:"id" bson:"_id"`
+	Name     string            `json:"name" bson:"name"`
+	Created  time.Time         `json:"created" bson:"created"`
+	Metadata map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
+	Status   string            `json:"status" bson:"status"`
+}
+
+// Closure is result of closing account, player is withdrawn from open tournaments and paid out their balances
+type Closure struct {
+	PlayerID  string   `json:"playerId" bson:"playerId"`
+	Withdrawn []string `json:"withdrawn,omitempty" bson:"withdrawn,omitempty"`
+	Payouts   []Player `json:"payouts" bson:"payouts"`
+}
+
+// Hold is reservation of player points, which can be captured or released until it expires
+type Hold struct {
+	ID       string    `json:"id" bson:"_id"`
+	PlayerID string    `json:"playerId" bson:"playerId"`
+	Points   int       `json:"points" bson:"points"`
+	Currency string    `json:"currency" bson:"currency"`
+	Expires  time.Time `json:"expires" bson:"expires"`
+}
+
+// Lot is dated part of player points, points are spent from the oldest lots first.
+// Lot with zero expiry time never expires.
+type Lot struct {
+	ID       string    `json:"id" bson:"_id"`
+	PlayerID string    `json:"playerId" bson:"playerId"`
+	Currency string    `json:"currency" bson:"currency"`
+	Points   int       `json:"points" bson:"points"`
+	Created  time.Time `json:"created" bson:"created"`
+	Expires  time.Time `json:"expires" bson:"expires"`
+}
+
+// Promo is promotion code, which grants points in currency or ticket into tournament.
+// Zero max uses means unlimited code, zero valid to means code, which never expires.
+type Promo struct {
+	Code           string    `json:"code" bson:"_id"`
+	Points         int       `json:"points" bson:"points"`
+	Currency       string    `json:"currency" bson:"currency"`
+	TournamentID   string    `json:"tournamentId,omitempty" bson:"tournamentId,omitempty"`
+	MaxUses        int       `json:"maxUses" bson:"maxUses"`
+	MaxPerPlayer   int       `json:"maxPerPlayer" bson:"maxPerPlayer"`
+	ValidFrom      time.Time `json:"validFrom" bson:"validFrom"`
+	ValidTo        time.Time `json:"validTo" bson:"validTo"`
+	NewPlayersOnly bool      `json:"newPlayersOnly" bson:"newPlayersOnly"`
+	Uses           int       `json:"uses" bson:"uses"`
+}
+
+// Redemption is usage of promo code by player, use is numbered from 1 for every player
+type Redemption struct {
+	Code         string    `json:"code" bson:"code"`
+	PlayerID     string    `json:"playerId" bson:"playerId"`
+	Use          int       `json:"use" bson:"use"`
+	Points       int       `json:"points" bson:"points"`
+	Currency     string    `json:"currency" bson:"currency"`
+	TournamentID string    `json:"tournamentId,omitempty" bson:"tournamentId,omitempty"`
+	Redeemed     time.Time `json:"redeemed" bson:"redeemed"`
+}
+
+// Limits are responsible play settings of player. Daily and weekly limits cap points spent on takes, captured holds
+// and tournament deposits during last day and week, zero limit means no limit. Spending is blocked until excluded until time.
+type Limits struct {
+	PlayerID      string         `json:"playerId" bson:"_id"`
+	Daily         int            `json:"daily" bson:"daily"`
+	Weekly        int            `json:"weekly" bson:"weekly"`
+	Pending       *PendingLimits `json:"pending,omitempty" bson:"pending,omitempty"`
+	ExcludedUntil time.Time      `json:"excludedUntil" bson:"excludedUntil"`
+}
+
+// PendingLimits are raised limits, which are waiting for the end of cooling-off period
+type PendingLimits struct {
+	Daily   int       `json:"daily" bson:"daily"`
+	Weekly  int       `json:"weekly" bson:"weekly"`
+	Applies time.Time `json:"applies" bson:"applies"`
+}
+
+// Effective returns limits, which are in force at the time, pending limits are applied after their cooling-off period
+func (l Limits) Effective(now time.Time) Limits {
+	if l.Pending != nil && !now.Before(l.Pending.Applies) {
+		l.Daily, l.Weekly, l.Pending = l.Pending.Daily, l.Pending.Weekly, nil
+	}
+	return l
 }
 
 // Winner is player, who won tournament
@@ -12,6 +126,9 @@ type Winner struct {
 	ID     string `json:"id" bson:"_id"`
 	Points int    `json:"points" bson:"points"`
 	Prize  int    `json:"prize" bson:"prize"`
+	Seat   string `json:"seat,omitempty" bson:"seat,omitempty"`
+	Entry  int    `json:"entry,omitempty" bson:"entry,omitempty"`
+	Team   string `json:"team,omitempty" bson:"team,omitempty"`
 }
 
 // Winners contains every winner from tournaments
@@ -19,12 +136,235 @@ type Winners struct {
 	Winners []Winner `json:"winners" bson:"winners"`
 }
 
+// Participation describes player participation in tournament: points paid as deposits, placing and prize won.
+// Placing is zero, while tournament has no results or player has not placed.
+type Participation struct {
+	TournamentID string    `json:"tournamentId" bson:"tournamentId"`
+	PlayerID     string    `json:"playerId" bson:"playerId"`
+	Currency     string    `json:"currency" bson:"currency"`
+	IsOpen       bool      `json:"isOpen" bson:"-"`
+	Paid         int       `json:"paid" bson:"paid"`
+	Placing      int       `json:"placing,omitempty" bson:"placing,omitempty"`
+	Prize        int       `json:"prize" bson:"prize"`
+	Joined       time.Time `json:"joined" bson:"joined"`
+}
+
+// History is list of tournaments, which player has participated in, the latest ones first
+type History struct {
+	PlayerID    string          `json:"playerId"`
+	Tournaments []Participation `json:"tournaments"`
+}
+
 // Tournament is struct for tournament perfomance
 type Tournament struct {
-	ID           string   `json:"id" bson:"_id"`
-	Deposit      int      `json:"deposit" bson:"deposit"`
-	Prize        int      `json:"prize" bson:"prize"`
-	Participants []string `json:"participants" bson:"participants"`
-	Winner       Winner   `json:"winner" bson:"winner"`
-	IsOpen       bool     `json:"isOpen" bson:"isOpen"`
+	ID           string     `json:"id" bson:"_id"`
+	Deposit      int        `json:"deposit" bson:"deposit"`
+	Currency     string     `json:"currency" bson:"currency,omitempty"`
+	Prize        int        `json:"prize" bson:"prize"`
+	Participants []string   `json:"participants" bson:"participants"`
+	Winner       Winner     `json:"winner" bson:"winner"`
+	Winners      []Winner   `json:"winners,omitempty" bson:"winners,omitempty"`
+	IsOpen       bool       `json:"isOpen" bson:"isOpen"`
+	Satellite    Satellite  `json:"satellite" bson:"satellite"`
+	MaxEntries   int        `json:"maxEntries" bson:"maxEntries"`
+	Entries      []Entry    `json:"entries" bson:"entries"`
+	IsTeam       bool       `json:"isTeam" bson:"isTeam"`
+	Teams        []string   `json:"teams" bson:"teams"`
+	Created      time.Time  `json:"created" bson:"created"`
+	Closed       *time.Time `json:"closed,omitempty" bson:"closed,omitempty"`
+}
+
+// Cursor points to the last item of page, next page starts after it.
+// Value is sort field value of the item, id makes order unique.
+type Cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// PlayerFilter selects players for list, zero fields do not filter.
+// Sort is one of id, name or created, players are sorted by id by default.
+type PlayerFilter struct {
+	Status      string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Sort        string
+	Desc        bool
+	After       *Cursor
+	Limit       int
+}
+
+// TourFilter selects tournaments for list, zero fields do not filter. Status is open or closed.
+// Sort is one of id, deposit or created, tournaments are sorted by id by default.
+type TourFilter struct {
+	Status        string
+	MinDeposit    int
+	MaxDeposit    int
+	CreatedFrom   time.Time
+	CreatedTo     time.Time
+	ParticipantID string
+	Sort          string
+	Desc          bool
+	After         *Cursor
+	Limit         int
+}
+
+// PlayerPage is page of players list, next is cursor of the next page, it is empty on the last page
+type PlayerPage struct {
+	Players []Account `json:"players"`
+	Next    string    `json:"next,omitempty"`
+}
+
+// TourPage is page of tournaments list, next is cursor of the next page, it is empty on the last page
+type TourPage struct {
+	Tournaments []Tournament `json:"tournaments"`
+	Next        string       `json:"next,omitempty"`
+}
+
+// Entry is one entry of player into tournament, numbered from 1 for every player. Free entry is ticket or seat,
+// deposit has not been paid for it.
+type Entry struct {
+	PlayerID string `json:"playerId" bson:"playerId"`
+	Number   int    `json:"entry" bson:"entry"`
+	Free     bool   `json:"-" bson:"free,omitempty"`
+}
+
+// Satellite describes tournament, which prize is seats in target tournament
+type Satellite struct {
+	TargetID string `json:"targetId" bson:"targetId"`
+	Seats    int    `json:"seats" bson:"seats"`
+}
+
+// Team is group of players, which joins tournaments together
+type Team struct {
+	ID      string       `json:"id" bson:"_id"`
+	Captain string       `json:"captain" bson:"captain"`
+	Members []TeamMember `json:"members" bson:"members"`
+}
+
+// TeamMember is team player with their share of deposits and prizes in percents
+type TeamMember struct {
+	PlayerID string `json:"playerId" bson:"playerId"`
+	Share    int    `json:"share" bson:"share"`
+}
+
+// Split splits points between team members by their shares, captain gets points left after rounding.
+// Parts are returned in order of team members.
+func (t Team) Split(points int) []int {
+	parts := make([]int, len(t.Members))
+	left := points
+	captain := 0
+	for i, m := range t.Members {
+		parts[i] = points * m.Share / 100
+		left -= parts[i]
+		if m.PlayerID == t.Captain {
+			captain = i
+		}
+	}
+	if len(parts) > 0 {
+		parts[captain] += left
+	}
+	return parts
+}
+
+// Block of event types
+const (
+	EventPlayerJoined     = "playerJoined"
+	EventTournamentClosed = "tournamentClosed"
+	EventWinnerChosen     = "winnerChosen"
+	EventBalanceChanged   = "balanceChanged"
+)
+
+// Event is domain event, which is published after change is saved. Points are prize of chosen winner
+// and change of balance, every member of joined team gets event with team id.
+type Event struct {
+	Type         string    `json:"type" bson:"type"`
+	TournamentID string    `json:"tournamentId,omitempty" bson:"tournamentId,omitempty"`
+	PlayerID     string    `json:"playerId,omitempty" bson:"playerId,omitempty"`
+	TeamID       string    `json:"teamId,omitempty" bson:"teamId,omitempty"`
+	Points       int       `json:"points,omitempty" bson:"points,omitempty"`
+	Currency     string    `json:"currency,omitempty" bson:"currency,omitempty"`
+	Created      time.Time `json:"created" bson:"created"`
+}
+
+// Webhook is subscription of partner system to events of types, they are posted to url and signed by secret.
+// Secret is returned only, when webhook is created.
+type Webhook struct {
+	ID      string    `json:"id" bson:"_id"`
+	URL     string    `json:"url" bson:"url"`
+	Events  []string  `json:"events" bson:"events"`
+	Secret  string    `json:"secret,omitempty" bson:"secret"`
+	Created time.Time `json:"created" bson:"created"`
+}
+
+// Delivery is event in outbox, which is waiting for delivery to webhook. Failed delivery is attempted again
+// at next attempt, delivery, which has failed every attempt, is dead and is kept in dead-letter list.
+type Delivery struct {
+	ID          string    `json:"id" bson:"_id"`
+	WebhookID   string    `json:"webhookId" bson:"webhookId"`
+	URL         string    `json:"url" bson:"-"`
+	Secret      string    `json:"-" bson:"-"`
+	Event       Event     `json:"event" bson:"event"`
+	Attempts    int       `json:"attempts" bson:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt" bson:"nextAttempt"`
+	LastError   string    `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	Dead        bool      `json:"dead" bson:"dead"`
+}
+
+// Block of types of batch operations
+const (
+	OpFund = "fund"
+	OpTake = "take"
+	OpJoin = "join"
+)
+
+// Operation is fund, take or join operation of batch. Points in currency are funded or taken from player,
+// player joins tournament with tournament id.
+type Operation struct {
+	Type         string `json:"type"`
+	PlayerID     string `json:"playerId"`
+	Currency     string `json:"currency,omitempty"`
+	Points       int    `json:"points,omitempty"`
+	TournamentID string `json:"tournamentId,omitempty"`
+}
+
+// Block of statuses of batch operations, aborted operation is not applied, because other operation of atomic batch has failed
+const (
+	OperationApplied = "applied"
+	OperationFailed  = "failed"
+	OperationAborted = "aborted"
+)
+
+// OperationResult is result of batch operation, error is set, if operation has failed
+type OperationResult struct {
+	Status string        `json:"status"`
+	Error  *errors.Error `json:"error,omitempty"`
+}
+
+// BatchResult is result of batch, results are in order of operations
+type BatchResult struct {
+	Atomic  bool              `json:"atomic"`
+	Applied int               `json:"applied"`
+	Failed  int               `json:"failed"`
+	Results []OperationResult `json:"results"`
+}
+
+// Block of roles of API keys. Admin can use every endpoint, client can only join tournaments and read,
+// read-only client can only read.
+const (
+	RoleAdmin    = "admin"
+	RoleClient   = "client"
+	RoleReadOnly = "read-only"
+)
+
+// RolePlayer is role of player, who is authenticated by JWT. Player can only view own balance and history
+// and join tournaments as themselves.
+const RolePlayer = "player"
+
+// APIKey is key of API client with role. Key is given to client only, when it is created, only its hash is stored.
+type APIKey struct {
+	ID      string    `json:"id" bson:"_id"`
+	Name    string    `json:"name" bson:"name"`
+	Role    string    `json:"role" bson:"role"`
+	Hash    string    `json:"-" bson:"hash"`
+	Created time.Time `json:"created" bson:"created"`
 }
@@ -23,8 +23,26 @@ const (
 	DuplicatedIDError         ErrCode = "duplicatedIDError"
 	NegativePointsNumberError ErrCode = "negativePointsNumberError"
 	NegativeDepositError      ErrCode = "negativeDepositError"
+	NegativeSeatsError        ErrCode = "negativeSeatsError"
+	NegativeEntriesError      ErrCode = "negativeEntriesError"
+	NegativeTTLError          ErrCode = "negativeTTLError"
+	InvalidSplitError         ErrCode = "invalidSplitError"
+	TeamTournamentError       ErrCode = "teamTournamentError"
 	NoneParticipantsError     ErrCode = "noneParticipantsError"
 	ClosedTournamentError     ErrCode = "closedTournamentError"
+	InvalidPromoError         ErrCode = "invalidPromoError"
+	PromoUnavailableError     ErrCode = "promoUnavailableError"
+	LimitExceededError        ErrCode = "limitExceededError"
+	SelfExcludedError         ErrCode = "selfExcludedError"
+	InactiveAccountError      ErrCode = "inactiveAccountError"
+	OpenEntriesError          ErrCode = "openEntriesError"
+	InvalidFilterError        ErrCode = "invalidFilterError"
+	InvalidWebhookError       ErrCode = "invalidWebhookError"
+	InvalidBatchError         ErrCode = "invalidBatchError"
+	InvalidRoleError          ErrCode = "invalidRoleError"
+	UnauthorizedError         ErrCode = "unauthorizedError"
+	ForbiddenError            ErrCode = "forbiddenError"
+	RateLimitError            ErrCode = "rateLimitError"
 	UnexpectedError           ErrCode = "unexpectedError"
 	JSONError                 ErrCode = "jsonError"
 	DatabaseOpenError         ErrCode = "databaseOpenError"
@@ -0,0 +1,124 @@
+// Package events provides in-process event bus, which delivers domain events to subscribers
+package events
+
+import (
+	"sync"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+)
+
+// bufferSize is number of events, which subscriber can lag behind, next events are dropped for it
+const bufferSize = 64
+
+// Filter selects events of tournaments and players, event matches, if it is of any of them.
+// Filter with All matches every event, empty filter without All matches none.
+type Filter struct {
+	All         bool
+	Tournaments []string
+	Players     []string
+}
+
+// Match reports whether event matches filter
+func (f Filter) Match(e entity.Event) bool {
+	return f.All || contains(f.Tournaments, e.TournamentID) || contains(f.Players, e.PlayerID)
+}
+
+// Add returns filter, which also matches events of tournament and player, empty ids are not added.
+// Filter with All matches only added ids then.
+func (f Filter) Add(tourID, playerID string) Filter {
+	if tourID != "" || playerID != "" {
+		f.All = false
+	}
+	if tourID != "" && !contains(f.Tournaments, tourID) {
+		f.Tournaments = append(f.Tournaments[:len(f.Tournaments):len(f.Tournaments)], tourID)
+	}
+	if playerID != "" && !contains(f.Players, playerID) {
+		f.Players = append(f.Players[:len(f.Players):len(f.Players)], playerID)
+	}
+	return f
+}
+
+// Remove returns filter, which does not match events of tournament and player any more
+func (f Filter) Remove(tourID, playerID string) Filter {
+	f.Tournaments = without(f.Tournaments, tourID)
+	f.Players = without(f.Players, playerID)
+	return f
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func without(ids []string, id string) []string {
+	var res []string
+	for _, v := range ids {
+		if v != id {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Bus is in-process event bus, published event is sent to every subscriber, which filter it matches.
+// Publishing never blocks, subscriber, which does not read events, loses them.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// Subscription receives events, which match its filter, until it is closed
+type Subscription struct {
+	bus    *Bus
+	filter Filter
+	events chan entity.Event
+	once   sync.Once
+}
+
+// NewBus returns bus without subscribers
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Publish sends event to subscribers
+func (b *Bus) Publish(e entity.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for s := range b.subs {
+		if !s.filter.Match(e) {
+			continue
+		}
+		select {
+		case s.events <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns subscription to events, which match filter
+func (b *Bus) Subscribe(f Filter) *Subscription {
+	s := &Subscription{bus: b, filter: f, events: make(chan entity.Event, bufferSize)}
+	b.mu.Lock()
+	b.subs[s] = struct{}{}
+	b.mu.Unlock()
+	return s
+}
+
+// Events returns channel of subscription events, it is closed, when subscription is closed
+func (s *Subscription) Events() <-chan entity.Event {
+	return s.events
+}
+
+// Close stops delivering events to subscription, it can be called several times
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.events)
+	})
+}
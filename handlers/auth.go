@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/dmitriyomelyusik/Tournament/auth"
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// APIKeyHeader is header, which carries API key of client
+const APIKeyHeader = "X-API-Key"
+
+// authenticator returns API key of key
+type authenticator interface {
+	Authenticate(key string) (entity.APIKey, error)
+}
+
+// tokenVerifier returns player principal of bearer token
+type tokenVerifier interface {
+	Verify(token string) (auth.Principal, error)
+}
+
+// publicRoutes are used without API key
+var publicRoutes = map[string]bool{"/openapi.json": true}
+
+// readRoutes only read, read-only role can use them. V1 routes accept any method, so they are matched by path,
+// v2 routes by method and path.
+var readRoutes = map[string]bool{
+	"/balance": true, "/limits": true, "/player": true, "/players": true, "/tournaments": true, "/tournaments/{id}": true,
+	"/players/{id}/tournaments": true, "/events": true, "/events/ws": true,
+	"GET /v2/players": true, "GET /v2/players/{id}": true, "GET /v2/players/{id}/balance": true, "GET /v2/players/{id}/limits": true,
+	"GET /v2/players/{id}/tournaments": true, "GET /v2/tournaments": true, "GET /v2/tournaments/{id}": true,
+}
+
+// clientRoutes join tournaments, client role can use them and read routes. Other routes are admin routes.
+var clientRoutes = map[string]bool{"/joinTournament": true, "POST /v2/tournaments/{id}/entries": true}
+
+// playerRoutes are routes, which player with token can use, handlers of them let player use only own id
+var playerRoutes = map[string]bool{
+	"/balance": true, "/joinTournament": true, "/tournaments": true, "/tournaments/{id}": true, "/players/{id}/tournaments": true,
+	"GET /v2/players/{id}/balance": true, "GET /v2/players/{id}/tournaments": true, "GET /v2/tournaments": true,
+	"GET /v2/tournaments/{id}": true, "POST /v2/tournaments/{id}/entries": true,
+}
+
+// routeRole returns role, which route of request needs, it is empty for public routes. Routes, which are not listed,
+// and requests without route path template need admin.
+func routeRole(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return entity.RoleAdmin
+	}
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return entity.RoleAdmin
+	}
+	if publicRoutes[path] {
+		return ""
+	}
+	switch {
+	case readRoutes[path] || readRoutes[r.Method+" "+path]:
+		return entity.RoleReadOnly
+	case clientRoutes[path] || clientRoutes[r.Method+" "+path]:
+		return entity.RoleClient
+	}
+	return entity.RoleAdmin
+}
+
+// Authenticate is middleware, which lets request in, if its API key has role, which route needs, or if its bearer token
+// is valid and route is player route. Principal of request is put into its context. Requests without valid key or token
+// get 401 status, requests, which cannot use route, 403, both with errors.Error json. Requests without token are let in
+// without API key, if API keys are not authenticated.
+func (s Server) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := routeRole(r)
+		if role == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if token := auth.BearerToken(r.Header.Get("Authorization")); token != "" && s.Tokens != nil {
+			p, err := s.Tokens.Verify(token)
+			if err != nil {
+				authError(w, err)
+				return
+			}
+			path, _ := mux.CurrentRoute(r).GetPathTemplate()
+			if !playerRoutes[path] && !playerRoutes[r.Method+" "+path] {
+				authError(w, errors.Error{Code: errors.ForbiddenError, Message: "authorize: player cannot use " + r.URL.Path + ", id: " + p.PlayerID, Info: role})
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), p)))
+			return
+		}
+		if s.Auth == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, err := s.Auth.Authenticate(r.Header.Get(APIKeyHeader))
+		if err != nil {
+			authError(w, err)
+			return
+		}
+		if !auth.Allows(key.Role, role) {
+			authError(w, errors.Error{Code: errors.ForbiddenError, Message: "authorize: role " + key.Role + " cannot use " + r.URL.Path + ", id: " + key.ID, Info: role})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), auth.Principal{Role: key.Role, KeyID: key.ID})))
+	})
+}
+
+// checkPlayer returns forbidden error, if request is made by player with other id than id
+func checkPlayer(r *http.Request, id string) error {
+	return auth.CheckPlayer(r.Context(), id)
+}
+
+// checkJoin returns forbidden error, if request is made by player, who joins as other player or joins team
+func checkJoin(r *http.Request, playerID, teamID string) error {
+	return auth.CheckJoin(r.Context(), playerID, teamID)
+}
+
+// authError writes error of authentication as errors.Error json with status of its code
+func authError(w http.ResponseWriter, err error) {
+	myErr := errors.Transform(err)
+	jsonResponse(w, myErr, Status(myErr.Code))
+}
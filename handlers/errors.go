@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// problemContentType is content type of RFC 7807 error body
+const problemContentType = "application/problem+json"
+
+// Problem is RFC 7807 error body, it carries code, message and info of errors.Error
+type Problem struct {
+	Type    string         `json:"type"`
+	Title   string         `json:"title"`
+	Status  int            `json:"status"`
+	Detail  string         `json:"detail,omitempty"`
+	Code    errors.ErrCode `json:"code"`
+	Message string         `json:"message"`
+	Info    interface{}    `json:"info"`
+}
+
+// Status returns HTTP status of error code: malformed requests get 400, invalid values 422, missing resources 404,
+// requests, which conflict with current state, 409, requests without valid API key 401, requests over player limits,
+// of inactive players or with API key without needed role 403, requests over rate limit 429, unavailable database 503
+func Status(code errors.ErrCode) int {
+	switch code {
+	case errors.JSONError, errors.NotNumberError, errors.InvalidFilterError:
+		return http.StatusBadRequest
+	case errors.NegativePointsNumberError, errors.NegativeDepositError, errors.NegativeSeatsError, errors.NegativeEntriesError, errors.NegativeTTLError, errors.InvalidSplitError, errors.InvalidPromoError, errors.InvalidWebhookError, errors.InvalidBatchError, errors.InvalidRoleError, errors.TeamTournamentError:
+		return http.StatusUnprocessableEntity
+	case errors.NotFoundError:
+		return http.StatusNotFound
+	case errors.DuplicatedIDError, errors.ClosedTournamentError, errors.PromoUnavailableError, errors.OpenEntriesError, errors.NoneParticipantsError:
+		return http.StatusConflict
+	case errors.UnauthorizedError:
+		return http.StatusUnauthorized
+	case errors.LimitExceededError, errors.SelfExcludedError, errors.InactiveAccountError, errors.ForbiddenError:
+		return http.StatusForbidden
+	case errors.RateLimitError:
+		return http.StatusTooManyRequests
+	case errors.DatabaseOpenError, errors.DatabaseCreatingError, errors.DatabasePingError, errors.ConnectionError, errors.TransactionError:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// jsonError writes error of v1 route, in legacy mode it is written the way v1 always did
+func (s Server) jsonError(w http.ResponseWriter, err error) {
+	if s.LegacyErrors {
+		legacyError(w, err)
+		return
+	}
+	problemError(w, err)
+}
+
+// problemError writes error as problem json with status of its code
+func problemError(w http.ResponseWriter, err error) {
+	myErr := errors.Transform(err)
+	status := Status(myErr.Code)
+	problem := Problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  myErr.Message,
+		Code:    myErr.Code,
+		Message: myErr.Message,
+		Info:    myErr.Info,
+	}
+	w.Header().Set("content-type", problemContentType)
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(problem); err != nil {
+		log.Println(err)
+	}
+}
+
+// legacyError writes error with v1 statuses: invalid values, duplicates and conflicts get 404,
+// tournament without participants 200
+func legacyError(w http.ResponseWriter, err error) {
+	myErr, ok := err.(errors.Error)
+	if !ok {
+		myErr = errors.Error{
+			Code:    "UnknownError",
+			Message: err.Error(),
+		}
+	}
+	var status int
+	switch myErr.Code {
+	case errors.NotFoundError, errors.NotNumberError, errors.NegativePointsNumberError, errors.NegativeDepositError, errors.NegativeSeatsError, errors.NegativeEntriesError, errors.NegativeTTLError, errors.InvalidSplitError, errors.TeamTournamentError, errors.DuplicatedIDError, errors.ClosedTournamentError, errors.InvalidPromoError, errors.PromoUnavailableError, errors.OpenEntriesError, errors.InvalidFilterError:
+		status = http.StatusNotFound
+	case errors.LimitExceededError, errors.SelfExcludedError, errors.InactiveAccountError:
+		status = http.StatusForbidden
+	case errors.NoneParticipantsError:
+		status = http.StatusOK
+	default:
+		status = http.StatusInternalServerError
+	}
+	jsonResponse(w, myErr, status)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/events"
+)
+
+// keepAlive is period of pings, which keep idle event streams open
+const keepAlive = 15 * time.Second
+
+// writeWait is time, which is given to write websocket message
+const writeWait = 10 * time.Second
+
+type eventBus interface {
+	Subscribe(f events.Filter) *events.Subscription
+}
+
+// wsMessage changes websocket subscription, action is subscribe or unsubscribe
+type wsMessage struct {
+	Action       string `json:"action"`
+	TournamentID string `json:"tournamentId"`
+	PlayerID     string `json:"playerId"`
+}
+
+var upgrader = websocket.Upgrader{}
+
+// HandleEvents handles GET /events, events of tournaments and players from query are streamed as server-sent events,
+// without them every event is streamed
+func (s Server) HandleEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Events == nil {
+			problemError(w, errors.Error{Code: errors.ConnectionError, Message: "events: event bus is not available"})
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			problemError(w, errors.Error{Code: errors.UnexpectedError, Message: "events: streaming is not supported"})
+			return
+		}
+		sub := s.Events.Subscribe(eventFilter(r.URL.Query()))
+		defer sub.Close()
+		// stream lives longer than server write timeout
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		w.Header().Set("content-type", "text/event-stream")
+		w.Header().Set("cache-control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(e)
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// HandleEventsWS handles GET /events/ws, events are sent as websocket json messages. Subscription starts with
+// tournaments and players from query and is changed by subscribe and unsubscribe messages of client.
+func (s Server) HandleEventsWS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Events == nil {
+			problemError(w, errors.Error{Code: errors.ConnectionError, Message: "events: event bus is not available"})
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// upgrader has answered with error status
+			return
+		}
+		defer conn.Close()
+		messages := make(chan []byte)
+		quit := make(chan struct{})
+		defer close(quit)
+		go func() {
+			defer close(messages)
+			for {
+				_, m, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				select {
+				case messages <- m:
+				case <-quit:
+					return
+				}
+			}
+		}()
+		filter := eventFilter(r.URL.Query())
+		sub := s.Events.Subscribe(filter)
+		defer func() { sub.Close() }()
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		for {
+			var err error
+			select {
+			case m, ok := <-messages:
+				if !ok {
+					return
+				}
+				var invalid error
+				filter, invalid = updateFilter(filter, m)
+				if invalid != nil {
+					err = writeWS(conn, invalid)
+					break
+				}
+				sub.Close()
+				sub = s.Events.Subscribe(filter)
+			case e, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				err = writeWS(conn, e)
+			case <-ticker.C:
+				err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// updateFilter applies subscribe or unsubscribe message to filter, invalid message is returned as error
+func updateFilter(f events.Filter, m []byte) (events.Filter, error) {
+	var msg wsMessage
+	err := json.Unmarshal(m, &msg)
+	if err != nil {
+		return f, errors.Error{Code: errors.JSONError, Message: "events: message is not valid json", Info: err.Error()}
+	}
+	switch msg.Action {
+	case "subscribe":
+		return f.Add(msg.TournamentID, msg.PlayerID), nil
+	case "unsubscribe":
+		return f.Remove(msg.TournamentID, msg.PlayerID), nil
+	default:
+		return f, errors.Error{Code: errors.JSONError, Message: "events: unknown action: " + msg.Action}
+	}
+}
+
+func writeWS(conn *websocket.Conn, v interface{}) error {
+	err := conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err != nil {
+		return err
+	}
+	return conn.WriteJSON(v)
+}
+
+// eventFilter returns filter of tournamentId and playerId query parameters, both can be repeated.
+// Filter matches all events, if query has no ids.
+func eventFilter(query url.Values) events.Filter {
+	f := events.Filter{All: true}
+	for _, id := range query["tournamentId"] {
+		f = f.Add(id, "")
+	}
+	for _, id := range query["playerId"] {
+		f = f.Add("", id)
+	}
+	return f
+}
@@ -5,25 +5,75 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
 	"github.com/gorilla/mux"
 )
 
 type ctlr interface {
-	Fund(id string, points int) (entity.Player, error)
-	Take(id string, points int) error
-	Balance(id string) (entity.Player, error)
-	AnnounceTournament(id string, deposit int) error
+	Fund(id, currency string, points int, expiresIn time.Duration) (entity.Player, error)
+	Take(id, currency string, points int) error
+	Transfer(from, to, currency string, points int) error
+	Balance(id, currency string) (entity.Balance, error)
+	Hold(id, playerID, currency string, points int, ttl time.Duration) (entity.Hold, error)
+	Capture(id string) error
+	Release(id string) error
+	AnnounceTournament(id, currency string, deposit, maxEntries int) error
+	AnnounceSatellite(id string, deposit int, targetID string, seats int) error
 	JoinTournament(tourID, playerID string) error
 	Results(tourID string) (entity.Winners, error)
+	CreateTeam(id, captain string, members []string, shares []int) (entity.Team, error)
+	AnnounceTeamTournament(id, currency string, deposit int) error
+	JoinTeam(tourID, teamID string, captainPays bool) error
+	CreatePromo(promo entity.Promo) (entity.Promo, error)
+	Redeem(code, playerID string) (entity.Redemption, error)
+	Limits(playerID string) (entity.Limits, error)
+	SetLimits(playerID string, daily, weekly int) (entity.Limits, error)
+	SelfExclude(playerID string, period time.Duration) (entity.Limits, error)
+	Register(id, name string, metadata map[string]string) (entity.Account, error)
+	Account(id string) (entity.Account, error)
+	Suspend(id string) (entity.Account, error)
+	Reinstate(id string) (entity.Account, error)
+	CloseAccount(id string, withdraw bool) (entity.Closure, error)
+	ListPlayers(filter entity.PlayerFilter, cursor string) (entity.PlayerPage, error)
+	ListTournaments(filter entity.TourFilter, cursor string) (entity.TourPage, error)
+	Tournament(id string) (entity.Tournament, error)
+	History(playerID string) (entity.History, error)
+	CreateWebhook(webhook entity.Webhook) (entity.Webhook, error)
+	Webhooks() ([]entity.Webhook, error)
+	DeleteWebhook(id string) error
+	DeadDeliveries() ([]entity.Delivery, error)
+	RetryDelivery(id string) error
+	Batch(ops []entity.Operation, atomic bool) (entity.BatchResult, error)
 }
 
+// metadataPrefix is prefix of register query parameters, which are saved into account metadata
+const metadataPrefix = "meta."
+
+// defaultHoldTTL is used, when hold query has no ttl
+const defaultHoldTTL = 15 * time.Minute
+
 // Server uses controller in handling http methods
 type Server struct {
 	Controller ctlr
+	// Events streams domain events, event routes are not available without it
+	Events eventBus
+	// LegacyErrors makes v1 routes answer errors with v1 statuses and errors.Error body instead of problem json
+	LegacyErrors bool
+	// Auth authenticates API keys, every route except public ones needs key with role, routes are open without it
+	Auth authenticator
+	// Tokens verifies bearer tokens of players, player can use only player routes and only with own id
+	Tokens tokenVerifier
+	// RateLimit limits rate of requests of every API key, player or IP address in every route class, requests are not limited without it
+	RateLimit rateLimiter
+	// TrustedProxies are proxies, requests from which are limited by client address of their X-Forwarded-For header
+	TrustedProxies ratelimit.Proxies
 }
 
 // HandleFund handles fund query
@@ -34,12 +84,21 @@ func (s Server) HandleFund() http.HandlerFunc {
 		points := query.Get("points")
 		p, err := strconv.Atoi(points)
 		if err != nil {
-			jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot fund player, points is not number: " + points, Info: err.Error()})
+			s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot fund player, points is not number: " + points, Info: err.Error()})
 			return
 		}
-		player, err := s.Controller.Fund(id, p)
+		var expiresIn time.Duration
+		if days := query.Get("expireDays"); days != "" {
+			d, err := strconv.Atoi(days)
+			if err != nil {
+				s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot fund player, expire days is not number: " + days, Info: err.Error()})
+				return
+			}
+			expiresIn = time.Duration(d) * 24 * time.Hour
+		}
+		player, err := s.Controller.Fund(id, query.Get("currency"), p, expiresIn)
 		if err != nil {
-			jsonError(w, err)
+			s.jsonError(w, err)
 			return
 		}
 		if player != (entity.Player{}) {
@@ -57,12 +116,30 @@ func (s Server) HandleTake() http.HandlerFunc {
 		points := query.Get("points")
 		p, err := strconv.Atoi(points)
 		if err != nil {
-			jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot take points, points is not number: " + points, Info: err.Error()})
+			s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot take points, points is not number: " + points, Info: err.Error()})
 			return
 		}
-		err = s.Controller.Take(id, p)
+		err = s.Controller.Take(id, query.Get("currency"), p)
 		if err != nil {
-			jsonError(w, err)
+			s.jsonError(w, err)
+			return
+		}
+	}
+}
+
+// HandleTransfer handles transfer query
+func (s Server) HandleTransfer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		points := query.Get("points")
+		p, err := strconv.Atoi(points)
+		if err != nil {
+			s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot transfer points, points is not number: " + points, Info: err.Error()})
+			return
+		}
+		err = s.Controller.Transfer(query.Get("from"), query.Get("to"), query.Get("currency"), p)
+		if err != nil {
+			s.jsonError(w, err)
 			return
 		}
 	}
@@ -71,16 +148,70 @@ func (s Server) HandleTake() http.HandlerFunc {
 // HandleBalance handles balance query
 func (s Server) HandleBalance() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := r.URL.Query().Get("playerId")
-		p, err := s.Controller.Balance(id)
+		query := r.URL.Query()
+		err := checkPlayer(r, query.Get("playerId"))
 		if err != nil {
-			jsonError(w, err)
+			s.jsonError(w, err)
+			return
+		}
+		p, err := s.Controller.Balance(query.Get("playerId"), query.Get("currency"))
+		if err != nil {
+			s.jsonError(w, err)
 			return
 		}
 		jsonResponse(w, p, http.StatusOK)
 	}
 }
 
+// HandleHold handles hold query
+func (s Server) HandleHold() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		points := query.Get("points")
+		p, err := strconv.Atoi(points)
+		if err != nil {
+			s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot hold points, points is not number: " + points, Info: err.Error()})
+			return
+		}
+		ttl := defaultHoldTTL
+		if t := query.Get("ttl"); t != "" {
+			ttl, err = time.ParseDuration(t)
+			if err != nil {
+				s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot hold points, ttl is not duration: " + t, Info: err.Error()})
+				return
+			}
+		}
+		hold, err := s.Controller.Hold(query.Get("holdId"), query.Get("playerId"), query.Get("currency"), p, ttl)
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, hold, http.StatusCreated)
+	}
+}
+
+// HandleCapture handles capture query
+func (s Server) HandleCapture() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := s.Controller.Capture(r.URL.Query().Get("holdId"))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+	}
+}
+
+// HandleRelease handles release query
+func (s Server) HandleRelease() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := s.Controller.Release(r.URL.Query().Get("holdId"))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+	}
+}
+
 // HandleAnnounce handles announce query
 func (s Server) HandleAnnounce() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -89,38 +220,387 @@ func (s Server) HandleAnnounce() http.HandlerFunc {
 		dep := query.Get("deposit")
 		deposit, err := strconv.Atoi(dep)
 		if err != nil {
-			jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot create tournament, deposit is not number: " + dep, Info: err.Error()})
+			s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot create tournament, deposit is not number: " + dep, Info: err.Error()})
+			return
+		}
+		if targetID := query.Get("targetId"); targetID != "" {
+			s.announceSatellite(w, id, deposit, targetID, query.Get("seats"))
 			return
 		}
-		err = s.Controller.AnnounceTournament(id, deposit)
+		if query.Get("teams") == "true" {
+			err = s.Controller.AnnounceTeamTournament(id, query.Get("currency"), deposit)
+			if err != nil {
+				s.jsonError(w, err)
+			}
+			return
+		}
+		maxEntries := 1
+		if me := query.Get("maxEntries"); me != "" {
+			maxEntries, err = strconv.Atoi(me)
+			if err != nil {
+				s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot create tournament, max entries is not number: " + me, Info: err.Error()})
+				return
+			}
+		}
+		err = s.Controller.AnnounceTournament(id, query.Get("currency"), deposit, maxEntries)
 		if err != nil {
-			jsonError(w, err)
+			s.jsonError(w, err)
 			return
 		}
 	}
 }
 
+func (s Server) announceSatellite(w http.ResponseWriter, id string, deposit int, targetID, rawSeats string) {
+	seats, err := strconv.Atoi(rawSeats)
+	if err != nil {
+		s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot create satellite, seats is not number: " + rawSeats, Info: err.Error()})
+		return
+	}
+	err = s.Controller.AnnounceSatellite(id, deposit, targetID, seats)
+	if err != nil {
+		s.jsonError(w, err)
+	}
+}
+
 // HandleJoin handles join query
 func (s Server) HandleJoin() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		query := r.URL.Query()
 		tourID := query.Get("tournamentId")
 		playerID := query.Get("playerId")
-		err := s.Controller.JoinTournament(tourID, playerID)
+		teamID := query.Get("teamId")
+		err := checkJoin(r, playerID, teamID)
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		if teamID != "" {
+			err := s.Controller.JoinTeam(tourID, teamID, query.Get("payer") == "captain")
+			if err != nil {
+				s.jsonError(w, err)
+			}
+			return
+		}
+		err = s.Controller.JoinTournament(tourID, playerID)
 		if err != nil {
-			jsonError(w, err)
+			s.jsonError(w, err)
 			return
 		}
 	}
 }
 
+// HandleCreateTeam handles create team query
+func (s Server) HandleCreateTeam() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		id := query.Get("teamId")
+		var members []string
+		if m := query.Get("members"); m != "" {
+			members = strings.Split(m, ",")
+		}
+		var shares []int
+		if sh := query.Get("shares"); sh != "" {
+			for _, v := range strings.Split(sh, ",") {
+				share, err := strconv.Atoi(v)
+				if err != nil {
+					s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot create team, share is not number: " + v, Info: err.Error()})
+					return
+				}
+				shares = append(shares, share)
+			}
+		}
+		team, err := s.Controller.CreateTeam(id, query.Get("captain"), members, shares)
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, team, http.StatusCreated)
+	}
+}
+
+// HandleCreatePromo handles create promo query
+func (s Server) HandleCreatePromo() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		promo := entity.Promo{
+			Code:           query.Get("code"),
+			Currency:       query.Get("currency"),
+			TournamentID:   query.Get("tournamentId"),
+			NewPlayersOnly: query.Get("newPlayersOnly") == "true",
+		}
+		err := parseParams(query, "create promo", numberParam("points", &promo.Points), numberParam("maxUses", &promo.MaxUses),
+			numberParam("maxPerPlayer", &promo.MaxPerPlayer), timeParam("validFrom", &promo.ValidFrom), timeParam("validTo", &promo.ValidTo))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		promo, err = s.Controller.CreatePromo(promo)
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, promo, http.StatusCreated)
+	}
+}
+
+// HandleRedeem handles redeem query
+func (s Server) HandleRedeem() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		red, err := s.Controller.Redeem(query.Get("code"), query.Get("playerId"))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, red, http.StatusOK)
+	}
+}
+
+// HandleLimits handles limits query
+func (s Server) HandleLimits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limits, err := s.Controller.Limits(r.URL.Query().Get("playerId"))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, limits, http.StatusOK)
+	}
+}
+
+// HandleSetLimits handles set limits query, not set limit means no limit
+func (s Server) HandleSetLimits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var values [2]int
+		for i, name := range []string{"daily", "weekly"} {
+			v := query.Get(name)
+			if v == "" {
+				continue
+			}
+			limit, err := strconv.Atoi(v)
+			if err != nil {
+				s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot set limits, " + name + " is not number: " + v, Info: err.Error()})
+				return
+			}
+			values[i] = limit
+		}
+		limits, err := s.Controller.SetLimits(query.Get("playerId"), values[0], values[1])
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, limits, http.StatusOK)
+	}
+}
+
+// HandleSelfExclude handles self exclude query
+func (s Server) HandleSelfExclude() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		days := query.Get("days")
+		d, err := strconv.Atoi(days)
+		if err != nil {
+			s.jsonError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot self exclude player, days is not number: " + days, Info: err.Error()})
+			return
+		}
+		limits, err := s.Controller.SelfExclude(query.Get("playerId"), time.Duration(d)*24*time.Hour)
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, limits, http.StatusOK)
+	}
+}
+
+// HandleRegister handles register player query, parameters with meta. prefix are saved into account metadata
+func (s Server) HandleRegister() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var metadata map[string]string
+		for k := range query {
+			if strings.HasPrefix(k, metadataPrefix) && len(k) > len(metadataPrefix) {
+				if metadata == nil {
+					metadata = make(map[string]string)
+				}
+				metadata[strings.TrimPrefix(k, metadataPrefix)] = query.Get(k)
+			}
+		}
+		account, err := s.Controller.Register(query.Get("playerId"), query.Get("name"), metadata)
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, account, http.StatusCreated)
+	}
+}
+
+// HandleAccount handles player account query
+func (s Server) HandleAccount() http.HandlerFunc {
+	return s.handleAccount(s.Controller.Account)
+}
+
+// HandleSuspend handles suspend player query
+func (s Server) HandleSuspend() http.HandlerFunc {
+	return s.handleAccount(s.Controller.Suspend)
+}
+
+// HandleReinstate handles reinstate player query
+func (s Server) HandleReinstate() http.HandlerFunc {
+	return s.handleAccount(s.Controller.Reinstate)
+}
+
+// HandleClose handles close player query, with withdraw=true player is withdrawn from open tournaments
+func (s Server) HandleClose() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		closure, err := s.Controller.CloseAccount(query.Get("playerId"), query.Get("withdraw") == "true")
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, closure, http.StatusOK)
+	}
+}
+
+func (s Server) handleAccount(action func(id string) (entity.Account, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, err := action(r.URL.Query().Get("playerId"))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, account, http.StatusOK)
+	}
+}
+
+// HandleListPlayers handles list of registered players query
+func (s Server) HandleListPlayers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := entity.PlayerFilter{
+			Status: query.Get("status"),
+			Sort:   query.Get("sort"),
+			Desc:   query.Get("order") == "desc",
+		}
+		err := parseParams(query, "list players", numberParam("limit", &filter.Limit),
+			timeParam("createdFrom", &filter.CreatedFrom), timeParam("createdTo", &filter.CreatedTo))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		page, err := s.Controller.ListPlayers(filter, query.Get("cursor"))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, page, http.StatusOK)
+	}
+}
+
+// HandleListTournaments handles list of tournaments query
+func (s Server) HandleListTournaments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := entity.TourFilter{
+			Status:        query.Get("status"),
+			ParticipantID: query.Get("participantId"),
+			Sort:          query.Get("sort"),
+			Desc:          query.Get("order") == "desc",
+		}
+		err := parseParams(query, "list tournaments", numberParam("minDeposit", &filter.MinDeposit), numberParam("maxDeposit", &filter.MaxDeposit),
+			numberParam("limit", &filter.Limit), timeParam("createdFrom", &filter.CreatedFrom), timeParam("createdTo", &filter.CreatedTo))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		page, err := s.Controller.ListTournaments(filter, query.Get("cursor"))
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, page, http.StatusOK)
+	}
+}
+
+// HandleTournament handles tournament details query, tournament id is path variable
+func (s Server) HandleTournament() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tour, err := s.Controller.Tournament(mux.Vars(r)["id"])
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, tour, http.StatusOK)
+	}
+}
+
+// HandleHistory handles player tournament history query, player id is path variable
+func (s Server) HandleHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := checkPlayer(r, mux.Vars(r)["id"])
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		history, err := s.Controller.History(mux.Vars(r)["id"])
+		if err != nil {
+			s.jsonError(w, err)
+			return
+		}
+		jsonResponse(w, history, http.StatusOK)
+	}
+}
+
+// queryParam is query parameter, which is parsed into number or into time
+type queryParam struct {
+	name   string
+	number *int
+	time   *time.Time
+}
+
+// numberParam returns query parameter, which is parsed into number
+func numberParam(name string, value *int) queryParam {
+	return queryParam{name: name, number: value}
+}
+
+// timeParam returns query parameter, which is parsed into RFC3339 time
+func timeParam(name string, value *time.Time) queryParam {
+	return queryParam{name: name, time: value}
+}
+
+// parseParams parses set query parameters in order of params, op is used in error message.
+// Error is of the first invalid parameter, so it does not depend on order of query.
+func parseParams(query url.Values, op string, params ...queryParam) error {
+	for _, p := range params {
+		v := query.Get(p.name)
+		if v == "" {
+			continue
+		}
+		if p.number != nil {
+			num, err := strconv.Atoi(v)
+			if err != nil {
+				return errors.Error{Code: errors.NotNumberError, Message: "cannot " + op + ", " + p.name + " is not number: " + v, Info: err.Error()}
+			}
+			*p.number = num
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return errors.Error{Code: errors.NotNumberError, Message: "cannot " + op + ", " + p.name + " is not RFC3339 time: " + v, Info: err.Error()}
+		}
+		*p.time = t
+	}
+	return nil
+}
+
 //HandleResults handles results query
 func (s Server) HandleResults() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		tourID := r.URL.Query().Get("tournamentId")
 		res, err := s.Controller.Results(tourID)
 		if err != nil {
-			jsonError(w, err)
+			s.jsonError(w, err)
 			return
 		}
 		jsonResponse(w, res, http.StatusOK)
@@ -132,36 +612,49 @@ func NewRouter(s Server) *mux.Router {
 	r := mux.NewRouter()
 	r.HandleFunc("/fund", s.HandleFund())
 	r.HandleFunc("/take", s.HandleTake())
+	r.HandleFunc("/transfer", s.HandleTransfer())
 	r.HandleFunc("/balance", s.HandleBalance())
+	r.HandleFunc("/hold", s.HandleHold())
+	r.HandleFunc("/capture", s.HandleCapture())
+	r.HandleFunc("/release", s.HandleRelease())
 	r.HandleFunc("/announceTournament", s.HandleAnnounce())
 	r.HandleFunc("/joinTournament", s.HandleJoin())
 	r.HandleFunc("/resultTournament", s.HandleResults())
-	return r
-}
-
-func jsonError(w http.ResponseWriter, err error) {
-	myErr, ok := err.(errors.Error)
-	if !ok {
-		myErr = errors.Error{
-			Code:    "UnknownError",
-			Message: err.Error(),
-		}
+	r.HandleFunc("/createTeam", s.HandleCreateTeam())
+	r.HandleFunc("/createPromo", s.HandleCreatePromo())
+	r.HandleFunc("/redeem", s.HandleRedeem())
+	r.HandleFunc("/limits", s.HandleLimits())
+	r.HandleFunc("/setLimits", s.HandleSetLimits())
+	r.HandleFunc("/selfExclude", s.HandleSelfExclude())
+	r.HandleFunc("/registerPlayer", s.HandleRegister())
+	r.HandleFunc("/player", s.HandleAccount())
+	r.HandleFunc("/suspendPlayer", s.HandleSuspend())
+	r.HandleFunc("/reinstatePlayer", s.HandleReinstate())
+	r.HandleFunc("/closePlayer", s.HandleClose())
+	r.HandleFunc("/players", s.HandleListPlayers()).Methods(http.MethodGet)
+	r.HandleFunc("/tournaments", s.HandleListTournaments()).Methods(http.MethodGet)
+	r.HandleFunc("/tournaments/{id}", s.HandleTournament()).Methods(http.MethodGet)
+	r.HandleFunc("/players/{id}/tournaments", s.HandleHistory()).Methods(http.MethodGet)
+	s.routesV2(r)
+	s.routesAdmin(r)
+	r.HandleFunc("/events", s.HandleEvents()).Methods(http.MethodGet)
+	r.HandleFunc("/events/ws", s.HandleEventsWS()).Methods(http.MethodGet)
+	r.HandleFunc("/openapi.json", s.HandleOpenAPI()).Methods(http.MethodGet)
+	if s.RateLimit != nil {
+		r.Use(s.LimitIP)
+	}
+	if s.Auth != nil || s.Tokens != nil {
+		r.Use(s.Authenticate)
 	}
-	var status int
-	switch myErr.Code {
-	case errors.NotFoundError, errors.NotNumberError, errors.NegativePointsNumberError, errors.NegativeDepositError, errors.DuplicatedIDError, errors.ClosedTournamentError:
-		status = http.StatusNotFound
-	case errors.NoneParticipantsError:
-		status = http.StatusOK
-	default:
-		status = http.StatusInternalServerError
+	if s.RateLimit != nil {
+		r.Use(s.LimitRate)
 	}
-	jsonResponse(w, myErr, status)
+	return r
 }
 
 func jsonResponse(w http.ResponseWriter, data interface{}, status int) {
-	w.WriteHeader(status)
 	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
 	encoder := json.NewEncoder(w)
 	if err := encoder.Encode(data); err != nil {
 		log.Println(err)
@@ -1,18 +1,32 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	e "errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dmitriyomelyusik/Tournament/auth"
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/events"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
 )
 
 var (
@@ -22,7 +36,7 @@ var (
 
 func TestMain(m *testing.M) {
 	controller = new(mockCtlr)
-	r := NewRouter(Server{Controller: controller})
+	r := NewRouter(Server{Controller: controller, LegacyErrors: true})
 	ts = httptest.NewServer(r)
 	defer ts.Close()
 	code := m.Run()
@@ -34,13 +48,15 @@ func TestHandlers_FundHandler(t *testing.T) {
 		{ID: "fund_ok", Points: 200},
 		{ID: "fund_negative_points_number", Points: -100},
 	}
-	controller.On("Fund", players[0].ID, players[0].Points).Return(players[0], nil)
-	controller.On("Fund", players[1].ID, players[1].Points).Return(entity.Player{}, errors.Error{Code: errors.NegativePointsNumberError})
+	controller.On("Fund", players[0].ID, "", players[0].Points, time.Duration(0)).Return(players[0], nil)
+	controller.On("Fund", players[0].ID, "", players[0].Points, 30*24*time.Hour).Return(players[0], nil)
+	controller.On("Fund", players[1].ID, "", players[1].Points, time.Duration(0)).Return(entity.Player{}, errors.Error{Code: errors.NegativePointsNumberError})
 	client := http.Client{}
 	tt := []struct {
 		name           string
 		playerID       string
 		fund           interface{}
+		expireDays     string
 		err            error
 		expectedError  error
 		expectedPlayer entity.Player
@@ -54,6 +70,24 @@ func TestHandlers_FundHandler(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectedError:  errors.Error{},
 		},
+		{
+			name:           "fund: expiring points",
+			playerID:       players[0].ID,
+			fund:           players[0].Points,
+			expireDays:     "30",
+			expectedPlayer: players[0],
+			expectedStatus: http.StatusCreated,
+			expectedError:  errors.Error{},
+		},
+		{
+			name:           "fund: incorrect expire days format",
+			playerID:       players[0].ID,
+			fund:           players[0].Points,
+			expireDays:     "month",
+			expectedPlayer: entity.Player{},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot fund player, expire days is not number: month", Info: "strconv.Atoi: parsing \"month\": invalid syntax"},
+		},
 		{
 			name:           "fund: incorrect points format",
 			playerID:       players[0].ID,
@@ -74,7 +108,7 @@ func TestHandlers_FundHandler(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/fund?playerId=%v&points=%v", ts.URL, tc.playerID, tc.fund), nil)
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/fund?playerId=%v&points=%v&expireDays=%v", ts.URL, tc.playerID, tc.fund, tc.expireDays), nil)
 			assert.Equal(t, tc.err, err)
 			res, err := client.Do(req)
 			assert.Equal(t, tc.err, err)
@@ -94,9 +128,9 @@ func TestHandlers_TakeHandler(t *testing.T) {
 		{ID: "take_unexpected", Points: 200},
 		{ID: "take_not_found", Points: 200},
 	}
-	controller.On("Take", players[0].ID, players[0].Points).Return(nil)
-	controller.On("Take", players[1].ID, players[1].Points).Return(errors.Error{Code: errors.UnexpectedError})
-	controller.On("Take", players[2].ID, players[2].Points).Return(errors.Error{Code: errors.NotFoundError})
+	controller.On("Take", players[0].ID, "", players[0].Points).Return(nil)
+	controller.On("Take", players[1].ID, "", players[1].Points).Return(errors.Error{Code: errors.UnexpectedError})
+	controller.On("Take", players[2].ID, "", players[2].Points).Return(errors.Error{Code: errors.NotFoundError})
 	client := http.Client{}
 	tt := []struct {
 		name           string
@@ -154,51 +188,111 @@ func TestHandlers_TakeHandler(t *testing.T) {
 	}
 }
 
-func TestHandlers_BalanceHandler(t *testing.T) {
-	players := []entity.Player{
-		{ID: "balance_ok", Points: 200},
-		{ID: "balance_not_found", Points: 100},
-	}
-	controller.On("Balance", players[0].ID).Return(players[0], nil)
-	controller.On("Balance", players[1].ID).Return(entity.Player{}, errors.Error{Code: errors.NotFoundError})
+func TestHandlers_TransferHandler(t *testing.T) {
+	controller.On("Transfer", "transfer_1", "transfer_2", "", 100).Return(nil)
+	controller.On("Transfer", "transfer_1", "transfer_2", "", 1000).Return(errors.Error{Code: errors.NegativePointsNumberError})
 	client := http.Client{}
 	tt := []struct {
 		name           string
-		playerID       string
+		points         interface{}
 		err            error
 		expectedError  errors.Error
-		expectedPlayer entity.Player
 		expectedStatus int
 	}{
 		{
-			name:           "balance: ok",
-			playerID:       players[0].ID,
+			name:           "transfer: ok",
+			points:         100,
 			expectedError:  errors.Error{},
-			expectedPlayer: players[0],
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name:           "balance: not found",
-			playerID:       players[1].ID,
-			expectedError:  errors.Error{Code: errors.NotFoundError},
-			expectedPlayer: entity.Player{},
+			name:           "transfer: not enough points",
+			points:         1000,
+			expectedError:  errors.Error{Code: errors.NegativePointsNumberError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "transfer: incorrect format",
+			points:         "incorrect_format",
+			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot transfer points, points is not number: incorrect_format", Info: "strconv.Atoi: parsing \"incorrect_format\": invalid syntax"},
 			expectedStatus: http.StatusNotFound,
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/balance?playerId=%v", ts.URL, tc.playerID), nil)
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/transfer?from=transfer_1&to=transfer_2&points=%v", ts.URL, tc.points), nil)
+			assert.Equal(t, tc.err, err)
+			res, err := client.Do(req)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				decoder := json.NewDecoder(res.Body)
+				var expErr errors.Error
+				err = decoder.Decode(&expErr)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedError, expErr)
+			}
+		})
+	}
+}
+
+func TestHandlers_BalanceHandler(t *testing.T) {
+	balances := []entity.Balance{
+		{ID: "balance_ok", Points: 200, Available: 150, Holds: []entity.Hold{{ID: "balance_hold", PlayerID: "balance_ok", Points: 50, Expires: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}}},
+		{ID: "balance_not_found", Points: 100},
+		{ID: "balance_ok", Points: 20, Currency: "coins", Available: 20},
+	}
+	controller.On("Balance", balances[0].ID, "").Return(balances[0], nil)
+	controller.On("Balance", balances[1].ID, "").Return(entity.Balance{}, errors.Error{Code: errors.NotFoundError})
+	controller.On("Balance", balances[2].ID, "coins").Return(balances[2], nil)
+	client := http.Client{}
+	tt := []struct {
+		name            string
+		playerID        string
+		currency        string
+		err             error
+		expectedError   errors.Error
+		expectedBalance entity.Balance
+		expectedStatus  int
+	}{
+		{
+			name:            "balance: ok",
+			playerID:        balances[0].ID,
+			expectedError:   errors.Error{},
+			expectedBalance: balances[0],
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "balance: currency",
+			playerID:        balances[2].ID,
+			currency:        "coins",
+			expectedError:   errors.Error{},
+			expectedBalance: balances[2],
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "balance: not found",
+			playerID:        balances[1].ID,
+			expectedError:   errors.Error{Code: errors.NotFoundError},
+			expectedBalance: entity.Balance{},
+			expectedStatus:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/balance?playerId=%v&currency=%v", ts.URL, tc.playerID, tc.currency), nil)
 			assert.Equal(t, tc.err, err)
 			res, err := client.Do(req)
 			assert.Equal(t, tc.err, err)
 			assert.Equal(t, tc.expectedStatus, res.StatusCode)
 			decoder := json.NewDecoder(res.Body)
-			if tc.expectedPlayer != (entity.Player{}) {
-				var player entity.Player
-				err = decoder.Decode(&player)
+			if tc.expectedBalance.ID != "" {
+				var balance entity.Balance
+				err = decoder.Decode(&balance)
 				assert.Equal(t, tc.err, err)
-				assert.Equal(t, tc.expectedPlayer, player)
+				assert.Equal(t, tc.expectedBalance, balance)
 			}
 			if tc.expectedError != (errors.Error{}) {
 				var expErr errors.Error
@@ -210,171 +304,1894 @@ func TestHandlers_BalanceHandler(t *testing.T) {
 	}
 }
 
-func TestHandlers_AnnounceHandler(t *testing.T) {
-	tournaments := []entity.Tournament{
-		{ID: "announce_ok_and_duplicated", Deposit: 100},
-	}
-	controller.On("AnnounceTournament", tournaments[0].ID, tournaments[0].Deposit).Return(nil).Once()
-	controller.On("AnnounceTournament", tournaments[0].ID, tournaments[0].Deposit).Return(errors.Error{Code: errors.DuplicatedIDError})
+func TestHandlers_HoldHandler(t *testing.T) {
+	hold := entity.Hold{ID: "hold_ok", PlayerID: "hold_player", Points: 100, Expires: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	controller.On("Hold", hold.ID, hold.PlayerID, "", 100, time.Minute).Return(hold, nil)
+	controller.On("Hold", hold.ID, hold.PlayerID, "", 100, defaultHoldTTL).Return(hold, nil)
+	controller.On("Hold", hold.ID, hold.PlayerID, "", 1000, time.Minute).Return(entity.Hold{}, errors.Error{Code: errors.NegativePointsNumberError})
 	client := http.Client{}
 	tt := []struct {
 		name           string
-		tournamentID   string
-		deposit        interface{}
+		points         interface{}
+		ttl            string
 		err            error
 		expectedError  errors.Error
 		expectedStatus int
 	}{
 		{
-			name:           "announce: ok",
-			tournamentID:   tournaments[0].ID,
-			deposit:        tournaments[0].Deposit,
+			name:           "hold: ok",
+			points:         100,
+			ttl:            "1m",
 			expectedError:  errors.Error{},
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusCreated,
 		},
 		{
-			name:           "announce: duplicated id",
-			tournamentID:   tournaments[0].ID,
-			deposit:        tournaments[0].Deposit,
-			expectedError:  errors.Error{Code: errors.DuplicatedIDError},
+			name:           "hold: default ttl",
+			points:         100,
+			ttl:            "",
+			expectedError:  errors.Error{},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "hold: not enough points",
+			points:         1000,
+			ttl:            "1m",
+			expectedError:  errors.Error{Code: errors.NegativePointsNumberError},
 			expectedStatus: http.StatusNotFound,
 		},
 		{
-			name:           "announce: incorrect deposit",
-			tournamentID:   tournaments[0].ID,
-			deposit:        "incorrect_deposit",
-			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot create tournament, deposit is not number: incorrect_deposit", Info: "strconv.Atoi: parsing \"incorrect_deposit\": invalid syntax"},
+			name:           "hold: incorrect ttl",
+			points:         100,
+			ttl:            "incorrect_format",
+			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot hold points, ttl is not duration: incorrect_format", Info: "time: invalid duration \"incorrect_format\""},
 			expectedStatus: http.StatusNotFound,
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/announceTournament?tournamentId=%v&deposit=%v", ts.URL, tc.tournamentID, tc.deposit), nil)
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/hold?holdId=%v&playerId=%v&points=%v&ttl=%v", ts.URL, hold.ID, hold.PlayerID, tc.points, tc.ttl), nil)
 			assert.Equal(t, tc.err, err)
 			res, err := client.Do(req)
 			assert.Equal(t, tc.err, err)
 			assert.Equal(t, tc.expectedStatus, res.StatusCode)
-			if tc.expectedStatus != http.StatusOK {
-				decoder := json.NewDecoder(res.Body)
-				var expErr errors.Error
-				err = decoder.Decode(&expErr)
+			decoder := json.NewDecoder(res.Body)
+			if tc.expectedStatus == http.StatusCreated {
+				var h entity.Hold
+				err = decoder.Decode(&h)
 				assert.Equal(t, tc.err, err)
-				assert.Equal(t, tc.expectedError, expErr)
+				assert.Equal(t, hold, h)
+				return
 			}
+			var expErr errors.Error
+			err = decoder.Decode(&expErr)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedError, expErr)
 		})
 	}
 }
 
-func TestHandlers_JoinHandler(t *testing.T) {
-	tournaments := []entity.Tournament{
-		{ID: "join_ok", Deposit: 100},
-		{ID: "join_unexpected", Deposit: 100},
+func TestHandlers_CaptureReleaseHandler(t *testing.T) {
+	controller.On("Capture", "capture_ok").Return(nil)
+	controller.On("Capture", "capture_expired").Return(errors.Error{Code: errors.NotFoundError})
+	controller.On("Release", "release_ok").Return(nil)
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "capture: ok", path: "/capture?holdId=capture_ok", expectedStatus: http.StatusOK},
+		{name: "capture: expired", path: "/capture?holdId=capture_expired", expectedStatus: http.StatusNotFound},
+		{name: "release: ok", path: "/release?holdId=release_ok", expectedStatus: http.StatusOK},
 	}
-	players := []entity.Player{
-		{ID: "join_ok", Points: 350},
-		{ID: "join_unexpected", Points: 200},
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, ts.URL+tc.path, nil)
+			assert.Nil(t, err)
+			res, err := client.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+		})
 	}
-	controller.On("JoinTournament", tournaments[0].ID, players[0].ID).Return(nil)
-	controller.On("JoinTournament", tournaments[1].ID, players[1].ID).Return(e.New("unexpected"))
+}
+
+func TestHandlers_PromoHandler(t *testing.T) {
+	validTo := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	promo := entity.Promo{Code: "promo_ok", Points: 100, MaxUses: 10, ValidTo: validTo, NewPlayersOnly: true}
+	controller.On("CreatePromo", promo).Return(promo, nil)
+	controller.On("CreatePromo", entity.Promo{Code: "promo_empty"}).Return(entity.Promo{}, errors.Error{Code: errors.InvalidPromoError})
+	red := entity.Redemption{Code: "promo_ok", PlayerID: "promo_player", Use: 1, Points: 100}
+	controller.On("Redeem", "promo_ok", "promo_player").Return(red, nil)
+	controller.On("Redeem", "promo_expired", "promo_player").Return(entity.Redemption{}, errors.Error{Code: errors.PromoUnavailableError})
 	client := http.Client{}
 	tt := []struct {
 		name           string
-		tourID         string
-		playerID       string
-		err            error
-		expectedError  errors.Error
+		path           string
+		expected       interface{}
 		expectedStatus int
 	}{
 		{
-			name:           "join: ok",
-			tourID:         tournaments[0].ID,
-			playerID:       players[0].ID,
-			expectedError:  errors.Error{},
+			name:           "create promo: ok",
+			path:           "/createPromo?code=promo_ok&points=100&maxUses=10&validTo=2030-01-01T00:00:00Z&newPlayersOnly=true",
+			expected:       promo,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "create promo: no reward",
+			path:           "/createPromo?code=promo_empty",
+			expected:       errors.Error{Code: errors.InvalidPromoError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "create promo: incorrect points",
+			path:           "/createPromo?code=promo_ok&points=incorrect_format",
+			expected:       errors.Error{Code: errors.NotNumberError, Message: "cannot create promo, points is not number: incorrect_format", Info: "strconv.Atoi: parsing \"incorrect_format\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "create promo: incorrect time",
+			path:           "/createPromo?code=promo_ok&points=100&validTo=tomorrow",
+			expected:       errors.Error{Code: errors.NotNumberError, Message: "cannot create promo, validTo is not RFC3339 time: tomorrow", Info: "parsing time \"tomorrow\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"tomorrow\" as \"2006\""},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "redeem: ok",
+			path:           "/redeem?code=promo_ok&playerId=promo_player",
+			expected:       red,
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name:           "join: unexpected error",
-			tourID:         tournaments[1].ID,
-			playerID:       players[1].ID,
-			expectedError:  errors.Error{Code: "UnknownError", Message: "unexpected"},
-			expectedStatus: http.StatusInternalServerError,
+			name:           "redeem: expired",
+			path:           "/redeem?code=promo_expired&playerId=promo_player",
+			expected:       errors.Error{Code: errors.PromoUnavailableError},
+			expectedStatus: http.StatusNotFound,
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/joinTournament?tournamentId=%v&playerId=%v", ts.URL, tc.tourID, tc.playerID), nil)
-			assert.Equal(t, tc.err, err)
+			req, err := http.NewRequest(http.MethodPut, ts.URL+tc.path, nil)
+			assert.Nil(t, err)
 			res, err := client.Do(req)
-			assert.Equal(t, tc.err, err)
+			assert.Nil(t, err)
 			assert.Equal(t, tc.expectedStatus, res.StatusCode)
-			if tc.expectedStatus != http.StatusOK {
-				decoder := json.NewDecoder(res.Body)
-				var expErr errors.Error
-				err = decoder.Decode(&expErr)
-				assert.Equal(t, tc.err, err)
-				assert.Equal(t, tc.expectedError, expErr)
+			decoder := json.NewDecoder(res.Body)
+			switch expected := tc.expected.(type) {
+			case entity.Promo:
+				var p entity.Promo
+				assert.Nil(t, decoder.Decode(&p))
+				assert.Equal(t, expected, p)
+			case entity.Redemption:
+				var r entity.Redemption
+				assert.Nil(t, decoder.Decode(&r))
+				assert.Equal(t, expected, r)
+			default:
+				var e errors.Error
+				assert.Nil(t, decoder.Decode(&e))
+				assert.Equal(t, expected, e)
 			}
 		})
 	}
 }
 
-func TestHandlers_ResultHandler(t *testing.T) {
-	tournaments := []entity.Tournament{
-		{ID: "result_ok", Deposit: 100},
-		{ID: "result_none_participants", Deposit: 100},
+func TestHandlers_LimitsHandler(t *testing.T) {
+	limits := entity.Limits{PlayerID: "limits_player", Daily: 100, Weekly: 500}
+	excluded := entity.Limits{PlayerID: "limits_player", ExcludedUntil: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	controller.On("Limits", limits.PlayerID).Return(limits, nil)
+	controller.On("SetLimits", limits.PlayerID, 100, 500).Return(limits, nil)
+	controller.On("SelfExclude", limits.PlayerID, 30*24*time.Hour).Return(excluded, nil)
+	controller.On("Take", "limits_excluded", "", 100).Return(errors.Error{Code: errors.SelfExcludedError})
+	controller.On("JoinTournament", "limits_tournament", "limits_player").Return(errors.Error{Code: errors.LimitExceededError})
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		path           string
+		expected       interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "limits: ok",
+			path:           "/limits?playerId=limits_player",
+			expected:       limits,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "set limits: ok",
+			path:           "/setLimits?playerId=limits_player&daily=100&weekly=500",
+			expected:       limits,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "set limits: incorrect daily",
+			path:           "/setLimits?playerId=limits_player&daily=incorrect_format",
+			expected:       errors.Error{Code: errors.NotNumberError, Message: "cannot set limits, daily is not number: incorrect_format", Info: "strconv.Atoi: parsing \"incorrect_format\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "self exclude: ok",
+			path:           "/selfExclude?playerId=limits_player&days=30",
+			expected:       excluded,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "take: self-excluded",
+			path:           "/take?playerId=limits_excluded&points=100",
+			expected:       errors.Error{Code: errors.SelfExcludedError},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "join: limit exceeded",
+			path:           "/joinTournament?tournamentId=limits_tournament&playerId=limits_player",
+			expected:       errors.Error{Code: errors.LimitExceededError},
+			expectedStatus: http.StatusForbidden,
+		},
 	}
-	winners := []entity.Winners{
-		{Winners: []entity.Winner{entity.Winner{ID: "result_ok"}}},
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, ts.URL+tc.path, nil)
+			assert.Nil(t, err)
+			res, err := client.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			decoder := json.NewDecoder(res.Body)
+			if expected, ok := tc.expected.(entity.Limits); ok {
+				var l entity.Limits
+				assert.Nil(t, decoder.Decode(&l))
+				assert.Equal(t, expected, l)
+				return
+			}
+			var e errors.Error
+			assert.Nil(t, decoder.Decode(&e))
+			assert.Equal(t, tc.expected, e)
+		})
 	}
-	controller.On("Results", tournaments[0].ID).Return(winners[0], nil)
-	controller.On("Results", tournaments[1].ID).Return(entity.Winners{}, errors.Error{Code: errors.NoneParticipantsError})
+}
+
+func TestHandlers_AccountsHandler(t *testing.T) {
+	account := entity.Account{ID: "accounts_player", Name: "Player", Created: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Metadata: map[string]string{"country": "DE"}, Status: entity.StatusActive}
+	suspended := account
+	suspended.Status = entity.StatusSuspended
+	controller.On("Register", account.ID, account.Name, account.Metadata).Return(account, nil)
+	controller.On("Account", account.ID).Return(account, nil)
+	controller.On("Suspend", account.ID).Return(suspended, nil)
+	controller.On("Reinstate", account.ID).Return(account, nil)
+	controller.On("Suspend", "accounts_closed").Return(entity.Account{}, errors.Error{Code: errors.InactiveAccountError})
+	closure := entity.Closure{PlayerID: account.ID, Withdrawn: []string{"accounts_tournament"}, Payouts: []entity.Player{{ID: account.ID, Points: 100, Currency: entity.DefaultCurrency}}}
+	controller.On("CloseAccount", account.ID, true).Return(closure, nil)
+	controller.On("CloseAccount", account.ID, false).Return(entity.Closure{}, errors.Error{Code: errors.OpenEntriesError})
 	client := http.Client{}
 	tt := []struct {
-		name            string
-		tourID          string
-		err             error
-		expectedWinners entity.Winners
-		expectedError   error
-		expectedStatus  int
+		name           string
+		path           string
+		expected       interface{}
+		expectedStatus int
 	}{
 		{
-			name:            "result: ok",
-			tourID:          tournaments[0].ID,
-			expectedWinners: winners[0],
-			expectedError:   errors.Error{},
-			expectedStatus:  http.StatusOK,
+			name:           "register: ok",
+			path:           "/registerPlayer?playerId=accounts_player&name=Player&meta.country=DE",
+			expected:       account,
+			expectedStatus: http.StatusCreated,
 		},
 		{
-			name:            "result: none participants",
-			tourID:          tournaments[1].ID,
-			expectedWinners: entity.Winners{},
-			expectedError:   errors.Error{Code: errors.NoneParticipantsError},
-			expectedStatus:  http.StatusOK,
+			name:           "player: ok",
+			path:           "/player?playerId=accounts_player",
+			expected:       account,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "suspend: ok",
+			path:           "/suspendPlayer?playerId=accounts_player",
+			expected:       suspended,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "reinstate: ok",
+			path:           "/reinstatePlayer?playerId=accounts_player",
+			expected:       account,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "close: ok",
+			path:           "/closePlayer?playerId=accounts_player&withdraw=true",
+			expected:       closure,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "close: open entries",
+			path:           "/closePlayer?playerId=accounts_player",
+			expected:       errors.Error{Code: errors.OpenEntriesError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "suspend: closed account",
+			path:           "/suspendPlayer?playerId=accounts_closed",
+			expected:       errors.Error{Code: errors.InactiveAccountError},
+			expectedStatus: http.StatusForbidden,
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/resultTournament?tournamentId=%v", ts.URL, tc.tourID), nil)
-			assert.Equal(t, tc.err, err)
+			req, err := http.NewRequest(http.MethodPut, ts.URL+tc.path, nil)
+			assert.Nil(t, err)
 			res, err := client.Do(req)
-			assert.Equal(t, tc.err, err)
+			assert.Nil(t, err)
 			assert.Equal(t, tc.expectedStatus, res.StatusCode)
 			decoder := json.NewDecoder(res.Body)
-			if tc.expectedWinners.Winners != nil {
-				var winner entity.Winners
-				err = decoder.Decode(&winner)
-				assert.Equal(t, tc.err, err)
-				assert.Equal(t, tc.expectedWinners, winner)
-			}
-			if tc.expectedError != (errors.Error{}) {
-				var expErr errors.Error
-				err = decoder.Decode(&expErr)
-				assert.Equal(t, tc.err, err)
-				assert.Equal(t, tc.expectedError, expErr)
+			switch expected := tc.expected.(type) {
+			case entity.Account:
+				var a entity.Account
+				assert.Nil(t, decoder.Decode(&a))
+				assert.Equal(t, expected, a)
+			case entity.Closure:
+				var c entity.Closure
+				assert.Nil(t, decoder.Decode(&c))
+				assert.Equal(t, expected, c)
+			default:
+				var e errors.Error
+				assert.Nil(t, decoder.Decode(&e))
+				assert.Equal(t, expected, e)
 			}
 		})
 	}
 }
+
+func TestHandlers_ListHandler(t *testing.T) {
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	players := entity.PlayerPage{Players: []entity.Account{{ID: "list_player", Name: "Player", Created: from, Status: entity.StatusActive}}, Next: "next_cursor"}
+	tours := entity.TourPage{Tournaments: []entity.Tournament{{ID: "list_tour", Deposit: 100, Participants: []string{"list_player"}, IsOpen: true, Created: from}}}
+	controller.On("ListPlayers", entity.PlayerFilter{Status: entity.StatusActive, CreatedFrom: from, Sort: "name", Desc: true, Limit: 10}, "cursor").Return(players, nil)
+	controller.On("ListTournaments", entity.TourFilter{Status: "open", MinDeposit: 50, MaxDeposit: 150, ParticipantID: "list_player"}, "").Return(tours, nil)
+	controller.On("ListTournaments", entity.TourFilter{Sort: "prize"}, "").Return(entity.TourPage{}, errors.Error{Code: errors.InvalidFilterError})
+	closed := from.Add(time.Hour)
+	details := entity.Tournament{ID: "list_tour", Deposit: 100, Prize: 100, Participants: []string{"list_player"}, Entries: []entity.Entry{{PlayerID: "list_player", Number: 1}},
+		Winner: entity.Winner{ID: "list_player", Points: 100, Prize: 100, Entry: 1}, Created: from, Closed: &closed}
+	controller.On("Tournament", details.ID).Return(details, nil)
+	controller.On("Tournament", "list_fake").Return(entity.Tournament{}, errors.Error{Code: errors.NotFoundError})
+	history := entity.History{PlayerID: "list_player", Tournaments: []entity.Participation{
+		{TournamentID: "list_tour", PlayerID: "list_player", Currency: entity.DefaultCurrency, Paid: 100, Placing: 1, Prize: 100, Joined: from}}}
+	controller.On("History", "list_player").Return(history, nil)
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		method         string
+		path           string
+		expected       interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "players: ok",
+			method:         http.MethodGet,
+			path:           "/players?status=active&createdFrom=2030-01-01T00:00:00Z&sort=name&order=desc&limit=10&cursor=cursor",
+			expected:       players,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "tournaments: ok",
+			method:         http.MethodGet,
+			path:           "/tournaments?status=open&minDeposit=50&maxDeposit=150&participantId=list_player",
+			expected:       tours,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "tournaments: invalid sort",
+			method:         http.MethodGet,
+			path:           "/tournaments?sort=prize",
+			expected:       errors.Error{Code: errors.InvalidFilterError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "tournaments: incorrect deposit",
+			method:         http.MethodGet,
+			path:           "/tournaments?minDeposit=incorrect_format",
+			expected:       errors.Error{Code: errors.NotNumberError, Message: "cannot list tournaments, minDeposit is not number: incorrect_format", Info: "strconv.Atoi: parsing \"incorrect_format\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "tournaments: several incorrect parameters",
+			method:         http.MethodGet,
+			path:           "/tournaments?createdTo=tomorrow&limit=ten&maxDeposit=incorrect_format",
+			expected:       errors.Error{Code: errors.NotNumberError, Message: "cannot list tournaments, maxDeposit is not number: incorrect_format", Info: "strconv.Atoi: parsing \"incorrect_format\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "players: not allowed method",
+			method:         http.MethodPost,
+			path:           "/players",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "tournament: ok",
+			method:         http.MethodGet,
+			path:           "/tournaments/list_tour",
+			expected:       details,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "tournament: not found",
+			method:         http.MethodGet,
+			path:           "/tournaments/list_fake",
+			expected:       errors.Error{Code: errors.NotFoundError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "tournament: not allowed method",
+			method:         http.MethodPost,
+			path:           "/tournaments/list_tour",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "history: ok",
+			method:         http.MethodGet,
+			path:           "/players/list_player/tournaments",
+			expected:       history,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, ts.URL+tc.path, nil)
+			assert.Nil(t, err)
+			res, err := client.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			decoder := json.NewDecoder(res.Body)
+			switch expected := tc.expected.(type) {
+			case entity.PlayerPage:
+				var p entity.PlayerPage
+				assert.Nil(t, decoder.Decode(&p))
+				assert.Equal(t, expected, p)
+			case entity.TourPage:
+				var p entity.TourPage
+				assert.Nil(t, decoder.Decode(&p))
+				assert.Equal(t, expected, p)
+			case entity.Tournament:
+				var tour entity.Tournament
+				assert.Nil(t, decoder.Decode(&tour))
+				assert.Equal(t, expected, tour)
+			case entity.History:
+				var h entity.History
+				assert.Nil(t, decoder.Decode(&h))
+				assert.Equal(t, expected, h)
+			case errors.Error:
+				var e errors.Error
+				assert.Nil(t, decoder.Decode(&e))
+				assert.Equal(t, expected, e)
+			}
+		})
+	}
+}
+
+func TestHandlers_AnnounceHandler(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "announce_ok_and_duplicated", Deposit: 100},
+	}
+	controller.On("AnnounceTournament", tournaments[0].ID, "", tournaments[0].Deposit, 1).Return(nil).Once()
+	controller.On("AnnounceTournament", tournaments[0].ID, "", tournaments[0].Deposit, 1).Return(errors.Error{Code: errors.DuplicatedIDError})
+	controller.On("AnnounceTournament", "announce_reentry", "", tournaments[0].Deposit, 3).Return(nil)
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		tournamentID   string
+		deposit        interface{}
+		maxEntries     string
+		err            error
+		expectedError  errors.Error
+		expectedStatus int
+	}{
+		{
+			name:           "announce: ok",
+			tournamentID:   tournaments[0].ID,
+			deposit:        tournaments[0].Deposit,
+			expectedError:  errors.Error{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "announce: re-entry",
+			tournamentID:   "announce_reentry",
+			deposit:        tournaments[0].Deposit,
+			maxEntries:     "3",
+			expectedError:  errors.Error{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "announce: incorrect max entries",
+			tournamentID:   "announce_reentry",
+			deposit:        tournaments[0].Deposit,
+			maxEntries:     "incorrect_entries",
+			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot create tournament, max entries is not number: incorrect_entries", Info: "strconv.Atoi: parsing \"incorrect_entries\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "announce: duplicated id",
+			tournamentID:   tournaments[0].ID,
+			deposit:        tournaments[0].Deposit,
+			expectedError:  errors.Error{Code: errors.DuplicatedIDError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "announce: incorrect deposit",
+			tournamentID:   tournaments[0].ID,
+			deposit:        "incorrect_deposit",
+			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot create tournament, deposit is not number: incorrect_deposit", Info: "strconv.Atoi: parsing \"incorrect_deposit\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("%v/announceTournament?tournamentId=%v&deposit=%v", ts.URL, tc.tournamentID, tc.deposit)
+			if tc.maxEntries != "" {
+				url += "&maxEntries=" + tc.maxEntries
+			}
+			req, err := http.NewRequest(http.MethodPut, url, nil)
+			assert.Equal(t, tc.err, err)
+			res, err := client.Do(req)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				decoder := json.NewDecoder(res.Body)
+				var expErr errors.Error
+				err = decoder.Decode(&expErr)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedError, expErr)
+			}
+		})
+	}
+}
+
+func TestHandlers_AnnounceSatelliteHandler(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "announce_satellite_ok", Deposit: 10, Satellite: entity.Satellite{TargetID: "announce_satellite_target", Seats: 2}},
+		{ID: "announce_satellite_closed", Deposit: 10, Satellite: entity.Satellite{TargetID: "announce_satellite_closed_target", Seats: 1}},
+	}
+	controller.On("AnnounceSatellite", tournaments[0].ID, tournaments[0].Deposit, tournaments[0].Satellite.TargetID, tournaments[0].Satellite.Seats).Return(nil)
+	controller.On("AnnounceSatellite", tournaments[1].ID, tournaments[1].Deposit, tournaments[1].Satellite.TargetID, tournaments[1].Satellite.Seats).Return(errors.Error{Code: errors.ClosedTournamentError})
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		tournamentID   string
+		targetID       string
+		seats          interface{}
+		err            error
+		expectedError  errors.Error
+		expectedStatus int
+	}{
+		{
+			name:           "announce satellite: ok",
+			tournamentID:   tournaments[0].ID,
+			targetID:       tournaments[0].Satellite.TargetID,
+			seats:          tournaments[0].Satellite.Seats,
+			expectedError:  errors.Error{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "announce satellite: closed target",
+			tournamentID:   tournaments[1].ID,
+			targetID:       tournaments[1].Satellite.TargetID,
+			seats:          tournaments[1].Satellite.Seats,
+			expectedError:  errors.Error{Code: errors.ClosedTournamentError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "announce satellite: incorrect seats",
+			tournamentID:   tournaments[0].ID,
+			targetID:       tournaments[0].Satellite.TargetID,
+			seats:          "incorrect_seats",
+			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot create satellite, seats is not number: incorrect_seats", Info: "strconv.Atoi: parsing \"incorrect_seats\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/announceTournament?tournamentId=%v&deposit=10&targetId=%v&seats=%v", ts.URL, tc.tournamentID, tc.targetID, tc.seats), nil)
+			assert.Equal(t, tc.err, err)
+			res, err := client.Do(req)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				decoder := json.NewDecoder(res.Body)
+				var expErr errors.Error
+				err = decoder.Decode(&expErr)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedError, expErr)
+			}
+		})
+	}
+}
+
+func TestHandlers_JoinHandler(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "join_ok", Deposit: 100},
+		{ID: "join_unexpected", Deposit: 100},
+	}
+	players := []entity.Player{
+		{ID: "join_ok", Points: 350},
+		{ID: "join_unexpected", Points: 200},
+	}
+	controller.On("JoinTournament", tournaments[0].ID, players[0].ID).Return(nil)
+	controller.On("JoinTournament", tournaments[1].ID, players[1].ID).Return(e.New("unexpected"))
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		tourID         string
+		playerID       string
+		err            error
+		expectedError  errors.Error
+		expectedStatus int
+	}{
+		{
+			name:           "join: ok",
+			tourID:         tournaments[0].ID,
+			playerID:       players[0].ID,
+			expectedError:  errors.Error{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "join: unexpected error",
+			tourID:         tournaments[1].ID,
+			playerID:       players[1].ID,
+			expectedError:  errors.Error{Code: "UnknownError", Message: "unexpected"},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/joinTournament?tournamentId=%v&playerId=%v", ts.URL, tc.tourID, tc.playerID), nil)
+			assert.Equal(t, tc.err, err)
+			res, err := client.Do(req)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				decoder := json.NewDecoder(res.Body)
+				var expErr errors.Error
+				err = decoder.Decode(&expErr)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedError, expErr)
+			}
+		})
+	}
+}
+
+func TestHandlers_CreateTeamHandler(t *testing.T) {
+	teams := []entity.Team{
+		{ID: "createteam_ok", Captain: "createteam_1", Members: []entity.TeamMember{{PlayerID: "createteam_1", Share: 60}, {PlayerID: "createteam_2", Share: 40}}},
+	}
+	controller.On("CreateTeam", teams[0].ID, teams[0].Captain, []string{"createteam_1", "createteam_2"}, []int{60, 40}).Return(teams[0], nil)
+	controller.On("CreateTeam", "createteam_sum", "", []string{"createteam_1"}, []int{60}).Return(entity.Team{}, errors.Error{Code: errors.InvalidSplitError})
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		query          string
+		err            error
+		expectedTeam   entity.Team
+		expectedError  errors.Error
+		expectedStatus int
+	}{
+		{
+			name:           "create team: ok",
+			query:          "teamId=createteam_ok&captain=createteam_1&members=createteam_1,createteam_2&shares=60,40",
+			expectedTeam:   teams[0],
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "create team: invalid split",
+			query:          "teamId=createteam_sum&members=createteam_1&shares=60",
+			expectedError:  errors.Error{Code: errors.InvalidSplitError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "create team: incorrect share",
+			query:          "teamId=createteam_ok&members=createteam_1&shares=incorrect_share",
+			expectedError:  errors.Error{Code: errors.NotNumberError, Message: "cannot create team, share is not number: incorrect_share", Info: "strconv.Atoi: parsing \"incorrect_share\": invalid syntax"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/createTeam?%v", ts.URL, tc.query), nil)
+			assert.Equal(t, tc.err, err)
+			res, err := client.Do(req)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			decoder := json.NewDecoder(res.Body)
+			if tc.expectedStatus == http.StatusCreated {
+				var team entity.Team
+				err = decoder.Decode(&team)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedTeam, team)
+				return
+			}
+			var expErr errors.Error
+			err = decoder.Decode(&expErr)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedError, expErr)
+		})
+	}
+}
+
+func TestHandlers_JoinTeamHandler(t *testing.T) {
+	controller.On("JoinTeam", "jointeam_ok", "jointeam_1", true).Return(nil)
+	controller.On("JoinTeam", "jointeam_ok", "jointeam_2", false).Return(errors.Error{Code: errors.DuplicatedIDError})
+	client := http.Client{}
+	tt := []struct {
+		name           string
+		query          string
+		err            error
+		expectedError  errors.Error
+		expectedStatus int
+	}{
+		{
+			name:           "join team: captain pays",
+			query:          "tournamentId=jointeam_ok&teamId=jointeam_1&payer=captain",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "join team: duplicated team",
+			query:          "tournamentId=jointeam_ok&teamId=jointeam_2",
+			expectedError:  errors.Error{Code: errors.DuplicatedIDError},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/joinTournament?%v", ts.URL, tc.query), nil)
+			assert.Equal(t, tc.err, err)
+			res, err := client.Do(req)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				decoder := json.NewDecoder(res.Body)
+				var expErr errors.Error
+				err = decoder.Decode(&expErr)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedError, expErr)
+			}
+		})
+	}
+}
+
+func TestHandlers_ResultHandler(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "result_ok", Deposit: 100},
+		{ID: "result_none_participants", Deposit: 100},
+	}
+	winners := []entity.Winners{
+		{Winners: []entity.Winner{entity.Winner{ID: "result_ok"}}},
+	}
+	controller.On("Results", tournaments[0].ID).Return(winners[0], nil)
+	controller.On("Results", tournaments[1].ID).Return(entity.Winners{}, errors.Error{Code: errors.NoneParticipantsError})
+	client := http.Client{}
+	tt := []struct {
+		name            string
+		tourID          string
+		err             error
+		expectedWinners entity.Winners
+		expectedError   error
+		expectedStatus  int
+	}{
+		{
+			name:            "result: ok",
+			tourID:          tournaments[0].ID,
+			expectedWinners: winners[0],
+			expectedError:   errors.Error{},
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "result: none participants",
+			tourID:          tournaments[1].ID,
+			expectedWinners: entity.Winners{},
+			expectedError:   errors.Error{Code: errors.NoneParticipantsError},
+			expectedStatus:  http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/resultTournament?tournamentId=%v", ts.URL, tc.tourID), nil)
+			assert.Equal(t, tc.err, err)
+			res, err := client.Do(req)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			decoder := json.NewDecoder(res.Body)
+			if tc.expectedWinners.Winners != nil {
+				var winner entity.Winners
+				err = decoder.Decode(&winner)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedWinners, winner)
+			}
+			if tc.expectedError != (errors.Error{}) {
+				var expErr errors.Error
+				err = decoder.Decode(&expErr)
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.expectedError, expErr)
+			}
+		})
+	}
+}
+
+func TestHandlers_V2Handler(t *testing.T) {
+	newPlayer := entity.Player{ID: "v2_new", Points: 100, Currency: entity.DefaultCurrency}
+	controller.On("Fund", newPlayer.ID, entity.DefaultCurrency, 100, 24*time.Hour).Return(newPlayer, nil)
+	controller.On("Fund", "v2_player", "", 50, time.Duration(0)).Return(entity.Player{}, nil)
+	controller.On("Fund", "v2_player", "", -50, time.Duration(0)).Return(entity.Player{}, errors.Error{Code: errors.NegativePointsNumberError})
+	controller.On("AnnounceTournament", "v2_tour", "", 100, 1).Return(nil)
+	controller.On("AnnounceTournament", "v2_dup", "", 100, 2).Return(errors.Error{Code: errors.DuplicatedIDError})
+	controller.On("JoinTournament", "v2_tour", "v2_player").Return(nil)
+	controller.On("JoinTournament", "v2_closed", "v2_player").Return(errors.Error{Code: errors.ClosedTournamentError})
+	controller.On("JoinTeam", "v2_team_tour", "v2_team", true).Return(nil)
+	controller.On("Capture", "v2_hold").Return(nil)
+	controller.On("Release", "v2_fake").Return(errors.Error{Code: errors.NotFoundError})
+	client := http.Client{}
+	tt := []struct {
+		name             string
+		method           string
+		path             string
+		body             string
+		expected         interface{}
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "fund: new player",
+			method:           http.MethodPost,
+			path:             "/v2/players/v2_new/fund",
+			body:             `{"points": 100, "currency": "points", "expireDays": 1}`,
+			expected:         newPlayer,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/v2/players/v2_new/balance",
+		},
+		{
+			name:           "fund: existing player",
+			method:         http.MethodPost,
+			path:           "/v2/players/v2_player/fund",
+			body:           `{"points": 50}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "fund: negative points",
+			method:         http.MethodPost,
+			path:           "/v2/players/v2_player/fund",
+			body:           `{"points": -50}`,
+			expected:       errors.Error{Code: errors.NegativePointsNumberError},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "fund: unknown field",
+			method:         http.MethodPost,
+			path:           "/v2/players/v2_player/fund",
+			body:           `{"pts": 50}`,
+			expected:       errors.Error{Code: errors.JSONError, Message: "cannot fund player, request body is not valid json", Info: "json: unknown field \"pts\""},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "fund: not allowed method",
+			method:         http.MethodGet,
+			path:           "/v2/players/v2_player/fund",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:             "tournament: created",
+			method:           http.MethodPost,
+			path:             "/v2/tournaments",
+			body:             `{"id": "v2_tour", "deposit": 100}`,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/v2/tournaments/v2_tour",
+		},
+		{
+			name:           "tournament: duplicated id",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments",
+			body:           `{"id": "v2_dup", "deposit": 100, "maxEntries": 2}`,
+			expected:       errors.Error{Code: errors.DuplicatedIDError},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:             "entries: player joined",
+			method:           http.MethodPost,
+			path:             "/v2/tournaments/v2_tour/entries",
+			body:             `{"playerId": "v2_player"}`,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/v2/tournaments/v2_tour",
+		},
+		{
+			name:             "entries: team joined",
+			method:           http.MethodPost,
+			path:             "/v2/tournaments/v2_team_tour/entries",
+			body:             `{"teamId": "v2_team", "payer": "captain"}`,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/v2/tournaments/v2_team_tour",
+		},
+		{
+			name:           "entries: closed tournament",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/v2_closed/entries",
+			body:           `{"playerId": "v2_player"}`,
+			expected:       errors.Error{Code: errors.ClosedTournamentError},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "entries: empty body",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/v2_tour/entries",
+			expected:       errors.Error{Code: errors.JSONError, Message: "cannot join tournament, request body is not valid json", Info: "EOF"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "holds: captured",
+			method:         http.MethodPost,
+			path:           "/v2/holds/v2_hold/capture",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "holds: release not found",
+			method:         http.MethodDelete,
+			path:           "/v2/holds/v2_fake",
+			expected:       errors.Error{Code: errors.NotFoundError},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "v1: still served",
+			method:         http.MethodGet,
+			path:           "/fund?playerId=v2_player&points=50",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, ts.URL+tc.path, strings.NewReader(tc.body))
+			assert.Nil(t, err)
+			res, err := client.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			assert.Equal(t, tc.expectedLocation, res.Header.Get("Location"))
+			decoder := json.NewDecoder(res.Body)
+			switch expected := tc.expected.(type) {
+			case entity.Player:
+				var p entity.Player
+				assert.Nil(t, decoder.Decode(&p))
+				assert.Equal(t, expected, p)
+			case errors.Error:
+				assert.Equal(t, problemContentType, res.Header.Get("content-type"))
+				var e errors.Error
+				assert.Nil(t, decoder.Decode(&e))
+				assert.Equal(t, expected, e)
+			}
+		})
+	}
+}
+
+func TestHandlers_WebhooksHandler(t *testing.T) {
+	webhook := entity.Webhook{ID: "admin_hook", URL: "https://example.com/hook", Events: []string{entity.EventWinnerChosen}, Secret: "secret",
+		Created: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	controller.On("CreateWebhook", entity.Webhook{ID: "admin_hook", URL: "https://example.com/hook", Events: []string{entity.EventWinnerChosen}}).Return(webhook, nil)
+	controller.On("CreateWebhook", entity.Webhook{ID: "admin_invalid", URL: "/hook", Events: []string{entity.EventWinnerChosen}}).
+		Return(entity.Webhook{}, errors.Error{Code: errors.InvalidWebhookError})
+	controller.On("Webhooks").Return([]entity.Webhook{{ID: webhook.ID, URL: webhook.URL, Events: webhook.Events, Created: webhook.Created}}, nil)
+	controller.On("DeleteWebhook", "admin_hook").Return(nil)
+	controller.On("DeleteWebhook", "admin_fake").Return(errors.Error{Code: errors.NotFoundError})
+	controller.On("DeadDeliveries").Return([]entity.Delivery{{ID: "1", WebhookID: webhook.ID, URL: webhook.URL, Attempts: 8, Dead: true}}, nil)
+	controller.On("RetryDelivery", "1").Return(nil)
+	client := http.Client{}
+	tt := []struct {
+		name             string
+		method           string
+		path             string
+		body             string
+		expectedBody     string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "create webhook: ok",
+			method:           http.MethodPost,
+			path:             "/admin/webhooks",
+			body:             `{"id": "admin_hook", "url": "https://example.com/hook", "events": ["winnerChosen"]}`,
+			expectedBody:     `{"id":"admin_hook","url":"https://example.com/hook","events":["winnerChosen"],"secret":"secret","created":"2030-01-01T00:00:00Z"}`,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/admin/webhooks/admin_hook",
+		},
+		{
+			name:           "create webhook: invalid",
+			method:         http.MethodPost,
+			path:           "/admin/webhooks",
+			body:           `{"id": "admin_invalid", "url": "/hook", "events": ["winnerChosen"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "webhooks: without secrets",
+			method:         http.MethodGet,
+			path:           "/admin/webhooks",
+			expectedBody:   `[{"id":"admin_hook","url":"https://example.com/hook","events":["winnerChosen"],"created":"2030-01-01T00:00:00Z"}]`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "delete webhook: ok",
+			method:         http.MethodDelete,
+			path:           "/admin/webhooks/admin_hook",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "delete webhook: not found",
+			method:         http.MethodDelete,
+			path:           "/admin/webhooks/admin_fake",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "dead deliveries: ok",
+			method: http.MethodGet,
+			path:   "/admin/deliveries/dead",
+			expectedBody: `[{"id":"1","webhookId":"admin_hook","url":"https://example.com/hook","event":{"type":"","created":"0001-01-01T00:00:00Z"},` +
+				`"attempts":8,"nextAttempt":"0001-01-01T00:00:00Z","dead":true}]`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "retry delivery: ok",
+			method:         http.MethodPost,
+			path:           "/admin/deliveries/1/retry",
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, ts.URL+tc.path, strings.NewReader(tc.body))
+			assert.Nil(t, err)
+			res, err := client.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			assert.Equal(t, tc.expectedLocation, res.Header.Get("Location"))
+			if tc.expectedBody != "" {
+				body, err := io.ReadAll(res.Body)
+				assert.Nil(t, err)
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestHandlers_BatchHandler(t *testing.T) {
+	ops := []entity.Operation{
+		{Type: entity.OpFund, PlayerID: "batch_1", Points: 100},
+		{Type: entity.OpJoin, PlayerID: "batch_1", TournamentID: "batch_tournament"},
+	}
+	controller.On("Batch", ops, false).Return(entity.BatchResult{Applied: 1, Failed: 1, Results: []entity.OperationResult{
+		{Status: entity.OperationApplied},
+		{Status: entity.OperationFailed, Error: &errors.Error{Code: errors.ClosedTournamentError, Message: "join tournament: cannot join to closed tournament"}},
+	}}, nil)
+	controller.On("Batch", ops, true).Return(entity.BatchResult{}, errors.Error{Code: errors.TransactionError})
+	controller.On("Batch", []entity.Operation(nil), false).Return(entity.BatchResult{}, errors.Error{Code: errors.InvalidBatchError})
+	body := `{"operations": [{"type": "fund", "playerId": "batch_1", "points": 100}, {"type": "join", "playerId": "batch_1", "tournamentId": "batch_tournament"}]`
+	tt := []struct {
+		name           string
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			name: "batch: independent",
+			body: body + `}`,
+			expectedBody: `{"atomic":false,"applied":1,"failed":1,"results":[{"status":"applied"},` +
+				`{"status":"failed","error":{"code":"closedTournamentError","message":"join tournament: cannot join to closed tournament","info":null}}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "batch: atomic failed as whole",
+			body:           body + `, "atomic": true}`,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "batch: without operations",
+			body:           `{}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "batch: unknown field",
+			body:           `{"operations": [{"type": "fund", "playerId": "batch_1", "expireDays": 1}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := http.Post(ts.URL+"/v2/batch", "application/json", strings.NewReader(tc.body))
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedBody != "" {
+				body, err := io.ReadAll(res.Body)
+				assert.Nil(t, err)
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+// keyStore authenticates api keys, which it keeps in memory
+type keyStore map[string]entity.APIKey
+
+func (k keyStore) Authenticate(key string) (entity.APIKey, error) {
+	if key == "" {
+		return entity.APIKey{}, errors.Error{Code: errors.UnauthorizedError, Message: "authenticate: api key is not set"}
+	}
+	apiKey, ok := k[key]
+	if !ok {
+		return entity.APIKey{}, errors.Error{Code: errors.UnauthorizedError, Message: "authenticate: api key is not valid"}
+	}
+	return apiKey, nil
+}
+
+func TestHandlers_AuthHandler(t *testing.T) {
+	keys := keyStore{
+		"admin_key":  {ID: "auth_admin", Role: entity.RoleAdmin},
+		"client_key": {ID: "auth_client", Role: entity.RoleClient},
+		"read_key":   {ID: "auth_read", Role: entity.RoleReadOnly},
+		"guest_key":  {ID: "auth_guest", Role: "guest"},
+	}
+	as := httptest.NewServer(NewRouter(Server{Controller: controller, Auth: keys}))
+	defer as.Close()
+	controller.On("Balance", "auth_player", "").Return(entity.Balance{ID: "auth_player", Points: 100, Currency: entity.DefaultCurrency}, nil)
+	controller.On("JoinTournament", "auth_tour", "auth_player").Return(nil)
+	controller.On("Fund", "auth_player", "", 100, time.Duration(0)).Return(entity.Player{}, nil)
+	controller.On("Webhooks").Return([]entity.Webhook{}, nil)
+	tt := []struct {
+		name           string
+		method         string
+		path           string
+		key            string
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			name:           "auth: without key",
+			method:         http.MethodGet,
+			path:           "/balance?playerId=auth_player",
+			expectedBody:   `{"code":"unauthorizedError","message":"authenticate: api key is not set","info":null}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "auth: invalid key",
+			method:         http.MethodGet,
+			path:           "/v2/players/auth_player/balance",
+			key:            "fake_key",
+			expectedBody:   `{"code":"unauthorizedError","message":"authenticate: api key is not valid","info":null}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "auth: read-only reads balance",
+			method:         http.MethodGet,
+			path:           "/v2/players/auth_player/balance",
+			key:            "read_key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "auth: read-only cannot join",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/auth_tour/entries",
+			key:            "read_key",
+			body:           `{"playerId": "auth_player"}`,
+			expectedBody:   `{"code":"forbiddenError","message":"authorize: role read-only cannot use /v2/tournaments/auth_tour/entries, id: auth_read","info":"client"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auth: client joins",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/auth_tour/entries",
+			key:            "client_key",
+			body:           `{"playerId": "auth_player"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "auth: client cannot fund",
+			method:         http.MethodPost,
+			path:           "/fund?playerId=auth_player&points=100",
+			key:            "client_key",
+			expectedBody:   `{"code":"forbiddenError","message":"authorize: role client cannot use /fund, id: auth_client","info":"admin"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auth: client cannot take",
+			method:         http.MethodPost,
+			path:           "/take?playerId=auth_player&points=10",
+			key:            "client_key",
+			expectedBody:   `{"code":"forbiddenError","message":"authorize: role client cannot use /take, id: auth_client","info":"admin"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auth: client cannot transfer",
+			method:         http.MethodPost,
+			path:           "/transfer?from=auth_player&to=auth_other&points=10",
+			key:            "client_key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auth: client cannot take in v2",
+			method:         http.MethodPost,
+			path:           "/v2/players/auth_player/take",
+			key:            "client_key",
+			body:           `{"points": 10}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auth: client reads balance",
+			method:         http.MethodGet,
+			path:           "/balance?playerId=auth_player",
+			key:            "client_key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "auth: client cannot manage webhooks",
+			method:         http.MethodGet,
+			path:           "/admin/webhooks",
+			key:            "client_key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auth: admin funds",
+			method:         http.MethodPost,
+			path:           "/fund?playerId=auth_player&points=100",
+			key:            "admin_key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "auth: admin manages webhooks",
+			method:         http.MethodGet,
+			path:           "/admin/webhooks",
+			key:            "admin_key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "auth: unknown role",
+			method:         http.MethodGet,
+			path:           "/balance?playerId=auth_player",
+			key:            "guest_key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auth: public openapi",
+			method:         http.MethodGet,
+			path:           "/openapi.json",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, as.URL+tc.path, strings.NewReader(tc.body))
+			assert.Nil(t, err)
+			if tc.key != "" {
+				req.Header.Set(APIKeyHeader, tc.key)
+			}
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedBody != "" {
+				assert.Equal(t, "application/json", res.Header.Get("content-type"))
+				body, err := io.ReadAll(res.Body)
+				assert.Nil(t, err)
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestHandlers_AuthRouteWithoutTemplate(t *testing.T) {
+	keys := keyStore{
+		"admin_key":  {ID: "auth_admin", Role: entity.RoleAdmin},
+		"client_key": {ID: "auth_client", Role: entity.RoleClient},
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	router := mux.NewRouter()
+	router.MatcherFunc(func(*http.Request, *mux.RouteMatch) bool { return true }).Handler(Server{Auth: keys}.Authenticate(ok))
+	tt := []struct {
+		name           string
+		handler        http.Handler
+		key            string
+		expectedStatus int
+	}{
+		{name: "auth: route without path template, without key", handler: router, expectedStatus: http.StatusUnauthorized},
+		{name: "auth: route without path template, client key", handler: router, key: "client_key", expectedStatus: http.StatusForbidden},
+		{name: "auth: route without path template, admin key", handler: router, key: "admin_key", expectedStatus: http.StatusOK},
+		{name: "auth: without route, client key", handler: Server{Auth: keys}.Authenticate(ok), key: "client_key", expectedStatus: http.StatusForbidden},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/balance", nil)
+			if tc.key != "" {
+				req.Header.Set(APIKeyHeader, tc.key)
+			}
+			w := httptest.NewRecorder()
+			tc.handler.ServeHTTP(w, req)
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandlers_TokenHandler(t *testing.T) {
+	secret := []byte("jwt_secret")
+	sign := func(subject, audience string, expires time.Time) string {
+		claims := jwt.RegisteredClaims{Subject: subject, Audience: jwt.ClaimStrings{audience}, ExpiresAt: jwt.NewNumericDate(expires)}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		assert.Nil(t, err)
+		return token
+	}
+	valid := sign("jwt_player", "tournament", time.Now().Add(time.Hour))
+	tokens := auth.Verifier{HMACKey: secret, Audience: "tournament"}
+	as := httptest.NewServer(NewRouter(Server{Controller: controller, Auth: keyStore{"jwt_admin_key": {ID: "jwt_admin", Role: entity.RoleAdmin}}, Tokens: tokens}))
+	defer as.Close()
+	controller.On("Balance", "jwt_player", "").Return(entity.Balance{ID: "jwt_player", Points: 100, Currency: entity.DefaultCurrency}, nil)
+	controller.On("Balance", "jwt_other", "").Return(entity.Balance{ID: "jwt_other", Points: 100, Currency: entity.DefaultCurrency}, nil)
+	controller.On("JoinTournament", "jwt_tour", "jwt_player").Return(nil)
+	controller.On("History", "jwt_player").Return(entity.History{PlayerID: "jwt_player"}, nil)
+	tt := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		key            string
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			name:           "token: own balance",
+			method:         http.MethodGet,
+			path:           "/v2/players/jwt_player/balance",
+			token:          valid,
+			expectedBody:   `{"id":"jwt_player","points":100,"currency":"points","available":0}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token: v1 own balance",
+			method:         http.MethodGet,
+			path:           "/balance?playerId=jwt_player",
+			token:          valid,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token: balance of other player",
+			method:         http.MethodGet,
+			path:           "/v2/players/jwt_other/balance",
+			token:          valid,
+			expectedBody:   `{"type":"about:blank","title":"Forbidden","status":403,"detail":"authorize: player can use only own id, id: jwt_player","code":"forbiddenError","message":"authorize: player can use only own id, id: jwt_player","info":"jwt_other"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: v1 balance of other player",
+			method:         http.MethodGet,
+			path:           "/balance?playerId=jwt_other",
+			token:          valid,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: own history",
+			method:         http.MethodGet,
+			path:           "/v2/players/jwt_player/tournaments",
+			token:          valid,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token: history of other player",
+			method:         http.MethodGet,
+			path:           "/players/jwt_other/tournaments",
+			token:          valid,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: joins as self",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/jwt_tour/entries",
+			token:          valid,
+			body:           `{"playerId": "jwt_player"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "token: v1 joins as self",
+			method:         http.MethodGet,
+			path:           "/joinTournament?tournamentId=jwt_tour&playerId=jwt_player",
+			token:          valid,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token: joins as other player",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/jwt_tour/entries",
+			token:          valid,
+			body:           `{"playerId": "jwt_other"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: joins team",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/jwt_tour/entries",
+			token:          valid,
+			body:           `{"teamId": "jwt_team"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: joins as self with other team",
+			method:         http.MethodPost,
+			path:           "/v2/tournaments/jwt_tour/entries",
+			token:          valid,
+			body:           `{"playerId": "jwt_player", "teamId": "jwt_other_team"}`,
+			expectedBody:   `{"type":"about:blank","title":"Forbidden","status":403,"detail":"authorize: player cannot join team, id: jwt_player","code":"forbiddenError","message":"authorize: player cannot join team, id: jwt_player","info":"jwt_other_team"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: v1 joins as self with other team",
+			method:         http.MethodGet,
+			path:           "/joinTournament?tournamentId=jwt_tour&playerId=jwt_player&teamId=jwt_other_team&payer=captain",
+			token:          valid,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: cannot use other routes",
+			method:         http.MethodGet,
+			path:           "/take?playerId=jwt_player&points=10",
+			token:          valid,
+			expectedBody:   `{"code":"forbiddenError","message":"authorize: player cannot use /take, id: jwt_player","info":"admin"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token: expired",
+			method:         http.MethodGet,
+			path:           "/v2/players/jwt_player/balance",
+			token:          sign("jwt_player", "tournament", time.Now().Add(-time.Hour)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "token: wrong audience",
+			method:         http.MethodGet,
+			path:           "/v2/players/jwt_player/balance",
+			token:          sign("jwt_player", "other", time.Now().Add(time.Hour)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "token: api key reads other player",
+			method:         http.MethodGet,
+			path:           "/v2/players/jwt_other/balance",
+			key:            "jwt_admin_key",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, as.URL+tc.path, strings.NewReader(tc.body))
+			assert.Nil(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			if tc.key != "" {
+				req.Header.Set(APIKeyHeader, tc.key)
+			}
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedBody != "" {
+				body, err := io.ReadAll(res.Body)
+				assert.Nil(t, err)
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestHandlers_RateLimitHandler(t *testing.T) {
+	limiter := ratelimit.Limiter{Store: ratelimit.NewMemory(), Rules: map[string]ratelimit.Rule{
+		ratelimit.ClassJoin: {Rate: 0.5, Burst: 1}, ratelimit.ClassResults: {Rate: 0.5, Burst: 1},
+	}}
+	keys := keyStore{"rate_key": {ID: "rate_client", Role: entity.RoleAdmin}, "rate_other_key": {ID: "rate_other", Role: entity.RoleAdmin}}
+	rs := httptest.NewServer(NewRouter(Server{Controller: controller, Auth: keys, RateLimit: limiter}))
+	defer rs.Close()
+	controller.On("JoinTournament", "rate_tour", "rate_player").Return(nil)
+	controller.On("Results", "rate_tour").Return(entity.Winners{Winners: []entity.Winner{}}, nil)
+	controller.On("Balance", "rate_player", "").Return(entity.Balance{ID: "rate_player", Points: 100, Currency: entity.DefaultCurrency}, nil)
+	tt := []struct {
+		name               string
+		method             string
+		path               string
+		key                string
+		body               string
+		expectedStatus     int
+		expectedRetryAfter string
+		expectedBody       string
+	}{
+		{name: "rate limit: join", method: http.MethodPost, path: "/v2/tournaments/rate_tour/entries", key: "rate_key", body: `{"playerId": "rate_player"}`, expectedStatus: http.StatusCreated},
+		{
+			name:               "rate limit: join over limit",
+			method:             http.MethodGet,
+			path:               "/joinTournament?tournamentId=rate_tour&playerId=rate_player",
+			key:                "rate_key",
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "2",
+			expectedBody:       `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"rate limit: too many join requests, id: key:rate_client","code":"rateLimitError","message":"rate limit: too many join requests, id: key:rate_client","info":2}`,
+		},
+		{name: "rate limit: other key joins", method: http.MethodGet, path: "/joinTournament?tournamentId=rate_tour&playerId=rate_player", key: "rate_other_key", expectedStatus: http.StatusOK},
+		{name: "rate limit: results have own bucket", method: http.MethodGet, path: "/resultTournament?tournamentId=rate_tour", key: "rate_key", expectedStatus: http.StatusOK},
+		{name: "rate limit: results over limit", method: http.MethodPost, path: "/v2/tournaments/rate_tour/results", key: "rate_key", expectedStatus: http.StatusTooManyRequests, expectedRetryAfter: "2"},
+		{name: "rate limit: read is not limited", method: http.MethodGet, path: "/balance?playerId=rate_player", key: "rate_key", expectedStatus: http.StatusOK},
+		{name: "rate limit: read again", method: http.MethodGet, path: "/balance?playerId=rate_player", key: "rate_key", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, rs.URL+tc.path, strings.NewReader(tc.body))
+			assert.Nil(t, err)
+			req.Header.Set(APIKeyHeader, tc.key)
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			assert.Equal(t, tc.expectedRetryAfter, res.Header.Get("Retry-After"))
+			if tc.expectedBody != "" {
+				assert.Equal(t, problemContentType, res.Header.Get("content-type"))
+				body, err := io.ReadAll(res.Body)
+				assert.Nil(t, err)
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestHandlers_LimitIP(t *testing.T) {
+	limiter := ratelimit.Limiter{Store: ratelimit.NewMemory(), Rules: map[string]ratelimit.Rule{ratelimit.ClassIP: {Rate: 0.5, Burst: 3}}}
+	rs := httptest.NewServer(NewRouter(Server{Controller: controller, Auth: keyStore{}, RateLimit: limiter}))
+	defer rs.Close()
+	// requests with invalid key are limited before they are authenticated
+	for i, expected := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		req, err := http.NewRequest(http.MethodGet, rs.URL+"/balance?playerId=ip_player", nil)
+		assert.Nil(t, err)
+		req.Header.Set(APIKeyHeader, "fake_key_"+fmt.Sprint(i))
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res.StatusCode, "request %v", i)
+		if expected == http.StatusTooManyRequests {
+			assert.Equal(t, "2", res.Header.Get("Retry-After"))
+		}
+	}
+
+	// requests from trusted proxy are limited by forwarded address
+	proxies, err := ratelimit.ParseProxies("127.0.0.1,::1")
+	assert.Nil(t, err)
+	limiter = ratelimit.Limiter{Store: ratelimit.NewMemory(), Rules: map[string]ratelimit.Rule{ratelimit.ClassIP: {Rate: 0.5, Burst: 1}}}
+	ps := httptest.NewServer(NewRouter(Server{Controller: controller, Auth: keyStore{}, RateLimit: limiter, TrustedProxies: proxies}))
+	defer ps.Close()
+	for i, tc := range []struct {
+		forwarded string
+		expected  int
+	}{
+		{forwarded: "198.51.100.1", expected: http.StatusUnauthorized},
+		{forwarded: "198.51.100.2", expected: http.StatusUnauthorized},
+		{forwarded: "198.51.100.3, 198.51.100.1", expected: http.StatusTooManyRequests},
+	} {
+		req, err := http.NewRequest(http.MethodGet, ps.URL+"/balance?playerId=ip_player", nil)
+		assert.Nil(t, err)
+		req.Header.Set("X-Forwarded-For", tc.forwarded)
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, res.StatusCode, "request %v", i)
+	}
+	// default rule does not limit ip class
+	limiter = ratelimit.Limiter{Store: ratelimit.NewMemory(), Rules: map[string]ratelimit.Rule{ratelimit.DefaultClass: {Rate: 0.5, Burst: 1}}}
+	ds := httptest.NewServer(NewRouter(Server{Controller: controller, Auth: keyStore{}, RateLimit: limiter}))
+	defer ds.Close()
+	for i := 0; i < 3; i++ {
+		res, err := http.Get(ds.URL + "/balance?playerId=ip_player")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "request %v", i)
+	}
+}
+
+func TestHandlers_Status(t *testing.T) {
+	tt := []struct {
+		code           errors.ErrCode
+		expectedStatus int
+	}{
+		{code: errors.JSONError, expectedStatus: http.StatusBadRequest},
+		{code: errors.NotNumberError, expectedStatus: http.StatusBadRequest},
+		{code: errors.NegativeDepositError, expectedStatus: http.StatusUnprocessableEntity},
+		{code: errors.InvalidSplitError, expectedStatus: http.StatusUnprocessableEntity},
+		{code: errors.InvalidWebhookError, expectedStatus: http.StatusUnprocessableEntity},
+		{code: errors.InvalidBatchError, expectedStatus: http.StatusUnprocessableEntity},
+		{code: errors.NotFoundError, expectedStatus: http.StatusNotFound},
+		{code: errors.DuplicatedIDError, expectedStatus: http.StatusConflict},
+		{code: errors.NoneParticipantsError, expectedStatus: http.StatusConflict},
+		{code: errors.SelfExcludedError, expectedStatus: http.StatusForbidden},
+		{code: errors.UnauthorizedError, expectedStatus: http.StatusUnauthorized},
+		{code: errors.ForbiddenError, expectedStatus: http.StatusForbidden},
+		{code: errors.RateLimitError, expectedStatus: http.StatusTooManyRequests},
+		{code: errors.DatabasePingError, expectedStatus: http.StatusServiceUnavailable},
+		{code: errors.UnexpectedError, expectedStatus: http.StatusInternalServerError},
+		{code: "UnknownError", expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tt {
+		t.Run(string(tc.code), func(t *testing.T) {
+			assert.Equal(t, tc.expectedStatus, Status(tc.code))
+		})
+	}
+}
+
+func TestHandlers_ProblemHandler(t *testing.T) {
+	problemTS := httptest.NewServer(NewRouter(Server{Controller: controller}))
+	defer problemTS.Close()
+	controller.On("Results", "problem_empty").Return(entity.Winners{}, errors.Error{Code: errors.NoneParticipantsError, Message: "results: none participants"})
+	controller.On("ListTournaments", entity.TourFilter{Sort: "problem"}, "").Return(entity.TourPage{}, errors.Error{Code: errors.InvalidFilterError, Message: "list: invalid sort"})
+	client := http.Client{}
+	tt := []struct {
+		name         string
+		url          string
+		expected     Problem
+		expectedType string
+	}{
+		{
+			name:         "v1: none participants",
+			url:          problemTS.URL + "/resultTournament?tournamentId=problem_empty",
+			expected:     Problem{Type: "about:blank", Title: "Conflict", Status: http.StatusConflict, Detail: "results: none participants", Code: errors.NoneParticipantsError, Message: "results: none participants"},
+			expectedType: problemContentType,
+		},
+		{
+			name: "v1: not number",
+			url:  problemTS.URL + "/fund?playerId=problem_player&points=problem",
+			expected: Problem{Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest, Detail: "cannot fund player, points is not number: problem",
+				Code: errors.NotNumberError, Message: "cannot fund player, points is not number: problem", Info: "strconv.Atoi: parsing \"problem\": invalid syntax"},
+			expectedType: problemContentType,
+		},
+		{
+			name:         "v2 in legacy mode: invalid filter",
+			url:          ts.URL + "/v2/tournaments?sort=problem",
+			expected:     Problem{Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest, Detail: "list: invalid sort", Code: errors.InvalidFilterError, Message: "list: invalid sort"},
+			expectedType: problemContentType,
+		},
+		{
+			name:         "v1 in legacy mode: none participants",
+			url:          ts.URL + "/resultTournament?tournamentId=problem_empty",
+			expected:     Problem{Status: http.StatusOK, Code: errors.NoneParticipantsError, Message: "results: none participants"},
+			expectedType: "application/json",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := client.Get(tc.url)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected.Status, res.StatusCode)
+			assert.Equal(t, tc.expectedType, res.Header.Get("content-type"))
+			var p Problem
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&p))
+			if tc.expectedType != problemContentType {
+				p.Status = res.StatusCode
+			}
+			assert.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestHandlers_OpenAPI(t *testing.T) {
+	oaTS := httptest.NewServer(NewRouter(Server{Controller: controller, Auth: keyStore{"oa_key": {ID: "oa_admin", Role: entity.RoleAdmin}}}))
+	defer oaTS.Close()
+	res, err := http.Get(oaTS.URL + "/openapi.json")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	doc, err := openapi3.NewLoader().LoadFromIoReader(res.Body)
+	assert.Nil(t, err)
+	assert.Nil(t, doc.Validate(context.Background()))
+
+	err = NewRouter(Server{Controller: controller}).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		assert.Nil(t, err)
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{http.MethodGet}
+		}
+		item := doc.Paths.Find(path)
+		if assert.NotNil(t, item, path) {
+			for _, m := range methods {
+				assert.NotNil(t, item.GetOperation(m), m+" "+path)
+			}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	closed := from.Add(time.Hour)
+	account := entity.Account{ID: "oa_player", Name: "Oa", Created: from, Metadata: map[string]string{"country": "DE"}, Status: entity.StatusActive}
+	hold := entity.Hold{ID: "oa_hold", PlayerID: "oa_player", Points: 100, Currency: entity.DefaultCurrency, Expires: from}
+	winners := entity.Winners{Winners: []entity.Winner{{ID: "oa_player", Points: 200, Prize: 100, Entry: 1}}}
+	tour := entity.Tournament{ID: "oa_tour", Deposit: 100, Currency: entity.DefaultCurrency, Participants: []string{"oa_player"}, Winner: winners.Winners[0],
+		MaxEntries: 1, Entries: []entity.Entry{{PlayerID: "oa_player", Number: 1}}, Created: from, Closed: &closed}
+	controller.On("Fund", "oa_new", "", 100, time.Duration(0)).Return(entity.Player{ID: "oa_new", Points: 100, Currency: entity.DefaultCurrency}, nil)
+	controller.On("Fund", "oa_player", "", 100, time.Duration(0)).Return(entity.Player{}, nil)
+	controller.On("Take", "oa_player", "", 100).Return(errors.Error{Code: errors.LimitExceededError, Message: "take: limit exceeded"})
+	controller.On("Balance", "oa_player", "").Return(entity.Balance{ID: "oa_player", Points: 200, Currency: entity.DefaultCurrency, Available: 100, Holds: []entity.Hold{hold},
+		Expiring: []entity.Lot{{ID: "1", PlayerID: "oa_player", Currency: entity.DefaultCurrency, Points: 200, Created: from, Expires: closed}}}, nil)
+	controller.On("Hold", "oa_hold", "oa_player", "", 100, defaultHoldTTL).Return(hold, nil)
+	controller.On("Capture", "oa_hold").Return(nil)
+	controller.On("Results", "oa_tour").Return(winners, nil)
+	controller.On("Results", "oa_empty").Return(entity.Winners{}, errors.Error{Code: errors.NoneParticipantsError, Message: "results: none participants"})
+	controller.On("AnnounceTournament", "oa_tour", "", 100, 1).Return(nil)
+	controller.On("JoinTournament", "oa_tour", "oa_fake").Return(errors.Error{Code: errors.NotFoundError, Message: "join: player not found", Info: "oa_fake"})
+	controller.On("CreateTeam", "oa_team", "oa_player", []string{"oa_player"}, []int(nil)).Return(entity.Team{ID: "oa_team", Captain: "oa_player",
+		Members: []entity.TeamMember{{PlayerID: "oa_player", Share: 100}}}, nil)
+	controller.On("Redeem", "OA", "oa_player").Return(entity.Redemption{Code: "OA", PlayerID: "oa_player", Use: 1, Points: 100, Currency: entity.DefaultCurrency, Redeemed: from}, nil)
+	controller.On("Limits", "oa_player").Return(entity.Limits{PlayerID: "oa_player", Daily: 100, Pending: &entity.PendingLimits{Daily: 200, Applies: from}}, nil)
+	controller.On("Register", account.ID, account.Name, account.Metadata).Return(account, nil)
+	controller.On("Account", account.ID).Return(account, nil)
+	controller.On("CloseAccount", "oa_closed", true).Return(entity.Closure{PlayerID: "oa_closed", Withdrawn: []string{"oa_tour"}}, nil)
+	controller.On("ListPlayers", entity.PlayerFilter{Status: entity.StatusSuspended}, "").Return(entity.PlayerPage{Players: []entity.Account{account}, Next: "next"}, nil)
+	controller.On("ListTournaments", entity.TourFilter{Status: "closed"}, "").Return(entity.TourPage{Tournaments: []entity.Tournament{tour}}, nil)
+	controller.On("Tournament", "oa_tour").Return(tour, nil)
+	controller.On("History", "oa_player").Return(entity.History{PlayerID: "oa_player", Tournaments: []entity.Participation{
+		{TournamentID: "oa_tour", PlayerID: "oa_player", Currency: entity.DefaultCurrency, Paid: 100, Placing: 1, Prize: 100, Joined: from}}}, nil)
+
+	router, err := gorillamux.NewRouter(doc)
+	assert.Nil(t, err)
+	tt := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "v1 fund: new player", method: http.MethodGet, path: "/fund?playerId=oa_new&points=100", expectedStatus: http.StatusCreated},
+		{name: "v1 fund: existing player", method: http.MethodGet, path: "/fund?playerId=oa_player&points=100", expectedStatus: http.StatusOK},
+		{name: "v1 take: limit exceeded", method: http.MethodGet, path: "/take?playerId=oa_player&points=100", expectedStatus: http.StatusForbidden},
+		{name: "v1 balance", method: http.MethodGet, path: "/balance?playerId=oa_player", expectedStatus: http.StatusOK},
+		{name: "v1 hold", method: http.MethodGet, path: "/hold?holdId=oa_hold&playerId=oa_player&points=100", expectedStatus: http.StatusCreated},
+		{name: "v1 results", method: http.MethodGet, path: "/resultTournament?tournamentId=oa_tour", expectedStatus: http.StatusOK},
+		{name: "v1 results: none participants", method: http.MethodGet, path: "/resultTournament?tournamentId=oa_empty", expectedStatus: http.StatusConflict},
+		{name: "v1 join: not found", method: http.MethodGet, path: "/joinTournament?tournamentId=oa_tour&playerId=oa_fake", expectedStatus: http.StatusNotFound},
+		{name: "v1 limits", method: http.MethodGet, path: "/limits?playerId=oa_player", expectedStatus: http.StatusOK},
+		{name: "v1 players", method: http.MethodGet, path: "/players?status=suspended", expectedStatus: http.StatusOK},
+		{name: "v1 tournament", method: http.MethodGet, path: "/tournaments/oa_tour", expectedStatus: http.StatusOK},
+		{name: "v2 register", method: http.MethodPost, path: "/v2/players", body: `{"id":"oa_player","name":"Oa","metadata":{"country":"DE"}}`, expectedStatus: http.StatusCreated},
+		{name: "v2 account", method: http.MethodGet, path: "/v2/players/oa_player", expectedStatus: http.StatusOK},
+		{name: "v2 close", method: http.MethodDelete, path: "/v2/players/oa_closed?withdraw=true", expectedStatus: http.StatusOK},
+		{name: "v2 fund: new player", method: http.MethodPost, path: "/v2/players/oa_new/fund", body: `{"points":100}`, expectedStatus: http.StatusCreated},
+		{name: "v2 fund: bad body", method: http.MethodPost, path: "/v2/players/oa_new/fund", body: `{"points":"100"}`, expectedStatus: http.StatusBadRequest},
+		{name: "v2 capture", method: http.MethodPost, path: "/v2/holds/oa_hold/capture", expectedStatus: http.StatusNoContent},
+		{name: "v2 redeem", method: http.MethodPost, path: "/v2/players/oa_player/redemptions", body: `{"code":"OA"}`, expectedStatus: http.StatusOK},
+		{name: "v2 history", method: http.MethodGet, path: "/v2/players/oa_player/tournaments", expectedStatus: http.StatusOK},
+		{name: "v2 announce", method: http.MethodPost, path: "/v2/tournaments", body: `{"id":"oa_tour","deposit":100}`, expectedStatus: http.StatusCreated},
+		{name: "v2 tournaments", method: http.MethodGet, path: "/v2/tournaments?status=closed", expectedStatus: http.StatusOK},
+		{name: "v2 results", method: http.MethodPost, path: "/v2/tournaments/oa_tour/results", expectedStatus: http.StatusOK},
+		{name: "v2 team", method: http.MethodPost, path: "/v2/teams", body: `{"id":"oa_team","captain":"oa_player","members":["oa_player"]}`, expectedStatus: http.StatusCreated},
+		{name: "openapi", method: http.MethodGet, path: "/openapi.json", expectedStatus: http.StatusOK},
+		{name: "v2 account: without api key", method: http.MethodGet, path: "/v2/players/oa_player", expectedStatus: http.StatusUnauthorized},
+	}
+	// every request is authenticated by api key, which middleware checks
+	options := &openapi3filter.Options{AuthenticationFunc: func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		if input.RequestValidationInput.Request.Header.Get(APIKeyHeader) == "" {
+			return errors.Error{Code: errors.UnauthorizedError}
+		}
+		return nil
+	}}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set("content-type", "application/json")
+			}
+			if tc.expectedStatus != http.StatusUnauthorized {
+				req.Header.Set(APIKeyHeader, "oa_key")
+			}
+			route, params, err := router.FindRoute(req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			input := &openapi3filter.RequestValidationInput{Request: req, PathParams: params, Route: route, Options: options}
+			if tc.expectedStatus != http.StatusBadRequest {
+				err = openapi3filter.ValidateRequest(context.Background(), input)
+				assert.Equal(t, tc.expectedStatus == http.StatusUnauthorized, err != nil)
+			}
+			real, err := http.NewRequest(tc.method, oaTS.URL+tc.path, strings.NewReader(tc.body))
+			assert.Nil(t, err)
+			real.Header = req.Header
+			res, err := http.DefaultClient.Do(real)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 res.StatusCode,
+				Header:                 res.Header,
+				Body:                   res.Body,
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			})
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestHandlers_EventsHandler(t *testing.T) {
+	bus := events.NewBus()
+	es := httptest.NewServer(NewRouter(Server{Controller: controller, Events: bus}))
+	defer es.Close()
+	joined := entity.Event{Type: entity.EventPlayerJoined, TournamentID: "events_tour", PlayerID: "events_1"}
+	other := entity.Event{Type: entity.EventPlayerJoined, TournamentID: "events_other", PlayerID: "events_2"}
+
+	t.Run("events: no bus", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/events")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		res.Body.Close()
+	})
+
+	t.Run("events: sse", func(t *testing.T) {
+		res, err := http.Get(es.URL + "/events?tournamentId=events_tour")
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer res.Body.Close()
+		assert.Equal(t, "text/event-stream", res.Header.Get("content-type"))
+		bus.Publish(other)
+		bus.Publish(joined)
+		reader := bufio.NewReader(res.Body)
+		line, err := reader.ReadString('\n')
+		assert.Nil(t, err)
+		assert.Equal(t, "event: playerJoined\n", line)
+		line, err = reader.ReadString('\n')
+		assert.Nil(t, err)
+		var e entity.Event
+		err = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e)
+		assert.Nil(t, err)
+		assert.Equal(t, joined, e)
+	})
+
+	t.Run("events: websocket", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(es.URL, "http")+"/events/ws", nil)
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer conn.Close()
+		err = conn.WriteJSON(wsMessage{Action: "subscribe", PlayerID: "events_1"})
+		assert.Nil(t, err)
+		// messages are handled in order, so subscription is changed, when error of next one is received
+		err = conn.WriteJSON(wsMessage{Action: "listen"})
+		assert.Nil(t, err)
+		var expErr errors.Error
+		err = conn.ReadJSON(&expErr)
+		assert.Nil(t, err)
+		assert.Equal(t, errors.Error{Code: errors.JSONError, Message: "events: unknown action: listen"}, expErr)
+		bus.Publish(other)
+		bus.Publish(joined)
+		var e entity.Event
+		err = conn.ReadJSON(&e)
+		assert.Nil(t, err)
+		assert.Equal(t, joined, e)
+	})
+
+	t.Run("events: websocket unsubscribe last id", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(es.URL, "http")+"/events/ws?playerId=events_1", nil)
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer conn.Close()
+		err = conn.WriteJSON(wsMessage{Action: "unsubscribe", PlayerID: "events_1"})
+		assert.Nil(t, err)
+		err = conn.WriteJSON(wsMessage{Action: "listen"})
+		assert.Nil(t, err)
+		var expErr errors.Error
+		err = conn.ReadJSON(&expErr)
+		assert.Nil(t, err)
+		bus.Publish(other)
+		bus.Publish(joined)
+		// subscription without ids gets no events
+		err = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		assert.Nil(t, err)
+		var e entity.Event
+		err = conn.ReadJSON(&e)
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.Event{}, e)
+	})
+}
@@ -2,6 +2,7 @@
 package handlers
 
 import entity "github.com/dmitriyomelyusik/Tournament/entity"
+import time "time"
 import mock "github.com/stretchr/testify/mock"
 
 // mockCtlr is an autogenerated mock type for the ctlr type
@@ -9,13 +10,34 @@ type mockCtlr struct {
 	mock.Mock
 }
 
-// AnnounceTournament provides a mock function with given fields: id, deposit
-func (_m *mockCtlr) AnnounceTournament(id string, deposit int) error {
-	ret := _m.Called(id, deposit)
+// Account provides a mock function with given fields: id
+func (_m *mockCtlr) Account(id string) (entity.Account, error) {
+	ret := _m.Called(id)
+
+	var r0 entity.Account
+	if rf, ok := ret.Get(0).(func(string) entity.Account); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnnounceSatellite provides a mock function with given fields: id, deposit, targetID, seats
+func (_m *mockCtlr) AnnounceSatellite(id string, deposit int, targetID string, seats int) error {
+	ret := _m.Called(id, deposit, targetID, seats)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(id, deposit)
+	if rf, ok := ret.Get(0).(func(string, int, string, int) error); ok {
+		r0 = rf(id, deposit, targetID, seats)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -23,20 +45,167 @@ func (_m *mockCtlr) AnnounceTournament(id string, deposit int) error {
 	return r0
 }
 
-// Balance provides a mock function with given fields: id
-func (_m *mockCtlr) Balance(id string) (entity.Player, error) {
+// AnnounceTeamTournament provides a mock function with given fields: id, currency, deposit
+func (_m *mockCtlr) AnnounceTeamTournament(id string, currency string, deposit int) error {
+	ret := _m.Called(id, currency, deposit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(id, currency, deposit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnnounceTournament provides a mock function with given fields: id, currency, deposit, maxEntries
+func (_m *mockCtlr) AnnounceTournament(id string, currency string, deposit int, maxEntries int) error {
+	ret := _m.Called(id, currency, deposit, maxEntries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int, int) error); ok {
+		r0 = rf(id, currency, deposit, maxEntries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Balance provides a mock function with given fields: id, currency
+func (_m *mockCtlr) Balance(id string, currency string) (entity.Balance, error) {
+	ret := _m.Called(id, currency)
+
+	var r0 entity.Balance
+	if rf, ok := ret.Get(0).(func(string, string) entity.Balance); ok {
+		r0 = rf(id, currency)
+	} else {
+		r0 = ret.Get(0).(entity.Balance)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(id, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Batch provides a mock function with given fields: ops, atomic
+func (_m *mockCtlr) Batch(ops []entity.Operation, atomic bool) (entity.BatchResult, error) {
+	ret := _m.Called(ops, atomic)
+
+	var r0 entity.BatchResult
+	if rf, ok := ret.Get(0).(func([]entity.Operation, bool) entity.BatchResult); ok {
+		r0 = rf(ops, atomic)
+	} else {
+		r0 = ret.Get(0).(entity.BatchResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]entity.Operation, bool) error); ok {
+		r1 = rf(ops, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Capture provides a mock function with given fields: id
+func (_m *mockCtlr) Capture(id string) error {
 	ret := _m.Called(id)
 
-	var r0 entity.Player
-	if rf, ok := ret.Get(0).(func(string) entity.Player); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
 		r0 = rf(id)
 	} else {
-		r0 = ret.Get(0).(entity.Player)
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloseAccount provides a mock function with given fields: id, withdraw
+func (_m *mockCtlr) CloseAccount(id string, withdraw bool) (entity.Closure, error) {
+	ret := _m.Called(id, withdraw)
+
+	var r0 entity.Closure
+	if rf, ok := ret.Get(0).(func(string, bool) entity.Closure); ok {
+		r0 = rf(id, withdraw)
+	} else {
+		r0 = ret.Get(0).(entity.Closure)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(id, withdraw)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePromo provides a mock function with given fields: promo
+func (_m *mockCtlr) CreatePromo(promo entity.Promo) (entity.Promo, error) {
+	ret := _m.Called(promo)
+
+	var r0 entity.Promo
+	if rf, ok := ret.Get(0).(func(entity.Promo) entity.Promo); ok {
+		r0 = rf(promo)
+	} else {
+		r0 = ret.Get(0).(entity.Promo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entity.Promo) error); ok {
+		r1 = rf(promo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTeam provides a mock function with given fields: id, captain, members, shares
+func (_m *mockCtlr) CreateTeam(id string, captain string, members []string, shares []int) (entity.Team, error) {
+	ret := _m.Called(id, captain, members, shares)
+
+	var r0 entity.Team
+	if rf, ok := ret.Get(0).(func(string, string, []string, []int) entity.Team); ok {
+		r0 = rf(id, captain, members, shares)
+	} else {
+		r0 = ret.Get(0).(entity.Team)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, []string, []int) error); ok {
+		r1 = rf(id, captain, members, shares)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhook provides a mock function with given fields: webhook
+func (_m *mockCtlr) CreateWebhook(webhook entity.Webhook) (entity.Webhook, error) {
+	ret := _m.Called(webhook)
+
+	var r0 entity.Webhook
+	if rf, ok := ret.Get(0).(func(entity.Webhook) entity.Webhook); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Get(0).(entity.Webhook)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entity.Webhook) error); ok {
+		r1 = rf(webhook)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -44,20 +213,99 @@ func (_m *mockCtlr) Balance(id string) (entity.Player, error) {
 	return r0, r1
 }
 
-// Fund provides a mock function with given fields: id, points
-func (_m *mockCtlr) Fund(id string, points int) (entity.Player, error) {
-	ret := _m.Called(id, points)
+// DeadDeliveries provides a mock function with given fields:
+func (_m *mockCtlr) DeadDeliveries() ([]entity.Delivery, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Delivery
+	if rf, ok := ret.Get(0).(func() []entity.Delivery); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: id
+func (_m *mockCtlr) DeleteWebhook(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fund provides a mock function with given fields: id, currency, points, expiresIn
+func (_m *mockCtlr) Fund(id string, currency string, points int, expiresIn time.Duration) (entity.Player, error) {
+	ret := _m.Called(id, currency, points, expiresIn)
 
 	var r0 entity.Player
-	if rf, ok := ret.Get(0).(func(string, int) entity.Player); ok {
-		r0 = rf(id, points)
+	if rf, ok := ret.Get(0).(func(string, string, int, time.Duration) entity.Player); ok {
+		r0 = rf(id, currency, points, expiresIn)
 	} else {
 		r0 = ret.Get(0).(entity.Player)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(id, points)
+	if rf, ok := ret.Get(1).(func(string, string, int, time.Duration) error); ok {
+		r1 = rf(id, currency, points, expiresIn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// History provides a mock function with given fields: playerID
+func (_m *mockCtlr) History(playerID string) (entity.History, error) {
+	ret := _m.Called(playerID)
+
+	var r0 entity.History
+	if rf, ok := ret.Get(0).(func(string) entity.History); ok {
+		r0 = rf(playerID)
+	} else {
+		r0 = ret.Get(0).(entity.History)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Hold provides a mock function with given fields: id, playerID, currency, points, ttl
+func (_m *mockCtlr) Hold(id string, playerID string, currency string, points int, ttl time.Duration) (entity.Hold, error) {
+	ret := _m.Called(id, playerID, currency, points, ttl)
+
+	var r0 entity.Hold
+	if rf, ok := ret.Get(0).(func(string, string, string, int, time.Duration) entity.Hold); ok {
+		r0 = rf(id, playerID, currency, points, ttl)
+	} else {
+		r0 = ret.Get(0).(entity.Hold)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, int, time.Duration) error); ok {
+		r1 = rf(id, playerID, currency, points, ttl)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -65,6 +313,20 @@ func (_m *mockCtlr) Fund(id string, points int) (entity.Player, error) {
 	return r0, r1
 }
 
+// JoinTeam provides a mock function with given fields: tourID, teamID, captainPays
+func (_m *mockCtlr) JoinTeam(tourID string, teamID string, captainPays bool) error {
+	ret := _m.Called(tourID, teamID, captainPays)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool) error); ok {
+		r0 = rf(tourID, teamID, captainPays)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // JoinTournament provides a mock function with given fields: tourID, playerID
 func (_m *mockCtlr) JoinTournament(tourID string, playerID string) error {
 	ret := _m.Called(tourID, playerID)
@@ -79,6 +341,146 @@ func (_m *mockCtlr) JoinTournament(tourID string, playerID string) error {
 	return r0
 }
 
+// Limits provides a mock function with given fields: playerID
+func (_m *mockCtlr) Limits(playerID string) (entity.Limits, error) {
+	ret := _m.Called(playerID)
+
+	var r0 entity.Limits
+	if rf, ok := ret.Get(0).(func(string) entity.Limits); ok {
+		r0 = rf(playerID)
+	} else {
+		r0 = ret.Get(0).(entity.Limits)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPlayers provides a mock function with given fields: filter, cursor
+func (_m *mockCtlr) ListPlayers(filter entity.PlayerFilter, cursor string) (entity.PlayerPage, error) {
+	ret := _m.Called(filter, cursor)
+
+	var r0 entity.PlayerPage
+	if rf, ok := ret.Get(0).(func(entity.PlayerFilter, string) entity.PlayerPage); ok {
+		r0 = rf(filter, cursor)
+	} else {
+		r0 = ret.Get(0).(entity.PlayerPage)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entity.PlayerFilter, string) error); ok {
+		r1 = rf(filter, cursor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTournaments provides a mock function with given fields: filter, cursor
+func (_m *mockCtlr) ListTournaments(filter entity.TourFilter, cursor string) (entity.TourPage, error) {
+	ret := _m.Called(filter, cursor)
+
+	var r0 entity.TourPage
+	if rf, ok := ret.Get(0).(func(entity.TourFilter, string) entity.TourPage); ok {
+		r0 = rf(filter, cursor)
+	} else {
+		r0 = ret.Get(0).(entity.TourPage)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entity.TourFilter, string) error); ok {
+		r1 = rf(filter, cursor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Redeem provides a mock function with given fields: code, playerID
+func (_m *mockCtlr) Redeem(code string, playerID string) (entity.Redemption, error) {
+	ret := _m.Called(code, playerID)
+
+	var r0 entity.Redemption
+	if rf, ok := ret.Get(0).(func(string, string) entity.Redemption); ok {
+		r0 = rf(code, playerID)
+	} else {
+		r0 = ret.Get(0).(entity.Redemption)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(code, playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: id, name, metadata
+func (_m *mockCtlr) Register(id string, name string, metadata map[string]string) (entity.Account, error) {
+	ret := _m.Called(id, name, metadata)
+
+	var r0 entity.Account
+	if rf, ok := ret.Get(0).(func(string, string, map[string]string) entity.Account); ok {
+		r0 = rf(id, name, metadata)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, map[string]string) error); ok {
+		r1 = rf(id, name, metadata)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reinstate provides a mock function with given fields: id
+func (_m *mockCtlr) Reinstate(id string) (entity.Account, error) {
+	ret := _m.Called(id)
+
+	var r0 entity.Account
+	if rf, ok := ret.Get(0).(func(string) entity.Account); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Release provides a mock function with given fields: id
+func (_m *mockCtlr) Release(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Results provides a mock function with given fields: tourID
 func (_m *mockCtlr) Results(tourID string) (entity.Winners, error) {
 	ret := _m.Called(tourID)
@@ -100,16 +502,151 @@ func (_m *mockCtlr) Results(tourID string) (entity.Winners, error) {
 	return r0, r1
 }
 
-// Take provides a mock function with given fields: id, points
-func (_m *mockCtlr) Take(id string, points int) error {
-	ret := _m.Called(id, points)
+// RetryDelivery provides a mock function with given fields: id
+func (_m *mockCtlr) RetryDelivery(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SelfExclude provides a mock function with given fields: playerID, period
+func (_m *mockCtlr) SelfExclude(playerID string, period time.Duration) (entity.Limits, error) {
+	ret := _m.Called(playerID, period)
+
+	var r0 entity.Limits
+	if rf, ok := ret.Get(0).(func(string, time.Duration) entity.Limits); ok {
+		r0 = rf(playerID, period)
+	} else {
+		r0 = ret.Get(0).(entity.Limits)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(playerID, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLimits provides a mock function with given fields: playerID, daily, weekly
+func (_m *mockCtlr) SetLimits(playerID string, daily int, weekly int) (entity.Limits, error) {
+	ret := _m.Called(playerID, daily, weekly)
+
+	var r0 entity.Limits
+	if rf, ok := ret.Get(0).(func(string, int, int) entity.Limits); ok {
+		r0 = rf(playerID, daily, weekly)
+	} else {
+		r0 = ret.Get(0).(entity.Limits)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = rf(playerID, daily, weekly)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Suspend provides a mock function with given fields: id
+func (_m *mockCtlr) Suspend(id string) (entity.Account, error) {
+	ret := _m.Called(id)
+
+	var r0 entity.Account
+	if rf, ok := ret.Get(0).(func(string) entity.Account); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Take provides a mock function with given fields: id, currency, points
+func (_m *mockCtlr) Take(id string, currency string, points int) error {
+	ret := _m.Called(id, currency, points)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(id, currency, points)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Tournament provides a mock function with given fields: id
+func (_m *mockCtlr) Tournament(id string) (entity.Tournament, error) {
+	ret := _m.Called(id)
+
+	var r0 entity.Tournament
+	if rf, ok := ret.Get(0).(func(string) entity.Tournament); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Tournament)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transfer provides a mock function with given fields: from, to, currency, points
+func (_m *mockCtlr) Transfer(from string, to string, currency string, points int) error {
+	ret := _m.Called(from, to, currency, points)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(id, points)
+	if rf, ok := ret.Get(0).(func(string, string, string, int) error); ok {
+		r0 = rf(from, to, currency, points)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+
+// Webhooks provides a mock function with given fields:
+func (_m *mockCtlr) Webhooks() ([]entity.Webhook, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Webhook
+	if rf, ok := ret.Get(0).(func() []entity.Webhook); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
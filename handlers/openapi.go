@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// operation describes route for OpenAPI document. Query parameters are strings, body and responses are values of
+// types, which are sent, nil response means response without body. Stream response is sequence of server-sent events.
+// Public operation is used without API key, player routes can be used with bearer token of player too.
+type operation struct {
+	method    string
+	path      string
+	summary   string
+	query     []string
+	body      interface{}
+	responses map[int]interface{}
+	v1        bool
+	stream    bool
+	public    bool
+}
+
+// operations returns every route of NewRouter, v1 routes accept any method, they are described with GET
+func operations() []operation {
+	return []operation{
+		{method: http.MethodGet, path: "/fund", summary: "Fund player, new player is returned", query: []string{"playerId", "points", "currency", "expireDays"},
+			responses: map[int]interface{}{http.StatusOK: nil, http.StatusCreated: entity.Player{}}, v1: true},
+		{method: http.MethodGet, path: "/take", summary: "Take points from player", query: []string{"playerId", "points", "currency"},
+			responses: map[int]interface{}{http.StatusOK: nil}, v1: true},
+		{method: http.MethodGet, path: "/transfer", summary: "Transfer points between players", query: []string{"from", "to", "points", "currency"},
+			responses: map[int]interface{}{http.StatusOK: nil}, v1: true},
+		{method: http.MethodGet, path: "/balance", summary: "Player balance", query: []string{"playerId", "currency"},
+			responses: map[int]interface{}{http.StatusOK: entity.Balance{}}, v1: true},
+		{method: http.MethodGet, path: "/hold", summary: "Hold player points", query: []string{"holdId", "playerId", "points", "currency", "ttl"},
+			responses: map[int]interface{}{http.StatusCreated: entity.Hold{}}, v1: true},
+		{method: http.MethodGet, path: "/capture", summary: "Capture held points", query: []string{"holdId"},
+			responses: map[int]interface{}{http.StatusOK: nil}, v1: true},
+		{method: http.MethodGet, path: "/release", summary: "Release held points", query: []string{"holdId"},
+			responses: map[int]interface{}{http.StatusOK: nil}, v1: true},
+		{method: http.MethodGet, path: "/announceTournament", summary: "Announce tournament, team or satellite tournament",
+			query:     []string{"tournamentId", "deposit", "currency", "maxEntries", "teams", "targetId", "seats"},
+			responses: map[int]interface{}{http.StatusOK: nil}, v1: true},
+		{method: http.MethodGet, path: "/joinTournament", summary: "Join player or team into tournament", query: []string{"tournamentId", "playerId", "teamId", "payer"},
+			responses: map[int]interface{}{http.StatusOK: nil}, v1: true},
+		{method: http.MethodGet, path: "/resultTournament", summary: "Close tournament and choose winners", query: []string{"tournamentId"},
+			responses: map[int]interface{}{http.StatusOK: entity.Winners{}}, v1: true},
+		{method: http.MethodGet, path: "/createTeam", summary: "Create team", query: []string{"teamId", "captain", "members", "shares"},
+			responses: map[int]interface{}{http.StatusCreated: entity.Team{}}, v1: true},
+		{method: http.MethodGet, path: "/createPromo", summary: "Create promo code",
+			query:     []string{"code", "points", "currency", "tournamentId", "maxUses", "maxPerPlayer", "validFrom", "validTo", "newPlayersOnly"},
+			responses: map[int]interface{}{http.StatusCreated: entity.Promo{}}, v1: true},
+		{method: http.MethodGet, path: "/redeem", summary: "Redeem promo code", query: []string{"code", "playerId"},
+			responses: map[int]interface{}{http.StatusOK: entity.Redemption{}}, v1: true},
+		{method: http.MethodGet, path: "/limits", summary: "Player limits in force", query: []string{"playerId"},
+			responses: map[int]interface{}{http.StatusOK: entity.Limits{}}, v1: true},
+		{method: http.MethodGet, path: "/setLimits", summary: "Set player limits", query: []string{"playerId", "daily", "weekly"},
+			responses: map[int]interface{}{http.StatusOK: entity.Limits{}}, v1: true},
+		{method: http.MethodGet, path: "/selfExclude", summary: "Exclude player from spending", query: []string{"playerId", "days"},
+			responses: map[int]interface{}{http.StatusOK: entity.Limits{}}, v1: true},
+		{method: http.MethodGet, path: "/registerPlayer", summary: "Register player, meta. prefixed parameters are metadata", query: []string{"playerId", "name"},
+			responses: map[int]interface{}{http.StatusCreated: entity.Account{}}, v1: true},
+		{method: http.MethodGet, path: "/player", summary: "Player account", query: []string{"playerId"},
+			responses: map[int]interface{}{http.StatusOK: entity.Account{}}, v1: true},
+		{method: http.MethodGet, path: "/suspendPlayer", summary: "Suspend player account", query: []string{"playerId"},
+			responses: map[int]interface{}{http.StatusOK: entity.Account{}}, v1: true},
+		{method: http.MethodGet, path: "/reinstatePlayer", summary: "Reinstate player account", query: []string{"playerId"},
+			responses: map[int]interface{}{http.StatusOK: entity.Account{}}, v1: true},
+		{method: http.MethodGet, path: "/closePlayer", summary: "Close player account", query: []string{"playerId", "withdraw"},
+			responses: map[int]interface{}{http.StatusOK: entity.Closure{}}, v1: true},
+		{method: http.MethodGet, path: "/players", summary: "List players",
+			query:     []string{"status", "createdFrom", "createdTo", "sort", "order", "limit", "cursor"},
+			responses: map[int]interface{}{http.StatusOK: entity.PlayerPage{}}, v1: true},
+		{method: http.MethodGet, path: "/tournaments", summary: "List tournaments",
+			query:     []string{"status", "minDeposit", "maxDeposit", "createdFrom", "createdTo", "participantId", "sort", "order", "limit", "cursor"},
+			responses: map[int]interface{}{http.StatusOK: entity.TourPage{}}, v1: true},
+		{method: http.MethodGet, path: "/tournaments/{id}", summary: "Tournament details",
+			responses: map[int]interface{}{http.StatusOK: entity.Tournament{}}, v1: true},
+		{method: http.MethodGet, path: "/players/{id}/tournaments", summary: "Player tournament history",
+			responses: map[int]interface{}{http.StatusOK: entity.History{}}, v1: true},
+
+		{method: http.MethodPost, path: "/v2/players", summary: "Register player", body: registerRequest{},
+			responses: map[int]interface{}{http.StatusCreated: entity.Account{}}},
+		{method: http.MethodGet, path: "/v2/players", summary: "List players",
+			query:     []string{"status", "createdFrom", "createdTo", "sort", "order", "limit", "cursor"},
+			responses: map[int]interface{}{http.StatusOK: entity.PlayerPage{}}},
+		{method: http.MethodGet, path: "/v2/players/{id}", summary: "Player account",
+			responses: map[int]interface{}{http.StatusOK: entity.Account{}}},
+		{method: http.MethodDelete, path: "/v2/players/{id}", summary: "Close player account", query: []string{"withdraw"},
+			responses: map[int]interface{}{http.StatusOK: entity.Closure{}}},
+		{method: http.MethodPost, path: "/v2/players/{id}/suspend", summary: "Suspend player account",
+			responses: map[int]interface{}{http.StatusOK: entity.Account{}}},
+		{method: http.MethodPost, path: "/v2/players/{id}/reinstate", summary: "Reinstate player account",
+			responses: map[int]interface{}{http.StatusOK: entity.Account{}}},
+		{method: http.MethodPost, path: "/v2/players/{id}/fund", summary: "Fund player, new player is returned", body: fundRequest{},
+			responses: map[int]interface{}{http.StatusCreated: entity.Player{}, http.StatusNoContent: nil}},
+		{method: http.MethodPost, path: "/v2/players/{id}/take", summary: "Take points from player", body: pointsRequest{},
+			responses: map[int]interface{}{http.StatusNoContent: nil}},
+		{method: http.MethodPost, path: "/v2/players/{id}/transfers", summary: "Transfer points to other player", body: transferRequest{},
+			responses: map[int]interface{}{http.StatusNoContent: nil}},
+		{method: http.MethodGet, path: "/v2/players/{id}/balance", summary: "Player balance", query: []string{"currency"},
+			responses: map[int]interface{}{http.StatusOK: entity.Balance{}}},
+		{method: http.MethodPost, path: "/v2/players/{id}/holds", summary: "Hold player points", body: holdRequest{},
+			responses: map[int]interface{}{http.StatusCreated: entity.Hold{}}},
+		{method: http.MethodGet, path: "/v2/players/{id}/limits", summary: "Player limits in force",
+			responses: map[int]interface{}{http.StatusOK: entity.Limits{}}},
+		{method: http.MethodPut, path: "/v2/players/{id}/limits", summary: "Set player limits", body: limitsRequest{},
+			responses: map[int]interface{}{http.StatusOK: entity.Limits{}}},
+		{method: http.MethodPost, path: "/v2/players/{id}/exclusion", summary: "Exclude player from spending", body: excludeRequest{},
+			responses: map[int]interface{}{http.StatusOK: entity.Limits{}}},
+		{method: http.MethodPost, path: "/v2/players/{id}/redemptions", summary: "Redeem promo code", body: redeemRequest{},
+			responses: map[int]interface{}{http.StatusOK: entity.Redemption{}}},
+		{method: http.MethodGet, path: "/v2/players/{id}/tournaments", summary: "Player tournament history",
+			responses: map[int]interface{}{http.StatusOK: entity.History{}}},
+		{method: http.MethodPost, path: "/v2/holds/{id}/capture", summary: "Capture held points",
+			responses: map[int]interface{}{http.StatusNoContent: nil}},
+		{method: http.MethodDelete, path: "/v2/holds/{id}", summary: "Release held points",
+			responses: map[int]interface{}{http.StatusNoContent: nil}},
+		{method: http.MethodPost, path: "/v2/tournaments", summary: "Announce tournament, team or satellite tournament", body: tournamentRequest{},
+			responses: map[int]interface{}{http.StatusCreated: nil}},
+		{method: http.MethodGet, path: "/v2/tournaments", summary: "List tournaments",
+			query:     []string{"status", "minDeposit", "maxDeposit", "createdFrom", "createdTo", "participantId", "sort", "order", "limit", "cursor"},
+			responses: map[int]interface{}{http.StatusOK: entity.TourPage{}}},
+		{method: http.MethodGet, path: "/v2/tournaments/{id}", summary: "Tournament details",
+			responses: map[int]interface{}{http.StatusOK: entity.Tournament{}}},
+		{method: http.MethodPost, path: "/v2/tournaments/{id}/entries", summary: "Join player or team into tournament", body: entryRequest{},
+			responses: map[int]interface{}{http.StatusCreated: nil}},
+		{method: http.MethodPost, path: "/v2/tournaments/{id}/results", summary: "Close tournament and choose winners",
+			responses: map[int]interface{}{http.StatusOK: entity.Winners{}}},
+		{method: http.MethodPost, path: "/v2/teams", summary: "Create team", body: teamRequest{},
+			responses: map[int]interface{}{http.StatusCreated: entity.Team{}}},
+		{method: http.MethodPost, path: "/v2/promos", summary: "Create promo code", body: entity.Promo{},
+			responses: map[int]interface{}{http.StatusCreated: entity.Promo{}}},
+		{method: http.MethodPost, path: "/v2/batch", summary: "Apply batch of fund, take and join operations atomically or independently", body: batchRequest{},
+			responses: map[int]interface{}{http.StatusOK: entity.BatchResult{}}},
+
+		{method: http.MethodPost, path: "/admin/webhooks", summary: "Create webhook, it is returned with its secret", body: webhookRequest{},
+			responses: map[int]interface{}{http.StatusCreated: entity.Webhook{}}},
+		{method: http.MethodGet, path: "/admin/webhooks", summary: "List webhooks without secrets",
+			responses: map[int]interface{}{http.StatusOK: []entity.Webhook{}}},
+		{method: http.MethodDelete, path: "/admin/webhooks/{id}", summary: "Delete webhook with its deliveries",
+			responses: map[int]interface{}{http.StatusNoContent: nil}},
+		{method: http.MethodGet, path: "/admin/deliveries/dead", summary: "Dead-letter list of deliveries, which have failed every attempt",
+			responses: map[int]interface{}{http.StatusOK: []entity.Delivery{}}},
+		{method: http.MethodPost, path: "/admin/deliveries/{id}/retry", summary: "Return dead delivery into outbox",
+			responses: map[int]interface{}{http.StatusNoContent: nil}},
+
+		{method: http.MethodGet, path: "/events", summary: "Server-sent events of tournaments and players, every event without them",
+			query: []string{"tournamentId", "playerId"}, responses: map[int]interface{}{http.StatusOK: entity.Event{}}, stream: true},
+		{method: http.MethodGet, path: "/events/ws", summary: "WebSocket of events, subscription is changed by subscribe and unsubscribe messages",
+			query: []string{"tournamentId", "playerId"}, responses: map[int]interface{}{http.StatusSwitchingProtocols: nil}},
+
+		{method: http.MethodGet, path: "/openapi.json", summary: "This document",
+			responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}}, public: true},
+	}
+}
+
+// HandleOpenAPI handles GET /openapi.json, document is built once
+func (s Server) HandleOpenAPI() http.HandlerFunc {
+	doc := openAPI()
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResponse(w, doc, http.StatusOK)
+	}
+}
+
+// openAPI builds OpenAPI 3 document of operations, schemas are generated from json tags of sent types
+func openAPI() map[string]interface{} {
+	c := components{}
+	paths := map[string]map[string]interface{}{}
+	for _, op := range operations() {
+		if paths[op.path] == nil {
+			paths[op.path] = map[string]interface{}{}
+		}
+		paths[op.path][strings.ToLower(op.method)] = c.operation(op)
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Tournament",
+			"version": "2.0.0",
+			"description": "Tournament service. V1 routes accept query parameters and any method, v2 routes accept JSON bodies. " +
+				"Errors are problem json, v1 routes answer errors.Error json with v1 statuses in legacy mode. " +
+				"Requests are authenticated by API key, its errors are errors.Error json. " +
+				"Players can view own balance and history and join tournaments as themselves with signed JWT, which subject is player id.",
+		},
+		"paths":    paths,
+		"security": []interface{}{map[string]interface{}{"apiKey": []string{}}},
+		"components": map[string]interface{}{
+			"schemas": c,
+			"securitySchemes": map[string]interface{}{
+				"apiKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": APIKeyHeader},
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+// components contains schemas of named types, which are referenced by operations
+type components map[string]interface{}
+
+func (c components) operation(op operation) map[string]interface{} {
+	var params []interface{}
+	for _, name := range pathParams(op.path) {
+		params = append(params, map[string]interface{}{"name": name, "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"}})
+	}
+	for _, name := range op.query {
+		params = append(params, map[string]interface{}{"name": name, "in": "query", "schema": map[string]interface{}{"type": "string"}})
+	}
+	errorContent := map[string]interface{}{problemContentType: map[string]interface{}{"schema": c.schema(reflect.TypeOf(Problem{}))}}
+	if op.v1 || !op.public {
+		errorContent["application/json"] = map[string]interface{}{"schema": c.schema(reflect.TypeOf(errors.Error{}))}
+	}
+	responses := map[string]interface{}{
+		"default": map[string]interface{}{"description": "Error", "content": errorContent},
+	}
+	if !op.public {
+		responses[strconv.Itoa(http.StatusUnauthorized)] = map[string]interface{}{"description": "API key or token is not set or is not valid", "content": errorContent}
+	}
+	responses[strconv.Itoa(http.StatusTooManyRequests)] = map[string]interface{}{
+		"description": "Rate limit of route class is exceeded",
+		"headers":     map[string]interface{}{"Retry-After": map[string]interface{}{"description": "Seconds until request can be sent again", "schema": map[string]interface{}{"type": "integer"}}},
+		"content":     map[string]interface{}{problemContentType: map[string]interface{}{"schema": c.schema(reflect.TypeOf(Problem{}))}},
+	}
+	for status, body := range op.responses {
+		res := map[string]interface{}{"description": http.StatusText(status)}
+		if body != nil {
+			contentType := "application/json"
+			if op.stream {
+				contentType = "text/event-stream"
+			}
+			res["content"] = map[string]interface{}{contentType: map[string]interface{}{"schema": c.schema(reflect.TypeOf(body))}}
+		}
+		responses[strconv.Itoa(status)] = res
+	}
+	result := map[string]interface{}{"summary": op.summary, "responses": responses}
+	switch {
+	case op.public:
+		result["security"] = []interface{}{}
+	case playerRoutes[op.path] || (!op.v1 && playerRoutes[op.method+" "+op.path]):
+		result["security"] = []interface{}{map[string]interface{}{"apiKey": []string{}}, map[string]interface{}{"bearer": []string{}}}
+	}
+	if params != nil {
+		result["parameters"] = params
+	}
+	if op.body != nil {
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": c.object(reflect.TypeOf(op.body), false)}},
+		}
+	}
+	return result
+}
+
+// schema returns schema of type, structs are added to components and referenced.
+// Nil slices, maps and interfaces are encoded as null, so they are nullable.
+func (c components) schema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return c.schema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": c.schema(t.Elem()), "nullable": true}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": c.schema(t.Elem()), "nullable": true}
+	case reflect.Struct:
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := c[name]; !ok {
+			c[name] = nil
+			c[name] = c.object(t, true)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]interface{}{"nullable": true}
+	}
+}
+
+// object returns schema of struct. Sent fields without omitempty are always present, so they are required,
+// fields of request body are optional.
+func (c components) object(t reflect.Type, sent bool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		if tag[0] == "-" || f.PkgPath != "" {
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = c.schema(f.Type)
+		if sent && (len(tag) == 1 || tag[1] != "omitempty") {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	schema := map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": !sent}
+	if required != nil {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// pathParams returns names of path parameters in order, they appear in path
+func pathParams(path string) []string {
+	var names []string
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			names = append(names, part[1:len(part)-1])
+		}
+	}
+	return names
+}
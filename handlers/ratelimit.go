@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/dmitriyomelyusik/Tournament/auth"
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
+)
+
+// rateLimiter takes token of client key from bucket of route class, wait is zero, if request is let in,
+// otherwise it is time until client can send request of class again
+type rateLimiter interface {
+	Take(key, class string) (time.Duration, error)
+}
+
+// classRoutes are routes of classes, which are limited separately from other writes
+var classRoutes = map[string]string{
+	"/joinTournament": ratelimit.ClassJoin, "POST /v2/tournaments/{id}/entries": ratelimit.ClassJoin,
+	"/resultTournament": ratelimit.ClassResults, "POST /v2/tournaments/{id}/results": ratelimit.ClassResults,
+}
+
+// routeClass returns class of route of request, routes, which only read, are read class, other routes are write class
+func routeClass(r *http.Request) string {
+	path, _ := mux.CurrentRoute(r).GetPathTemplate()
+	if class, ok := classRoutes[path]; ok {
+		return class
+	}
+	if class, ok := classRoutes[r.Method+" "+path]; ok {
+		return class
+	}
+	switch routeRole(r) {
+	case "", entity.RoleReadOnly:
+		return ratelimit.ClassRead
+	}
+	return ratelimit.ClassWrite
+}
+
+// clientKey returns key of client, who has made request: id of API key, id of player with token or IP address
+func (s Server) clientKey(r *http.Request) string {
+	p, _ := auth.FromContext(r.Context())
+	if key := p.Key(); key != "" {
+		return key
+	}
+	return s.ipKey(r)
+}
+
+// ipKey returns key of IP address, which request is made from, address of request from trusted proxy is forwarded one
+func (s Server) ipKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + s.TrustedProxies.ClientIP(host, r.Header.Values("X-Forwarded-For"))
+}
+
+// LimitRate is middleware, which lets request in, if its client has not exceeded rate limit of route class. Request over
+// limit gets 429 status with Retry-After header in seconds and problem json. Request is let in, if limiter fails.
+func (s Server) LimitRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.limit(w, s.clientKey(r), routeClass(r)) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// LimitIP is middleware, which limits rate of every request of IP address before it is authenticated,
+// so requests with invalid API keys or tokens are limited too. Requests are limited only if ip class has own rule.
+func (s Server) LimitIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.limit(w, s.ipKey(r), ratelimit.ClassIP) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// limit takes token of client key from bucket of class and returns whether request is let in,
+// otherwise it writes rate limit error
+func (s Server) limit(w http.ResponseWriter, key, class string) bool {
+	wait, err := s.RateLimit.Take(key, class)
+	if err != nil {
+		log.Println(err)
+	}
+	if err != nil || wait <= 0 {
+		return true
+	}
+	retry := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retry))
+	problemError(w, errors.Error{Code: errors.RateLimitError, Message: "rate limit: too many " + class + " requests, id: " + key, Info: retry})
+	return false
+}
@@ -0,0 +1,508 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/gorilla/mux"
+)
+
+// Block of v2 request bodies
+type (
+	registerRequest struct {
+		ID       string            `json:"id"`
+		Name     string            `json:"name"`
+		Metadata map[string]string `json:"metadata"`
+	}
+	fundRequest struct {
+		Points     int    `json:"points"`
+		Currency   string `json:"currency"`
+		ExpireDays int    `json:"expireDays"`
+	}
+	pointsRequest struct {
+		Points   int    `json:"points"`
+		Currency string `json:"currency"`
+	}
+	transferRequest struct {
+		To       string `json:"to"`
+		Points   int    `json:"points"`
+		Currency string `json:"currency"`
+	}
+	holdRequest struct {
+		ID       string `json:"id"`
+		Points   int    `json:"points"`
+		Currency string `json:"currency"`
+		TTL      string `json:"ttl"`
+	}
+	limitsRequest struct {
+		Daily  int `json:"daily"`
+		Weekly int `json:"weekly"`
+	}
+	excludeRequest struct {
+		Days int `json:"days"`
+	}
+	redeemRequest struct {
+		Code string `json:"code"`
+	}
+	tournamentRequest struct {
+		ID         string `json:"id"`
+		Currency   string `json:"currency"`
+		Deposit    int    `json:"deposit"`
+		MaxEntries int    `json:"maxEntries"`
+		Teams      bool   `json:"teams"`
+		TargetID   string `json:"targetId"`
+		Seats      int    `json:"seats"`
+	}
+	entryRequest struct {
+		PlayerID string `json:"playerId"`
+		TeamID   string `json:"teamId"`
+		Payer    string `json:"payer"`
+	}
+	teamRequest struct {
+		ID      string   `json:"id"`
+		Captain string   `json:"captain"`
+		Members []string `json:"members"`
+		Shares  []int    `json:"shares"`
+	}
+	batchRequest struct {
+		Atomic     bool               `json:"atomic"`
+		Operations []entity.Operation `json:"operations"`
+	}
+)
+
+// HandleRegisterV2 handles POST /v2/players
+func (s Server) HandleRegisterV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req registerRequest
+		if !decodeBody(w, r, "register", &req) {
+			return
+		}
+		account, err := s.Controller.Register(req.ID, req.Name, req.Metadata)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/v2/players/"+account.ID)
+		jsonResponse(w, account, http.StatusCreated)
+	}
+}
+
+// HandleAccountV2 handles GET /v2/players/{id}
+func (s Server) HandleAccountV2() http.HandlerFunc {
+	return s.handleAccountV2(s.Controller.Account)
+}
+
+// HandleSuspendV2 handles POST /v2/players/{id}/suspend
+func (s Server) HandleSuspendV2() http.HandlerFunc {
+	return s.handleAccountV2(s.Controller.Suspend)
+}
+
+// HandleReinstateV2 handles POST /v2/players/{id}/reinstate
+func (s Server) HandleReinstateV2() http.HandlerFunc {
+	return s.handleAccountV2(s.Controller.Reinstate)
+}
+
+func (s Server) handleAccountV2(action func(id string) (entity.Account, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, err := action(mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, account, http.StatusOK)
+	}
+}
+
+// HandleCloseV2 handles DELETE /v2/players/{id}, with withdraw=true query player is withdrawn from open tournaments
+func (s Server) HandleCloseV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		closure, err := s.Controller.CloseAccount(mux.Vars(r)["id"], r.URL.Query().Get("withdraw") == "true")
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, closure, http.StatusOK)
+	}
+}
+
+// HandleFundV2 handles POST /v2/players/{id}/fund, new player is returned with 201 status
+func (s Server) HandleFundV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req fundRequest
+		if !decodeBody(w, r, "fund player", &req) {
+			return
+		}
+		player, err := s.Controller.Fund(mux.Vars(r)["id"], req.Currency, req.Points, time.Duration(req.ExpireDays)*24*time.Hour)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		if player != (entity.Player{}) {
+			w.Header().Set("Location", "/v2/players/"+player.ID+"/balance")
+			jsonResponse(w, player, http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleTakeV2 handles POST /v2/players/{id}/take
+func (s Server) HandleTakeV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req pointsRequest
+		if !decodeBody(w, r, "take points", &req) {
+			return
+		}
+		err := s.Controller.Take(mux.Vars(r)["id"], req.Currency, req.Points)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleTransferV2 handles POST /v2/players/{id}/transfers
+func (s Server) HandleTransferV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req transferRequest
+		if !decodeBody(w, r, "transfer points", &req) {
+			return
+		}
+		err := s.Controller.Transfer(mux.Vars(r)["id"], req.To, req.Currency, req.Points)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleBalanceV2 handles GET /v2/players/{id}/balance
+func (s Server) HandleBalanceV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := checkPlayer(r, mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		balance, err := s.Controller.Balance(mux.Vars(r)["id"], r.URL.Query().Get("currency"))
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, balance, http.StatusOK)
+	}
+}
+
+// HandleHoldV2 handles POST /v2/players/{id}/holds
+func (s Server) HandleHoldV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req holdRequest
+		if !decodeBody(w, r, "hold points", &req) {
+			return
+		}
+		ttl := defaultHoldTTL
+		if req.TTL != "" {
+			var err error
+			ttl, err = time.ParseDuration(req.TTL)
+			if err != nil {
+				problemError(w, errors.Error{Code: errors.NotNumberError, Message: "cannot hold points, ttl is not duration: " + req.TTL, Info: err.Error()})
+				return
+			}
+		}
+		hold, err := s.Controller.Hold(req.ID, mux.Vars(r)["id"], req.Currency, req.Points, ttl)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/v2/holds/"+hold.ID)
+		jsonResponse(w, hold, http.StatusCreated)
+	}
+}
+
+// HandleCaptureV2 handles POST /v2/holds/{id}/capture
+func (s Server) HandleCaptureV2() http.HandlerFunc {
+	return s.handleNoContentV2(s.Controller.Capture)
+}
+
+// HandleReleaseV2 handles DELETE /v2/holds/{id}
+func (s Server) HandleReleaseV2() http.HandlerFunc {
+	return s.handleNoContentV2(s.Controller.Release)
+}
+
+// handleNoContentV2 applies action to resource of path id and answers with no content
+func (s Server) handleNoContentV2(action func(id string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := action(mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleLimitsV2 handles GET /v2/players/{id}/limits
+func (s Server) HandleLimitsV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limits, err := s.Controller.Limits(mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, limits, http.StatusOK)
+	}
+}
+
+// HandleSetLimitsV2 handles PUT /v2/players/{id}/limits, zero limit means no limit
+func (s Server) HandleSetLimitsV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req limitsRequest
+		if !decodeBody(w, r, "set limits", &req) {
+			return
+		}
+		limits, err := s.Controller.SetLimits(mux.Vars(r)["id"], req.Daily, req.Weekly)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, limits, http.StatusOK)
+	}
+}
+
+// HandleSelfExcludeV2 handles POST /v2/players/{id}/exclusion
+func (s Server) HandleSelfExcludeV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req excludeRequest
+		if !decodeBody(w, r, "self exclude player", &req) {
+			return
+		}
+		limits, err := s.Controller.SelfExclude(mux.Vars(r)["id"], time.Duration(req.Days)*24*time.Hour)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, limits, http.StatusOK)
+	}
+}
+
+// HandleRedeemV2 handles POST /v2/players/{id}/redemptions
+func (s Server) HandleRedeemV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req redeemRequest
+		if !decodeBody(w, r, "redeem promo", &req) {
+			return
+		}
+		red, err := s.Controller.Redeem(req.Code, mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, red, http.StatusOK)
+	}
+}
+
+// HandleHistoryV2 handles GET /v2/players/{id}/tournaments
+func (s Server) HandleHistoryV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := checkPlayer(r, mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		history, err := s.Controller.History(mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, history, http.StatusOK)
+	}
+}
+
+// HandleAnnounceV2 handles POST /v2/tournaments, tournament with targetId is satellite,
+// tournament with teams set can be joined by teams only
+func (s Server) HandleAnnounceV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req tournamentRequest
+		if !decodeBody(w, r, "create tournament", &req) {
+			return
+		}
+		var err error
+		switch {
+		case req.TargetID != "":
+			err = s.Controller.AnnounceSatellite(req.ID, req.Deposit, req.TargetID, req.Seats)
+		case req.Teams:
+			err = s.Controller.AnnounceTeamTournament(req.ID, req.Currency, req.Deposit)
+		default:
+			if req.MaxEntries == 0 {
+				req.MaxEntries = 1
+			}
+			err = s.Controller.AnnounceTournament(req.ID, req.Currency, req.Deposit, req.MaxEntries)
+		}
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/v2/tournaments/"+req.ID)
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// HandleTournamentV2 handles GET /v2/tournaments/{id}
+func (s Server) HandleTournamentV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tour, err := s.Controller.Tournament(mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, tour, http.StatusOK)
+	}
+}
+
+// HandleJoinV2 handles POST /v2/tournaments/{id}/entries, entry has either player or team,
+// with payer set to captain team deposit is paid by captain only
+func (s Server) HandleJoinV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req entryRequest
+		if !decodeBody(w, r, "join tournament", &req) {
+			return
+		}
+		tourID := mux.Vars(r)["id"]
+		err := checkJoin(r, req.PlayerID, req.TeamID)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		if req.TeamID != "" {
+			err = s.Controller.JoinTeam(tourID, req.TeamID, req.Payer == "captain")
+		} else {
+			err = s.Controller.JoinTournament(tourID, req.PlayerID)
+		}
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/v2/tournaments/"+tourID)
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// HandleResultsV2 handles POST /v2/tournaments/{id}/results, which closes tournament and chooses winners
+func (s Server) HandleResultsV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		winners, err := s.Controller.Results(mux.Vars(r)["id"])
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, winners, http.StatusOK)
+	}
+}
+
+// HandleCreateTeamV2 handles POST /v2/teams
+func (s Server) HandleCreateTeamV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req teamRequest
+		if !decodeBody(w, r, "create team", &req) {
+			return
+		}
+		team, err := s.Controller.CreateTeam(req.ID, req.Captain, req.Members, req.Shares)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, team, http.StatusCreated)
+	}
+}
+
+// HandleCreatePromoV2 handles POST /v2/promos
+func (s Server) HandleCreatePromoV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var promo entity.Promo
+		if !decodeBody(w, r, "create promo", &promo) {
+			return
+		}
+		promo, err := s.Controller.CreatePromo(promo)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, promo, http.StatusCreated)
+	}
+}
+
+// HandleBatchV2 handles POST /v2/batch, result of every operation is returned, even if some of them have failed
+func (s Server) HandleBatchV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		if !decodeBody(w, r, "apply batch", &req) {
+			return
+		}
+		result, err := s.Controller.Batch(req.Operations, req.Atomic)
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, result, http.StatusOK)
+	}
+}
+
+// HandleListPlayersV2 handles GET /v2/players, it accepts the same query as v1 list
+func (s Server) HandleListPlayersV2() http.HandlerFunc {
+	s.LegacyErrors = false
+	return s.HandleListPlayers()
+}
+
+// HandleListTournamentsV2 handles GET /v2/tournaments, it accepts the same query as v1 list
+func (s Server) HandleListTournamentsV2() http.HandlerFunc {
+	s.LegacyErrors = false
+	return s.HandleListTournaments()
+}
+
+// routesV2 registers v2 routes, every route accepts only its methods
+func (s Server) routesV2(r *mux.Router) {
+	r.HandleFunc("/v2/players", s.HandleRegisterV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players", s.HandleListPlayersV2()).Methods(http.MethodGet)
+	r.HandleFunc("/v2/players/{id}", s.HandleAccountV2()).Methods(http.MethodGet)
+	r.HandleFunc("/v2/players/{id}", s.HandleCloseV2()).Methods(http.MethodDelete)
+	r.HandleFunc("/v2/players/{id}/suspend", s.HandleSuspendV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/reinstate", s.HandleReinstateV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/fund", s.HandleFundV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/take", s.HandleTakeV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/transfers", s.HandleTransferV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/balance", s.HandleBalanceV2()).Methods(http.MethodGet)
+	r.HandleFunc("/v2/players/{id}/holds", s.HandleHoldV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/limits", s.HandleLimitsV2()).Methods(http.MethodGet)
+	r.HandleFunc("/v2/players/{id}/limits", s.HandleSetLimitsV2()).Methods(http.MethodPut)
+	r.HandleFunc("/v2/players/{id}/exclusion", s.HandleSelfExcludeV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/redemptions", s.HandleRedeemV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/players/{id}/tournaments", s.HandleHistoryV2()).Methods(http.MethodGet)
+	r.HandleFunc("/v2/holds/{id}/capture", s.HandleCaptureV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/holds/{id}", s.HandleReleaseV2()).Methods(http.MethodDelete)
+	r.HandleFunc("/v2/tournaments", s.HandleAnnounceV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/tournaments", s.HandleListTournamentsV2()).Methods(http.MethodGet)
+	r.HandleFunc("/v2/tournaments/{id}", s.HandleTournamentV2()).Methods(http.MethodGet)
+	r.HandleFunc("/v2/tournaments/{id}/entries", s.HandleJoinV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/tournaments/{id}/results", s.HandleResultsV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/teams", s.HandleCreateTeamV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/promos", s.HandleCreatePromoV2()).Methods(http.MethodPost)
+	r.HandleFunc("/v2/batch", s.HandleBatchV2()).Methods(http.MethodPost)
+}
+
+// decodeBody decodes JSON request body into v, unknown fields are rejected.
+// If body cannot be decoded, error is written and false is returned.
+func decodeBody(w http.ResponseWriter, r *http.Request, op string, v interface{}) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err != nil {
+		problemError(w, errors.Error{Code: errors.JSONError, Message: "cannot " + op + ", request body is not valid json", Info: err.Error()})
+		return false
+	}
+	return true
+}
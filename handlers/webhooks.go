@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+)
+
+// webhookRequest is body of POST /admin/webhooks, secret is generated, if it is not set
+type webhookRequest struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// HandleCreateWebhook handles POST /admin/webhooks, created webhook is returned with its secret
+func (s Server) HandleCreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req webhookRequest
+		if !decodeBody(w, r, "create webhook", &req) {
+			return
+		}
+		webhook, err := s.Controller.CreateWebhook(entity.Webhook{ID: req.ID, URL: req.URL, Events: req.Events, Secret: req.Secret})
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/admin/webhooks/"+webhook.ID)
+		jsonResponse(w, webhook, http.StatusCreated)
+	}
+}
+
+// HandleWebhooks handles GET /admin/webhooks
+func (s Server) HandleWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhooks, err := s.Controller.Webhooks()
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, webhooks, http.StatusOK)
+	}
+}
+
+// HandleDeleteWebhook handles DELETE /admin/webhooks/{id}
+func (s Server) HandleDeleteWebhook() http.HandlerFunc {
+	return s.handleNoContentV2(s.Controller.DeleteWebhook)
+}
+
+// HandleDeadDeliveries handles GET /admin/deliveries/dead
+func (s Server) HandleDeadDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deliveries, err := s.Controller.DeadDeliveries()
+		if err != nil {
+			problemError(w, err)
+			return
+		}
+		jsonResponse(w, deliveries, http.StatusOK)
+	}
+}
+
+// HandleRetryDelivery handles POST /admin/deliveries/{id}/retry
+func (s Server) HandleRetryDelivery() http.HandlerFunc {
+	return s.handleNoContentV2(s.Controller.RetryDelivery)
+}
+
+func (s Server) routesAdmin(r *mux.Router) {
+	r.HandleFunc("/admin/webhooks", s.HandleCreateWebhook()).Methods(http.MethodPost)
+	r.HandleFunc("/admin/webhooks", s.HandleWebhooks()).Methods(http.MethodGet)
+	r.HandleFunc("/admin/webhooks/{id}", s.HandleDeleteWebhook()).Methods(http.MethodDelete)
+	r.HandleFunc("/admin/deliveries/dead", s.HandleDeadDeliveries()).Methods(http.MethodGet)
+	r.HandleFunc("/admin/deliveries/{id}/retry", s.HandleRetryDelivery()).Methods(http.MethodPost)
+}
@@ -3,16 +3,25 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/dmitriyomelyusik/Tournament/auth"
 	"github.com/dmitriyomelyusik/Tournament/controller"
 	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/events"
 	"github.com/dmitriyomelyusik/Tournament/handlers"
 	"github.com/dmitriyomelyusik/Tournament/mongo"
 	"github.com/dmitriyomelyusik/Tournament/postgres"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
+	"github.com/dmitriyomelyusik/Tournament/rpc"
+	"github.com/dmitriyomelyusik/Tournament/webhooks"
+	"github.com/golang-jwt/jwt/v5"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 )
 
 // Environment variables that needs to open database
@@ -25,6 +34,28 @@ const (
 	DBDRIVER = "DBDRIVER"
 )
 
+// Environment variables of player tokens. HMAC key is secret, RSA key is path to PEM file of public key,
+// tokens are not accepted, if neither is set. Audience must be set with key, it is always checked.
+const (
+	JWTHMACKEY  = "JWTHMACKEY"
+	JWTRSAKEY   = "JWTRSAKEY"
+	JWTAUDIENCE = "JWTAUDIENCE"
+)
+
+// Environment variables of rate limits. Limits are list of class=rate:burst, where class is join, results, write, read,
+// ip (every request of IP address before authentication, only with own rule) or default, and rate is number of requests
+// per second, requests are not limited, if it is not set. Store set to shared keeps buckets in database, so limits hold
+// across instances, otherwise every instance keeps them in memory. Trusted proxies are list of IP addresses or networks
+// of proxies, requests from them are limited by client address of X-Forwarded-For.
+const (
+	RATELIMITS     = "RATELIMITS"
+	RATELIMITSTORE = "RATELIMITSTORE"
+	TRUSTEDPROXIES = "TRUSTEDPROXIES"
+)
+
+// LEGACYERRORS set to true makes v1 routes answer errors with v1 statuses and bodies
+const LEGACYERRORS = "LEGACYERRORS"
+
 func main() {
 	var (
 		db  controller.Database
@@ -45,8 +76,38 @@ func main() {
 		panic("You didn't set DBDRIVER variable.")
 	}
 
-	ctl := controller.Game{DB: db}
-	server := handlers.Server{Controller: ctl}
+	bus := events.NewBus()
+	ctl := controller.Game{DB: db, Events: bus}
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		err = runKeys(ctl, os.Args[2:])
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	go ctl.SweepLots(time.Minute, nil)
+	go webhooks.Dispatcher{DB: db}.Run(5*time.Second, nil)
+	server := handlers.Server{Controller: ctl, Events: bus, LegacyErrors: os.Getenv(LEGACYERRORS) == "true", Auth: ctl}
+	tokens, err := getVerifier()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if tokens != nil {
+		server.Tokens = *tokens
+	}
+	limiter, err := getLimiter(db)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if limiter != nil {
+		go limiter.Run(time.Minute, nil)
+		server.RateLimit = *limiter
+	}
+	server.TrustedProxies, err = ratelimit.ParseProxies(os.Getenv(TRUSTEDPROXIES))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	go serveGRPC(rpc.Server{Controller: ctl, Auth: ctl, Tokens: server.Tokens, RateLimit: server.RateLimit, TrustedProxies: server.TrustedProxies})
 	r := handlers.NewRouter(server)
 	s := http.Server{
 		Addr:         ":8080",
@@ -60,6 +121,91 @@ func main() {
 	}
 }
 
+// keysUsage describes API key management commands
+const keysUsage = `usage:
+	game keys create admin|client|read-only [name]	creates key, it is printed only once
+	game keys list					lists keys
+	game keys revoke <id>				revokes key`
+
+// runKeys runs API key management command
+func runKeys(ctl controller.Game, args []string) error {
+	switch {
+	case len(args) >= 2 && args[0] == "create":
+		key, secret, err := ctl.CreateAPIKey(strings.Join(args[2:], " "), args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("id: %v\nrole: %v\nkey: %v\n", key.ID, key.Role, secret)
+	case len(args) == 1 && args[0] == "list":
+		keys, err := ctl.APIKeys()
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			fmt.Printf("%v\t%v\t%v\t%v\n", k.ID, k.Role, k.Created.Format(time.RFC3339), k.Name)
+		}
+	case len(args) == 2 && args[0] == "revoke":
+		return ctl.RevokeAPIKey(args[1])
+	default:
+		fmt.Println(keysUsage)
+		os.Exit(2)
+	}
+	return nil
+}
+
+// getVerifier returns verifier of player tokens with keys from environment, it is nil, if no key is set
+func getVerifier() (*auth.Verifier, error) {
+	v := auth.Verifier{HMACKey: []byte(os.Getenv(JWTHMACKEY)), Audience: os.Getenv(JWTAUDIENCE)}
+	if path := os.Getenv(JWTRSAKEY); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "reading rsa key: cannot read key file", Info: err.Error()}
+		}
+		v.RSAKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "reading rsa key: key is not valid", Info: err.Error()}
+		}
+	}
+	if len(v.HMACKey) == 0 && v.RSAKey == nil {
+		return nil, nil
+	}
+	if v.Audience == "" {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "token verifier: " + JWTAUDIENCE + " must be set with token key"}
+	}
+	return &v, nil
+}
+
+// getLimiter returns rate limiter with rules from environment, it is nil, if no rule is set
+func getLimiter(db controller.Database) (*ratelimit.Limiter, error) {
+	rules, err := ratelimit.ParseRules(os.Getenv(RATELIMITS))
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	var store ratelimit.Store = ratelimit.NewMemory()
+	if os.Getenv(RATELIMITSTORE) == "shared" {
+		shared, ok := db.(ratelimit.Store)
+		if !ok {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "rate limit: database cannot keep buckets"}
+		}
+		store = shared
+	}
+	return &ratelimit.Limiter{Store: store, Rules: rules}, nil
+}
+
+// serveGRPC serves gRPC API on separate port, it uses the same controller, authentication and rate limits as HTTP API
+func serveGRPC(server rpc.Server) {
+	lis, err := net.Listen("tcp", ":9090")
+	if err != nil {
+		log.Fatal(errors.Error{Code: errors.ConnectionError, Message: "grpc listen: error occured", Info: err.Error()})
+	}
+	g := grpc.NewServer(grpc.ChainUnaryInterceptor(server.Interceptors()...))
+	rpc.RegisterTournamentServiceServer(g, server)
+	err = g.Serve(lis)
+	if err != nil {
+		log.Fatal(errors.Error{Code: errors.ConnectionError, Message: "grpc serve: error occured", Info: err.Error()})
+	}
+}
+
 func getMongo() (*mongo.Mongo, error) {
 	m, err := mongo.NewDB("localhost")
 	if err != nil {
@@ -0,0 +1,156 @@
+package mongo
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/mongo/logs"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CreateAccount registers player account
+func (m *Mongo) CreateAccount(account entity.Account) error {
+	err := m.accounts.Insert(account)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create account: using duplicated id to create account, id " + account.ID}
+	}
+	return nil
+}
+
+// GetAccount returns player account
+func (m *Mongo) GetAccount(id string) (entity.Account, error) {
+	var account entity.Account
+	err := m.accounts.FindId(id).One(&account)
+	if err != nil {
+		return entity.Account{}, errors.Error{Code: errors.NotFoundError, Message: "get account: cannot find account, id " + id}
+	}
+	account.Created = account.Created.UTC()
+	return account, nil
+}
+
+// SetAccountStatus sets status of player account
+func (m *Mongo) SetAccountStatus(id, status string) error {
+	err := m.accounts.UpdateId(id, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "set account status: cannot find account, id " + id}
+	}
+	return nil
+}
+
+// CloseAccount withdraws player from open tournaments, if withdraw is set, pays out their balances
+// and marks account closed. Player, who was funded without registration, gets closed account.
+func (m *Mongo) CloseAccount(id string, withdraw bool) (entity.Closure, error) {
+	closure := entity.Closure{PlayerID: id, Payouts: []entity.Player{}}
+	var teams []entity.Team
+	err := m.teams.Find(bson.M{"members.playerId": id}).Select(bson.M{"_id": 1}).All(&teams)
+	if err != nil {
+		return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	teamIDs := make([]string, len(teams))
+	for i := range teams {
+		teamIDs[i] = teams[i].ID
+	}
+	n, err := m.tournaments.Find(bson.M{"isOpen": true, "teams": bson.M{"$in": teamIDs}}).Count()
+	if err != nil {
+		return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	if n > 0 {
+		return entity.Closure{}, errors.Error{Code: errors.OpenEntriesError, Message: "close account: player is in team of open tournament, id " + id}
+	}
+	var tours []entity.Tournament
+	err = m.tournaments.Find(bson.M{"isOpen": true, "participants": id}).Sort("_id").All(&tours)
+	if err != nil {
+		return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	if len(tours) > 0 && !withdraw {
+		return entity.Closure{}, errors.Error{Code: errors.OpenEntriesError, Message: "close account: player has entries in " + strconv.Itoa(len(tours)) + " open tournaments, id " + id}
+	}
+	for _, t := range tours {
+		err = m.withdraw(t, id)
+		if err != nil {
+			return entity.Closure{}, err
+		}
+		closure.Withdrawn = append(closure.Withdrawn, t.ID)
+	}
+	_, err = m.holds.RemoveAll(bson.M{"playerId": id})
+	if err != nil {
+		return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	balances, err := m.GetBalances(id)
+	if err == nil {
+		closure.Payouts = balances
+	}
+	for _, b := range balances {
+		if b.Points == 0 {
+			continue
+		}
+		err = m.players.UpdateId(id, bson.M{"$inc": bson.M{pointsKey(b.Currency): -b.Points}})
+		if err != nil {
+			return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+		}
+		err = m.logBalance(id, b.Currency, logger.Payout, -b.Points)
+		if err != nil {
+			return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+		}
+	}
+	_, err = m.lots.RemoveAll(bson.M{"playerId": id})
+	if err != nil {
+		return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	err = m.accounts.UpdateId(id, bson.M{"$set": bson.M{"status": entity.StatusClosed}})
+	if err == nil {
+		return closure, nil
+	}
+	if len(balances) == 0 {
+		return entity.Closure{}, errors.Error{Code: errors.NotFoundError, Message: "close account: cannot find player, id " + id}
+	}
+	err = m.accounts.Insert(entity.Account{ID: id, Name: id, Created: time.Now().UTC().Truncate(time.Second), Status: entity.StatusClosed})
+	if err != nil {
+		return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	return closure, nil
+}
+
+// withdraw removes player entries from open tournament and returns deposits, which they have paid, from prize.
+// Tickets and seats are not refunded, participant without entries has joined before entries and has paid deposit once.
+func (m *Mongo) withdraw(t entity.Tournament, id string) error {
+	refund, entered := 0, false
+	for _, e := range t.Entries {
+		if e.PlayerID != id {
+			continue
+		}
+		entered = true
+		if !e.Free {
+			refund += t.Deposit
+		}
+	}
+	if !entered {
+		refund = t.Deposit
+	}
+	err := m.tournaments.Update(bson.M{"_id": t.ID, "isOpen": true},
+		bson.M{"$pull": bson.M{"entries": bson.M{"playerId": id}, "participants": id}, "$inc": bson.M{"prize": -refund}})
+	if err != nil {
+		return errors.Error{Code: errors.ClosedTournamentError, Message: "close account: tournament has been closed, id " + t.ID}
+	}
+	err = m.participations.Remove(bson.M{"tournamentId": t.ID, "playerId": id})
+	if err != nil && err != mgo.ErrNotFound {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	if refund == 0 {
+		return nil
+	}
+	currency := tourCurrency(t)
+	err = m.players.UpdateId(id, bson.M{"$inc": bson.M{pointsKey(currency): refund}})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	m.addLot(id, currency, refund, time.Time{})
+	err = m.logBalance(id, currency, logger.Refund, refund)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("close account: ")
+	}
+	return nil
+}
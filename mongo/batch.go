@@ -0,0 +1,36 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// ApplyBatch cannot apply batch as whole, because mongo has no transactions, so nothing is applied
+func (m *Mongo) ApplyBatch(ops []entity.Operation) (int, error) {
+	return -1, errors.Error{Code: errors.TransactionError, Message: "apply batch: mongo has no transactions, atomic batch cannot be applied"}
+}
+
+// ApplyEach applies every operation independently and returns their errors in order of operations
+func (m *Mongo) ApplyEach(ops []entity.Operation) ([]error, error) {
+	errs := make([]error, len(ops))
+	for i, op := range ops {
+		errs[i] = m.apply(op)
+	}
+	return errs, nil
+}
+
+// apply applies fund, take or join operation
+func (m *Mongo) apply(op entity.Operation) error {
+	switch op.Type {
+	case entity.OpFund:
+		_, err := m.FundLot(entity.Lot{PlayerID: op.PlayerID, Currency: op.Currency, Points: op.Points, Created: time.Now().UTC()})
+		return err
+	case entity.OpTake:
+		return m.UpdatePlayer(op.PlayerID, op.Currency, -op.Points)
+	case entity.OpJoin:
+		return m.UpdateTourAndPlayer(op.TournamentID, op.PlayerID)
+	}
+	return errors.Error{Code: errors.InvalidBatchError, Message: "apply operation: unknown operation type, id: " + op.PlayerID, Info: op.Type}
+}
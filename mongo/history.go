@@ -0,0 +1,138 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GetHistory returns every tournament, which player has participated in, the latest ones first
+func (m *Mongo) GetHistory(playerID string) ([]entity.Participation, error) {
+	history := []entity.Participation{}
+	err := m.participations.Find(bson.M{"playerId": playerID}).Sort("-joined", "tournamentId").All(&history)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("get history: ")
+	}
+	ids := make([]string, 0, len(history))
+	for _, part := range history {
+		ids = append(ids, part.TournamentID)
+	}
+	var tours []entity.Tournament
+	err = m.tournaments.Find(bson.M{"_id": bson.M{"$in": ids}}).Select(bson.M{"isOpen": 1}).All(&tours)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("get history: ")
+	}
+	open := make(map[string]bool, len(tours))
+	for _, t := range tours {
+		open[t.ID] = t.IsOpen
+	}
+	for i := range history {
+		history[i].IsOpen = open[history[i].TournamentID]
+		history[i].Joined = history[i].Joined.UTC()
+	}
+	return history, nil
+}
+
+// fillParticipations records participations in tournaments, which were played before participations were introduced,
+// when participations collection is created. Participants and paid entries have paid deposits, participants without
+// entries have joined before re-entries, so they have paid deposit once, team members have paid their shares.
+// Winners get their placings and prizes.
+func fillParticipations(db *mgo.Database, tournaments, teams, participations *mgo.Collection) error {
+	exists, err := hasCollection(db, participations.Name)
+	if err != nil || exists {
+		return err
+	}
+	var tours []entity.Tournament
+	err = tournaments.Find(nil).All(&tours)
+	if err != nil {
+		return err
+	}
+	for _, t := range tours {
+		parts := make(map[string]*entity.Participation)
+		var ids []string
+		part := func(playerID string) *entity.Participation {
+			if parts[playerID] == nil {
+				joined := t.Created
+				if joined.IsZero() {
+					joined = time.Now()
+				}
+				parts[playerID] = &entity.Participation{TournamentID: t.ID, PlayerID: playerID, Currency: tourCurrency(t), Joined: joined.UTC()}
+				ids = append(ids, playerID)
+			}
+			return parts[playerID]
+		}
+		entered := make(map[string]bool)
+		for _, e := range t.Entries {
+			entered[e.PlayerID] = true
+			if !e.Free {
+				part(e.PlayerID).Paid += t.Deposit
+			}
+		}
+		for _, playerID := range t.Participants {
+			if !entered[playerID] {
+				part(playerID).Paid += t.Deposit
+			}
+		}
+		for _, teamID := range t.Teams {
+			var team entity.Team
+			err = teams.FindId(teamID).One(&team)
+			if err == mgo.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			shares := team.Split(t.Deposit)
+			for i, member := range team.Members {
+				part(member.PlayerID).Paid += shares[i]
+			}
+		}
+		winners := t.Winners
+		if len(winners) == 0 && t.Winner.ID != "" {
+			winners = []entity.Winner{t.Winner}
+		}
+		for i, w := range winners {
+			p := part(w.ID)
+			placing := i + 1
+			if t.IsTeam {
+				placing = 1
+			}
+			if p.Placing == 0 || placing < p.Placing {
+				p.Placing = placing
+			}
+			p.Prize += w.Prize
+		}
+		for _, playerID := range ids {
+			err = participations.Insert(parts[playerID])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// participate records player participation in tournament, paid points are added to points paid before
+func (m *Mongo) participate(t entity.Tournament, playerID string, paid int) error {
+	_, err := m.participations.Upsert(bson.M{"tournamentId": t.ID, "playerId": playerID},
+		bson.M{"$inc": bson.M{"paid": paid, "prize": 0}, "$setOnInsert": bson.M{"currency": tourCurrency(t), "joined": time.Now().UTC()}})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "participate: cannot record participation, playerID: " + playerID, Info: err.Error()}
+	}
+	return nil
+}
+
+// place records player placing and prize in tournament results, the best placing is kept, if player placed twice.
+// Chosen winner is written into outbox.
+func (m *Mongo) place(t entity.Tournament, playerID string, placing, prize int) error {
+	_, err := m.participations.Upsert(bson.M{"tournamentId": t.ID, "playerId": playerID},
+		bson.M{"$min": bson.M{"placing": placing}, "$inc": bson.M{"prize": prize, "paid": 0}, "$setOnInsert": bson.M{"currency": tourCurrency(t), "joined": time.Now().UTC()}})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "place: cannot record placing, playerID: " + playerID, Info: err.Error()}
+	}
+	m.outbox(entity.Event{Type: entity.EventWinnerChosen, TournamentID: t.ID, PlayerID: playerID, Points: prize, Currency: tourCurrency(t)})
+	return nil
+}
@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/mongo/logs"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CreateHold reserves player points, if player has enough available points
+func (m *Mongo) CreateHold(hold entity.Hold) error {
+	player, err := m.GetPlayer(hold.PlayerID, hold.Currency)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "create hold: cannot find player, id " + hold.PlayerID}
+	}
+	held, err := m.heldPoints(hold.PlayerID, hold.Currency)
+	if err != nil {
+		return err
+	}
+	if player.Points-held < hold.Points {
+		return errors.Error{Code: errors.NegativePointsNumberError, Message: "create hold: player doesn't have enough available points, id " + hold.PlayerID}
+	}
+	err = m.holds.Insert(hold)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create hold: using duplicated id to create hold, id " + hold.ID}
+	}
+	return nil
+}
+
+// GetHolds returns player holds in currency, which have not expired yet
+func (m *Mongo) GetHolds(playerID, currency string) ([]entity.Hold, error) {
+	var holds []entity.Hold
+	err := m.holds.Find(bson.M{"playerId": playerID, "currency": currency, "expires": bson.M{"$gt": time.Now()}}).Sort("expires", "_id").All(&holds)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("get holds: ")
+	}
+	return holds, nil
+}
+
+// CaptureHold takes held points from player and returns captured hold
+func (m *Mongo) CaptureHold(id string) (entity.Hold, error) {
+	var hold entity.Hold
+	err := m.holds.Find(bson.M{"_id": id, "expires": bson.M{"$gt": time.Now()}}).One(&hold)
+	if err != nil {
+		return entity.Hold{}, errors.Error{Code: errors.NotFoundError, Message: "capture hold: hold does not exist or has expired, id " + id}
+	}
+	err = m.holds.RemoveId(id)
+	if err != nil {
+		return entity.Hold{}, errors.Error{Code: errors.NotFoundError, Message: "capture hold: hold does not exist or has expired, id " + id}
+	}
+	err = m.players.UpdateId(hold.PlayerID, bson.M{"$inc": bson.M{pointsKey(hold.Currency): -hold.Points}})
+	if err != nil {
+		return entity.Hold{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("capture hold: ")
+	}
+	m.consumeLots(hold.PlayerID, hold.Currency, hold.Points)
+	hold.Expires = hold.Expires.UTC()
+	return hold, m.logBalance(hold.PlayerID, hold.Currency, logger.Capture, -hold.Points)
+}
+
+// ReleaseHold deletes hold, so player can spend held points again
+func (m *Mongo) ReleaseHold(id string) error {
+	err := m.holds.Remove(bson.M{"_id": id, "expires": bson.M{"$gt": time.Now()}})
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "release hold: hold does not exist or has expired, id " + id}
+	}
+	return nil
+}
+
+func (m *Mongo) heldPoints(playerID, currency string) (int, error) {
+	holds, err := m.GetHolds(playerID, currency)
+	if err != nil {
+		return 0, err
+	}
+	var held int
+	for _, h := range holds {
+		held += h.Points
+	}
+	return held, nil
+}
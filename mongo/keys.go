@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CreateAPIKey creates API key
+func (m *Mongo) CreateAPIKey(key entity.APIKey) error {
+	err := m.keys.Insert(key)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create api key: using duplicated id to create api key, id " + key.ID}
+	}
+	return nil
+}
+
+// GetAPIKey returns API key by its hash
+func (m *Mongo) GetAPIKey(hash string) (entity.APIKey, error) {
+	var key entity.APIKey
+	err := m.keys.Find(bson.M{"hash": hash}).One(&key)
+	if err == mgo.ErrNotFound {
+		return entity.APIKey{}, errors.Error{Code: errors.NotFoundError, Message: "get api key: cannot find api key"}
+	}
+	if err != nil {
+		return entity.APIKey{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("get api key: ")
+	}
+	key.Created = key.Created.UTC()
+	return key, nil
+}
+
+// ListAPIKeys returns every API key ordered by creation time
+func (m *Mongo) ListAPIKeys() ([]entity.APIKey, error) {
+	keys := []entity.APIKey{}
+	err := m.keys.Find(nil).Sort("created", "_id").All(&keys)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list api keys: " + err.Error()}
+	}
+	for i := range keys {
+		keys[i].Created = keys[i].Created.UTC()
+	}
+	return keys, nil
+}
+
+// DeleteAPIKey deletes API key
+func (m *Mongo) DeleteAPIKey(id string) error {
+	err := m.keys.RemoveId(id)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "delete api key: api key does not exist, id " + id}
+	}
+	return nil
+}
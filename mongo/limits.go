@@ -0,0 +1,50 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GetLimits returns responsible play limits of player
+func (m *Mongo) GetLimits(playerID string) (entity.Limits, error) {
+	var limits entity.Limits
+	err := m.limits.FindId(playerID).One(&limits)
+	if err != nil {
+		return entity.Limits{}, errors.Error{Code: errors.NotFoundError, Message: "get limits: player has no limits, id " + playerID}
+	}
+	limits.ExcludedUntil = limits.ExcludedUntil.UTC()
+	if limits.Pending != nil {
+		limits.Pending.Applies = limits.Pending.Applies.UTC()
+	}
+	return limits, nil
+}
+
+// SetLimits creates or replaces responsible play limits of player
+func (m *Mongo) SetLimits(limits entity.Limits) error {
+	_, err := m.limits.UpsertId(limits.PlayerID, limits)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("set limits: ")
+	}
+	return nil
+}
+
+// GetSpent returns points, which player has spent in all currencies since the time. Points of open holds
+// are counted as spent, because they are spent, when holds are captured.
+func (m *Mongo) GetSpent(playerID string, since time.Time) (int, error) {
+	spent, err := m.logger.Spent(playerID, since)
+	if err != nil {
+		return 0, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("get spent: ")
+	}
+	var holds []entity.Hold
+	err = m.holds.Find(bson.M{"playerId": playerID, "expires": bson.M{"$gt": time.Now()}}).All(&holds)
+	if err != nil {
+		return 0, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("get spent: ")
+	}
+	for _, h := range holds {
+		spent += h.Points
+	}
+	return spent, nil
+}
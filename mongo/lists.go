@@ -0,0 +1,222 @@
+package mongo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ListAccounts returns players, registered or with balance, which match filter, sorted by filter sort field. Players
+// without account have empty name and status and zero created time.
+func (m *Mongo) ListAccounts(filter entity.PlayerFilter) ([]entity.Account, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if r := timeRange(filter.CreatedFrom, filter.CreatedTo); r != nil {
+		query["created"] = r
+	}
+	q, err := page(m.accounts, query, filter.Sort, filter.Desc, filter.After, filter.Limit)
+	if err != nil {
+		return nil, errors.Transform(err).SetPrefix("list accounts: ")
+	}
+	accounts := []entity.Account{}
+	err = q.All(&accounts)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("list accounts: ")
+	}
+	for i := range accounts {
+		accounts[i].Created = accounts[i].Created.UTC()
+	}
+	unregistered, err := m.unregistered(filter)
+	if err != nil {
+		return nil, errors.Transform(err).SetPrefix("list accounts: ")
+	}
+	if len(unregistered) == 0 {
+		return accounts, nil
+	}
+	accounts = append(accounts, unregistered...)
+	sort.Slice(accounts, func(i, j int) bool {
+		if filter.Desc {
+			return accountLess(accounts[j], accounts[i], filter.Sort)
+		}
+		return accountLess(accounts[i], accounts[j], filter.Sort)
+	})
+	if len(accounts) > filter.Limit {
+		accounts = accounts[:filter.Limit]
+	}
+	return accounts, nil
+}
+
+// accountLess returns whether account a is before account b in ascending order of sort field
+func accountLess(a, b entity.Account, field string) bool {
+	switch {
+	case field == "name" && a.Name != b.Name:
+		return a.Name < b.Name
+	case field == "created" && !a.Created.Equal(b.Created):
+		return a.Created.Before(b.Created)
+	}
+	return a.ID < b.ID
+}
+
+// unregistered returns players, who have balance, but no account, after cursor of filter sorted by id. They match filter
+// only without status and created from, their sort value is the same, so they are selected by id after cursor.
+func (m *Mongo) unregistered(filter entity.PlayerFilter) ([]entity.Account, error) {
+	if filter.Status != "" || !filter.CreatedFrom.IsZero() {
+		return nil, nil
+	}
+	op, sign := "$gt", ""
+	if filter.Desc {
+		op, sign = "$lt", "-"
+	}
+	query := bson.M{}
+	if after := filter.After; after != nil {
+		var cmp int
+		switch filter.Sort {
+		case "name":
+			cmp = strings.Compare("", after.Value)
+		case "created":
+			t, err := time.Parse(time.RFC3339Nano, after.Value)
+			if err != nil {
+				return nil, errors.Error{Code: errors.InvalidFilterError, Message: "invalid cursor value " + after.Value}
+			}
+			cmp = time.Time{}.Compare(t)
+		}
+		switch {
+		case cmp == 0:
+			query["_id"] = bson.M{op: after.ID}
+		case (cmp > 0) == filter.Desc:
+			// players without account are before cursor
+			return nil, nil
+		}
+	}
+	accounts := []entity.Account{}
+	for len(accounts) < filter.Limit {
+		var players []struct {
+			ID string `bson:"_id"`
+		}
+		err := m.players.Find(query).Sort(sign + "_id").Select(bson.M{"_id": 1}).Limit(filter.Limit).All(&players)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}
+		}
+		if len(players) == 0 {
+			break
+		}
+		ids := make([]string, len(players))
+		for i, p := range players {
+			ids[i] = p.ID
+		}
+		var registered []struct {
+			ID string `bson:"_id"`
+		}
+		err = m.accounts.Find(bson.M{"_id": bson.M{"$in": ids}}).Select(bson.M{"_id": 1}).All(&registered)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}
+		}
+		isRegistered := make(map[string]bool, len(registered))
+		for _, r := range registered {
+			isRegistered[r.ID] = true
+		}
+		for _, id := range ids {
+			if !isRegistered[id] {
+				accounts = append(accounts, entity.Account{ID: id})
+			}
+		}
+		if len(players) < filter.Limit {
+			break
+		}
+		query["_id"] = bson.M{op: ids[len(ids)-1]}
+	}
+	return accounts, nil
+}
+
+// ListTournaments returns tournaments, which match filter, sorted by filter sort field
+func (m *Mongo) ListTournaments(filter entity.TourFilter) ([]entity.Tournament, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["isOpen"] = filter.Status == "open"
+	}
+	deposit := bson.M{}
+	if filter.MinDeposit > 0 {
+		deposit["$gte"] = filter.MinDeposit
+	}
+	if filter.MaxDeposit > 0 {
+		deposit["$lte"] = filter.MaxDeposit
+	}
+	if len(deposit) > 0 {
+		query["deposit"] = deposit
+	}
+	if r := timeRange(filter.CreatedFrom, filter.CreatedTo); r != nil {
+		query["created"] = r
+	}
+	if filter.ParticipantID != "" {
+		query["participants"] = filter.ParticipantID
+	}
+	q, err := page(m.tournaments, query, filter.Sort, filter.Desc, filter.After, filter.Limit)
+	if err != nil {
+		return nil, errors.Transform(err).SetPrefix("list tournaments: ")
+	}
+	tours := []entity.Tournament{}
+	err = q.All(&tours)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("list tournaments: ")
+	}
+	for i := range tours {
+		tours[i].Created = tours[i].Created.UTC()
+	}
+	return tours, nil
+}
+
+func timeRange(from, to time.Time) bson.M {
+	r := bson.M{}
+	if !from.IsZero() {
+		r["$gte"] = from
+	}
+	if !to.IsZero() {
+		r["$lt"] = to
+	}
+	if len(r) == 0 {
+		return nil
+	}
+	return r
+}
+
+// page returns query of keyset pagination, documents after cursor are selected
+func page(c *mgo.Collection, query bson.M, field string, desc bool, after *entity.Cursor, limit int) (*mgo.Query, error) {
+	op, sign := "$gt", ""
+	if desc {
+		op, sign = "$lt", "-"
+	}
+	if field == "id" {
+		field = "_id"
+	}
+	if after != nil {
+		var value interface{} = after.Value
+		switch field {
+		case "created":
+			t, err := time.Parse(time.RFC3339Nano, after.Value)
+			if err != nil {
+				return nil, errors.Error{Code: errors.InvalidFilterError, Message: "invalid cursor value " + after.Value}
+			}
+			value = t
+		case "deposit":
+			d, err := strconv.Atoi(after.Value)
+			if err != nil {
+				return nil, errors.Error{Code: errors.InvalidFilterError, Message: "invalid cursor value " + after.Value}
+			}
+			value = d
+		}
+		if field == "_id" {
+			query["_id"] = bson.M{op: after.ID}
+		} else {
+			query["$or"] = []bson.M{{field: bson.M{op: value}}, {field: value, "_id": bson.M{op: after.ID}}}
+		}
+	}
+	return c.Find(query).Sort(sign+field, sign+"_id").Limit(limit), nil
+}
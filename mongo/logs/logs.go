@@ -1,22 +1,32 @@
 package logger
 
 import (
+	"time"
+
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
 // Data is a data that is stored in log
 type Data struct {
-	ID     string `bson:"id"`
-	Op     string `bson:"operation"`
-	Points int    `bson:"points"`
+	ID           string    `bson:"id"`
+	Op           string    `bson:"operation"`
+	Points       int       `bson:"points"`
+	Currency     string    `bson:"currency,omitempty"`
+	Counterparty string    `bson:"counterparty,omitempty"`
+	Time         time.Time `bson:"time,omitempty"`
 }
 
 // Block of available operations
 const (
-	Take = "take"
-	Fund = "fund"
-	Won  = "won"
+	Take     = "take"
+	Fund     = "fund"
+	Won      = "won"
+	Transfer = "transfer"
+	Capture  = "capture"
+	Expire   = "expire"
+	Refund   = "refund"
+	Payout   = "payout"
 )
 
 // Logger is collection that logs all operations with players
@@ -24,14 +34,41 @@ type Logger struct {
 	Logger *mgo.Collection
 }
 
-// Log logs operation
-func (l *Logger) Log(id, op string, points int) error {
-	return l.Logger.Insert(Data{ID: id, Op: op, Points: points})
+// Log logs operation in currency, empty currency is default one
+func (l *Logger) Log(id, currency, op string, points int) error {
+	return l.Logger.Insert(Data{ID: id, Op: op, Points: points, Currency: currency, Time: time.Now().UTC()})
+}
+
+// LogTransfer logs both sides of points transfer between players
+func (l *Logger) LogTransfer(from, to, currency string, points int) error {
+	now := time.Now().UTC()
+	return l.Logger.Insert(Data{ID: from, Op: Transfer, Points: -points, Currency: currency, Counterparty: to, Time: now},
+		Data{ID: to, Op: Transfer, Points: points, Currency: currency, Counterparty: from, Time: now})
 }
 
-// GetLogs returns all operations, that have been done with player
-func (l *Logger) GetLogs(id string) ([]Data, error) {
+// GetLogs returns all operations in currency, that have been done with player
+func (l *Logger) GetLogs(id, currency string) ([]Data, error) {
 	var d []Data
-	err := l.Logger.Find(bson.M{"id": id}).All(d)
+	query := bson.M{"id": id, "currency": currency}
+	if currency == "" {
+		// null matches operations, which were logged without currency
+		query["currency"] = nil
+	}
+	err := l.Logger.Find(query).All(d)
 	return d, err
 }
+
+// Spent returns points, which player has taken or paid as deposits in all currencies since the time
+func (l *Logger) Spent(id string, since time.Time) (int, error) {
+	var res struct {
+		Spent int `bson:"spent"`
+	}
+	err := l.Logger.Pipe([]bson.M{
+		{"$match": bson.M{"id": id, "operation": bson.M{"$in": []string{Take, Capture}}, "time": bson.M{"$gte": since}}},
+		{"$group": bson.M{"_id": nil, "spent": bson.M{"$sum": "$points"}}},
+	}).One(&res)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+	return -res.Spent, err
+}
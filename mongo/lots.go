@@ -0,0 +1,113 @@
+package mongo
+
+import (
+	"log"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/mongo/logs"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// FundLot adds lot points to player balance, lot points expire with lot
+func (m *Mongo) FundLot(lot entity.Lot) (entity.Player, error) {
+	_, err := m.players.UpsertId(lot.PlayerID, bson.M{"$inc": bson.M{pointsKey(lot.Currency): lot.Points}})
+	if err != nil {
+		return entity.Player{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("fund lot: ")
+	}
+	err = m.logBalance(lot.PlayerID, lot.Currency, logger.Fund, lot.Points)
+	if err != nil {
+		return entity.Player{}, err
+	}
+	m.addLot(lot.PlayerID, lot.Currency, lot.Points, lot.Expires)
+	return m.GetPlayer(lot.PlayerID, lot.Currency)
+}
+
+// GetExpiringLots returns player lots in currency, which will expire, ordered by expiry time
+func (m *Mongo) GetExpiringLots(playerID, currency string) ([]entity.Lot, error) {
+	var lots []entity.Lot
+	err := m.lots.Find(bson.M{"playerId": playerID, "currency": currency, "expires": bson.M{"$gt": time.Time{}}}).Sort("expires", "_id").All(&lots)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("get expiring lots: ")
+	}
+	return lots, nil
+}
+
+// ExpireLots takes points of expired lots from players and returns expired lots
+func (m *Mongo) ExpireLots() ([]entity.Lot, error) {
+	var lots []entity.Lot
+	err := m.lots.Find(bson.M{"expires": bson.M{"$gt": time.Time{}, "$lte": time.Now()}}).All(&lots)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("expire lots: ")
+	}
+	var expired []entity.Lot
+	for _, l := range lots {
+		// lot could be spent after it has been found
+		err = m.lots.Remove(bson.M{"_id": l.ID, "points": l.Points})
+		if err != nil {
+			continue
+		}
+		err = m.players.UpdateId(l.PlayerID, bson.M{"$inc": bson.M{pointsKey(l.Currency): -l.Points}})
+		if err != nil {
+			return expired, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("expire lots: ")
+		}
+		err = m.logBalance(l.PlayerID, l.Currency, logger.Expire, -l.Points)
+		if err != nil {
+			return expired, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("expire lots: ")
+		}
+		expired = append(expired, l)
+	}
+	return expired, nil
+}
+
+// addLot adds lot with points to player, lot with zero expires never expires.
+// Points are already added to balance, so failed lot is only logged, and its points are spent first.
+func (m *Mongo) addLot(id, currency string, points int, expires time.Time) {
+	if points == 0 {
+		return
+	}
+	lot := entity.Lot{ID: bson.NewObjectId().Hex(), PlayerID: id, Currency: currency, Points: points, Created: time.Now().UTC(), Expires: expires}
+	err := m.lots.Insert(lot)
+	if err != nil {
+		log.Println(errors.Error{Code: errors.UnexpectedError, Message: "add lot: cannot add lot, id " + id, Info: err.Error()})
+	}
+}
+
+// consumeLots spends points from the oldest player lots, after points have been taken from balance.
+// Points of balances, which were funded before lots were added, are not in any lot, they are spent first.
+func (m *Mongo) consumeLots(id, currency string, points int) {
+	p, err := m.GetPlayer(id, currency)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	var lots []entity.Lot
+	err = m.lots.Find(bson.M{"playerId": id, "currency": currency}).Sort("created", "_id").All(&lots)
+	if err != nil {
+		log.Println(errors.Error{Code: errors.UnexpectedError, Message: "consume lots: cannot find lots, id " + id, Info: err.Error()})
+		return
+	}
+	untracked := p.Points + points
+	for _, l := range lots {
+		untracked -= l.Points
+	}
+	if untracked > 0 {
+		points -= untracked
+	}
+	for _, l := range lots {
+		if points <= 0 {
+			return
+		}
+		if l.Points <= points {
+			err = m.lots.RemoveId(l.ID)
+		} else {
+			err = m.lots.UpdateId(l.ID, bson.M{"$inc": bson.M{"points": -points}})
+		}
+		if err != nil {
+			log.Println(errors.Error{Code: errors.UnexpectedError, Message: "consume lots: cannot spend lot, id " + id, Info: err.Error()})
+			return
+		}
+		points -= l.Points
+	}
+}
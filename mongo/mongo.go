@@ -1,17 +1,34 @@
 package mongo
 
 import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
 	"github.com/dmitriyomelyusik/Tournament/mongo/logs"
 	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // Mongo is an implementation of needed mongodb
 type Mongo struct {
-	s           *mgo.Session
-	db          *mgo.Database
-	players     *mgo.Collection
-	tournaments *mgo.Collection
-	logger      *logger.Logger
+	s              *mgo.Session
+	db             *mgo.Database
+	players        *mgo.Collection
+	tournaments    *mgo.Collection
+	teams          *mgo.Collection
+	holds          *mgo.Collection
+	lots           *mgo.Collection
+	promos         *mgo.Collection
+	redemptions    *mgo.Collection
+	limits         *mgo.Collection
+	accounts       *mgo.Collection
+	participations *mgo.Collection
+	webhooks       *mgo.Collection
+	deliveries     *mgo.Collection
+	keys           *mgo.Collection
+	buckets        *mgo.Collection
+	logger         *logger.Logger
 }
 
 // NewDB returns mongo database with configuration conf
@@ -23,8 +40,90 @@ func NewDB(conf string) (*Mongo, error) {
 	db := s.DB("mongo")
 	players := db.C("players")
 	tournaments := db.C("tournaments")
+	teams := db.C("teams")
+	holds := db.C("holds")
+	// mongo removes expired holds by itself, but not immediately, so queries filter them too
+	err = holds.EnsureIndex(mgo.Index{Key: []string{"expires"}, ExpireAfter: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	lots := db.C("lots")
+	promos := db.C("promos")
+	redemptions := db.C("redemptions")
+	limits := db.C("limits")
+	accounts := db.C("accounts")
+	err = registerPlayers(db, players, accounts)
+	if err != nil {
+		return nil, err
+	}
+	participations := db.C("participations")
+	err = fillParticipations(db, tournaments, teams, participations)
+	if err != nil {
+		return nil, err
+	}
+	err = participations.EnsureIndex(mgo.Index{Key: []string{"tournamentId", "playerId"}, Unique: true})
+	if err != nil {
+		return nil, err
+	}
+	err = participations.EnsureIndex(mgo.Index{Key: []string{"playerId", "-joined"}})
+	if err != nil {
+		return nil, err
+	}
+	webhooks := db.C("webhooks")
+	deliveries := db.C("deliveries")
+	err = deliveries.EnsureIndex(mgo.Index{Key: []string{"dead", "nextAttempt"}})
+	if err != nil {
+		return nil, err
+	}
+	keys := db.C("keys")
+	err = keys.EnsureIndex(mgo.Index{Key: []string{"hash"}, Unique: true})
+	if err != nil {
+		return nil, err
+	}
+	buckets := db.C("buckets")
+	err = buckets.EnsureIndex(mgo.Index{Key: []string{"updated"}})
+	if err != nil {
+		return nil, err
+	}
 	log := &logger.Logger{Logger: db.C("logger")}
-	return &Mongo{s, db, players, tournaments, log}, nil
+	return &Mongo{s, db, players, tournaments, teams, holds, lots, promos, redemptions, limits, accounts, participations, webhooks, deliveries, keys, buckets, log}, nil
+}
+
+// registerPlayers registers players, who were funded before accounts were introduced, when accounts collection is created
+func registerPlayers(db *mgo.Database, players, accounts *mgo.Collection) error {
+	exists, err := hasCollection(db, accounts.Name)
+	if err != nil || exists {
+		return err
+	}
+	var ids []struct {
+		ID string `bson:"_id"`
+	}
+	err = players.Find(nil).Select(bson.M{"_id": 1}).All(&ids)
+	if err != nil {
+		return err
+	}
+	created := time.Now().UTC().Truncate(time.Second)
+	for _, p := range ids {
+		err = accounts.Insert(entity.Account{ID: p.ID, Name: p.ID, Created: created, Status: entity.StatusActive})
+		if err != nil && !mgo.IsDup(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasCollection returns whether database has collection with name
+func hasCollection(db *mgo.Database, name string) (bool, error) {
+	names, err := db.CollectionNames()
+	if err != nil {
+		return false, err
+	}
+	for _, n := range names {
+		if n == name {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // Close closes database connection
@@ -38,7 +137,31 @@ func (m *Mongo) Ping() error {
 	return m.s.Ping()
 }
 
-// UpdateTourAndPlayer updates tournament participants and player balance
+// UpdateTourAndPlayer adds next player entry into tournament and takes deposit from player balance
 func (m *Mongo) UpdateTourAndPlayer(tourID string, playerID string) error {
-	return nil
+	var t entity.Tournament
+	err := m.tournaments.FindId(tourID).One(&t)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "update tournament and player: tournament is not found, id " + tourID}
+	}
+	var n int
+	for _, e := range t.Entries {
+		if e.PlayerID == playerID {
+			n++
+		}
+	}
+	if n >= maxEntries(t) {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "update tournament and player: player has used all entries, playerID: " + playerID}
+	}
+	currency := tourCurrency(t)
+	err = m.getPoints(playerID, currency, -t.Deposit)
+	if err != nil {
+		return err
+	}
+	entry := entity.Entry{PlayerID: playerID, Number: n + 1}
+	err = m.tournaments.UpdateId(tourID, bson.M{"$push": bson.M{"entries": entry}, "$addToSet": bson.M{"participants": playerID}, "$inc": bson.M{"prize": t.Deposit}})
+	if err != nil {
+		return m.rollback(playerID, currency, t.Deposit)
+	}
+	return m.participate(t, playerID, t.Deposit)
 }
@@ -2,84 +2,195 @@ package mongo
 
 import (
 	"log"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
 	"github.com/dmitriyomelyusik/Tournament/mongo/logs"
+	mgo "gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
-// CreatePlayer creates new player with id and points
-func (m *Mongo) CreatePlayer(id string, points int) (entity.Player, error) {
-	player := entity.Player{ID: id, Points: points}
-	err := m.players.Insert(player)
+// player is a document of players collection. Points in default currency are stored in points field,
+// so players created before currencies were added keep their balance, other currencies are stored in balances.
+type player struct {
+	ID       string         `bson:"_id"`
+	Points   *int           `bson:"points,omitempty"`
+	Balances map[string]int `bson:"balances,omitempty"`
+}
+
+// balance returns player points in currency and false, if player has no balance in currency
+func (p player) balance(currency string) (int, bool) {
+	if currency == entity.DefaultCurrency {
+		if p.Points == nil {
+			return 0, false
+		}
+		return *p.Points, true
+	}
+	points, ok := p.Balances[currency]
+	return points, ok
+}
+
+// pointsKey returns player document field, which stores points in currency
+func pointsKey(currency string) string {
+	if currency == entity.DefaultCurrency {
+		return "points"
+	}
+	return "balances." + currency
+}
+
+// logCurrency returns currency, that is written into logs, operations in default currency are logged without it
+func logCurrency(currency string) string {
+	if currency == entity.DefaultCurrency {
+		return ""
+	}
+	return currency
+}
+
+// CreatePlayer creates new player balance with id and points in currency
+func (m *Mongo) CreatePlayer(id, currency string, points int) (entity.Player, error) {
+	key := pointsKey(currency)
+	// upsert fails with duplicated key, if player already has balance in currency
+	_, err := m.players.Upsert(bson.M{"_id": id, key: bson.M{"$exists": false}}, bson.M{"$set": bson.M{key: points}})
 	if err != nil {
-		return entity.Player{}, err
+		return entity.Player{}, errors.Error{Code: errors.DuplicatedIDError, Message: "create player: using duplicated id to create player, id " + id}
 	}
-	err = m.logger.Log(id, logger.Fund, points)
+	err = m.logBalance(id, currency, logger.Fund, points)
 	if err != nil {
 		return entity.Player{}, err
 	}
-	return player, nil
+	m.addLot(id, currency, points, time.Time{})
+	return entity.Player{ID: id, Points: points, Currency: currency}, nil
 }
 
-// GetPlayer returns player by its id
-func (m *Mongo) GetPlayer(id string) (entity.Player, error) {
-	var p entity.Player
+// GetPlayer returns player points in currency by player id
+func (m *Mongo) GetPlayer(id, currency string) (entity.Player, error) {
+	var p player
 	err := m.players.FindId(id).One(&p)
-	return p, err
+	if err != nil {
+		return entity.Player{}, errors.Error{Code: errors.NotFoundError, Message: "get player: cannot find player, id " + id}
+	}
+	points, ok := p.balance(currency)
+	if !ok {
+		return entity.Player{}, errors.Error{Code: errors.NotFoundError, Message: "get player: cannot find player, id " + id}
+	}
+	return entity.Player{ID: id, Points: points, Currency: currency}, nil
+}
+
+// GetBalances returns player points in every currency, player has
+func (m *Mongo) GetBalances(id string) ([]entity.Player, error) {
+	var p player
+	err := m.players.FindId(id).One(&p)
+	if err != nil {
+		return nil, errors.Error{Code: errors.NotFoundError, Message: "get balances: cannot find player, id " + id}
+	}
+	var balances []entity.Player
+	if p.Points != nil {
+		balances = append(balances, entity.Player{ID: id, Points: *p.Points, Currency: entity.DefaultCurrency})
+	}
+	var currencies []string
+	for currency := range p.Balances {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		balances = append(balances, entity.Player{ID: id, Points: p.Balances[currency], Currency: currency})
+	}
+	return balances, nil
 }
 
-// UpdatePlayer updates player points
-func (m *Mongo) UpdatePlayer(id string, points int) error {
+// UpdatePlayer updates player points in currency
+func (m *Mongo) UpdatePlayer(id, currency string, points int) error {
 	if points < 0 {
-		return m.getPoints(id, points)
+		return m.getPoints(id, currency, points)
 	}
-	return m.setPoints(id, points)
+	return m.setPoints(id, currency, points)
 }
 
-func (m *Mongo) setPoints(id string, points int) error {
-	err := m.players.UpdateId(id, bson.M{"$inc": bson.M{"points": points}})
+func (m *Mongo) setPoints(id, currency string, points int) error {
+	err := m.players.UpdateId(id, bson.M{"$inc": bson.M{pointsKey(currency): points}})
 	if err != nil {
 		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}
 	}
-	return m.logger.Log(id, logger.Fund, points)
+	m.addLot(id, currency, points, time.Time{})
+	return m.logBalance(id, currency, logger.Fund, points)
 }
 
-func (m *Mongo) getPoints(id string, points int) error {
-	s0, err := logSum(m.logger, id)
+func (m *Mongo) getPoints(id, currency string, points int) error {
+	s0, err := logSum(m.logger, id, currency)
 	if err != nil {
 		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}
 	}
 	if s0 < 0 {
 		return errors.Error{Code: errors.NegativePointsNumberError, Message: "update player: negative balance, player id " + id}
 	}
-	if s0 < -points {
+	held, err := m.heldPoints(id, currency)
+	if err != nil {
+		return err
+	}
+	if s0-held < -points {
 		return errors.Error{Code: errors.NegativePointsNumberError, Message: "update player: cannot update points numbers, dif " + strconv.Itoa(points)}
 	}
-	err = m.players.UpdateId(id, bson.M{"$inc": bson.M{"points": points}})
+	err = m.players.UpdateId(id, bson.M{"$inc": bson.M{pointsKey(currency): points}})
 	if err != nil {
 		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("update player: ")
 	}
-	err = m.logger.Log(id, logger.Take, points)
+	err = m.logger.Log(id, logCurrency(currency), logger.Take, points)
 	if err != nil {
 		log.Println(err)
-		return m.rollback(id, -points)
+		return m.rollback(id, currency, -points)
 	}
-	s1, err := logSum(m.logger, id)
+	s1, err := logSum(m.logger, id, currency)
 	if err != nil {
 		log.Println(err)
-		return m.rollback(id, -points)
+		return m.rollback(id, currency, -points)
 	}
 	if s1 < 0 {
-		return m.rollback(id, -points)
+		return m.rollback(id, currency, -points)
+	}
+	m.consumeLots(id, currency, -points)
+	m.outbox(entity.Event{Type: entity.EventBalanceChanged, PlayerID: id, Currency: currency, Points: points})
+	return nil
+}
+
+// TransferPoints sends points in currency from one player to another, sender is charged only if they have enough points
+func (m *Mongo) TransferPoints(from, to, currency string, points int) error {
+	held, err := m.heldPoints(from, currency)
+	if err != nil {
+		return err
+	}
+	key := pointsKey(currency)
+	err = m.players.Update(bson.M{"_id": from, key: bson.M{"$gte": points + held}}, bson.M{"$inc": bson.M{key: -points}})
+	if err == mgo.ErrNotFound {
+		_, err = m.GetPlayer(from, currency)
+		if err != nil {
+			return errors.Error{Code: errors.NotFoundError, Message: "transfer points: cannot find player, id " + from}
+		}
+		return errors.Error{Code: errors.NegativePointsNumberError, Message: "transfer points: cannot update points numbers, dif " + strconv.Itoa(-points)}
+	}
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("transfer points: ")
+	}
+	err = m.players.Update(bson.M{"_id": to, key: bson.M{"$exists": true}}, bson.M{"$inc": bson.M{key: points}})
+	if err != nil {
+		log.Println(err)
+		return m.rollback(from, currency, points)
+	}
+	m.consumeLots(from, currency, points)
+	m.addLot(to, currency, points, time.Time{})
+	err = m.logger.LogTransfer(from, to, logCurrency(currency), points)
+	if err != nil {
+		return err
 	}
+	m.outbox(entity.Event{Type: entity.EventBalanceChanged, PlayerID: from, Currency: currency, Points: -points})
+	m.outbox(entity.Event{Type: entity.EventBalanceChanged, PlayerID: to, Currency: currency, Points: points})
 	return nil
 }
 
-func logSum(log *logger.Logger, id string) (int, error) {
-	data, err := log.GetLogs(id)
+func logSum(log *logger.Logger, id, currency string) (int, error) {
+	data, err := log.GetLogs(id, logCurrency(currency))
 	if err != nil {
 		return 0, err
 	}
@@ -90,8 +201,8 @@ func logSum(log *logger.Logger, id string) (int, error) {
 	return sum, nil
 }
 
-func (m *Mongo) rollback(id string, points int) error {
-	err := m.players.UpdateId(id, bson.M{"$inc": bson.M{"points": points}})
+func (m *Mongo) rollback(id, currency string, points int) error {
+	err := m.players.UpdateId(id, bson.M{"$inc": bson.M{pointsKey(currency): points}})
 	if err != nil {
 		return errors.Error{Code: errors.CriticalError, Message: "rollback: cannot rollback, next operations can be dangerous", Info: err.Error()}
 	}
@@ -0,0 +1,159 @@
+package mongo
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CreatePromo creates promotion code
+func (m *Mongo) CreatePromo(promo entity.Promo) error {
+	err := m.promos.Insert(promo)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create promo: using duplicated code to create promo, code " + promo.Code}
+	}
+	return nil
+}
+
+// RedeemPromo grants promo reward to player and writes redemption. Redemption id is unique for every player use,
+// so concurrent redemptions by the same player cannot grant reward twice. Redemption is removed and its use is given
+// back, if reward cannot be granted.
+func (m *Mongo) RedeemPromo(code, playerID string, now time.Time) (entity.Redemption, error) {
+	var promo entity.Promo
+	err := m.promos.FindId(code).One(&promo)
+	if err != nil {
+		return entity.Redemption{}, errors.Error{Code: errors.NotFoundError, Message: "redeem promo: promo does not exist, code " + code}
+	}
+	var reds []entity.Redemption
+	err = m.redemptions.Find(bson.M{"code": code, "playerId": playerID}).Sort("use").All(&reds)
+	if err != nil {
+		return entity.Redemption{}, errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("redeem promo: ")
+	}
+	// repeated redemption returns the last one, so retried request does not grant reward again
+	if len(reds) >= promo.MaxPerPlayer {
+		return reds[len(reds)-1], nil
+	}
+	err = m.checkPromo(promo, playerID, now)
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+	// ticket is checked before promo is used, so promo is not used up by players, who cannot enter tournament
+	if promo.TournamentID != "" {
+		_, _, err = m.checkTicket(promo.TournamentID, playerID)
+		if err != nil {
+			return entity.Redemption{}, err
+		}
+	}
+	red := entity.Redemption{Code: code, PlayerID: playerID, Use: len(reds) + 1, Points: promo.Points, Currency: promo.Currency, TournamentID: promo.TournamentID, Redeemed: now}
+	err = m.redemptions.Insert(bson.M{"_id": redemptionID(red), "code": code, "playerId": playerID, "use": red.Use,
+		"points": red.Points, "currency": red.Currency, "tournamentId": red.TournamentID, "redeemed": now})
+	if err != nil {
+		return entity.Redemption{}, errors.Error{Code: errors.DuplicatedIDError, Message: "redeem promo: promo is being redeemed by player, id " + playerID}
+	}
+	// uses are increased only if nobody has redeemed promo since it was read
+	err = m.promos.Update(bson.M{"_id": code, "uses": promo.Uses}, bson.M{"$inc": bson.M{"uses": 1}})
+	if err != nil {
+		m.redemptions.RemoveId(redemptionID(red))
+		return entity.Redemption{}, errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo is being redeemed, try again, code " + code}
+	}
+	if promo.TournamentID != "" {
+		err = m.grantTicket(promo.TournamentID, playerID)
+		if err != nil {
+			m.unredeem(red)
+			return entity.Redemption{}, err
+		}
+	}
+	if promo.Points > 0 {
+		err = m.grantPoints(playerID, promo.Currency, promo.Points)
+		if err != nil {
+			m.unredeem(red)
+			return entity.Redemption{}, err.(errors.Error).SetPrefix("redeem promo: ")
+		}
+	}
+	return red, nil
+}
+
+// redemptionID returns id of redemption, which is unique for every player use of promo
+func redemptionID(red entity.Redemption) string {
+	return red.Code + "/" + red.PlayerID + "/" + strconv.Itoa(red.Use)
+}
+
+// unredeem removes redemption and gives its use back to promo, when its reward cannot be granted
+func (m *Mongo) unredeem(red entity.Redemption) {
+	m.redemptions.RemoveId(redemptionID(red))
+	m.promos.UpdateId(red.Code, bson.M{"$inc": bson.M{"uses": -1}})
+}
+
+// checkTicket returns ticket tournament and number of player entries in it, if player can enter it by ticket
+func (m *Mongo) checkTicket(tourID, playerID string) (entity.Tournament, int, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(tourID).One(&t)
+	if err != nil {
+		return entity.Tournament{}, 0, errors.Error{Code: errors.NotFoundError, Message: "redeem promo: ticket tournament does not exist, id " + tourID}
+	}
+	if !t.IsOpen {
+		return entity.Tournament{}, 0, errors.Error{Code: errors.ClosedTournamentError, Message: "redeem promo: ticket tournament is closed, id " + tourID}
+	}
+	var n int
+	for _, e := range t.Entries {
+		if e.PlayerID == playerID {
+			n++
+		}
+	}
+	if n >= maxEntries(t) {
+		return entity.Tournament{}, 0, errors.Error{Code: errors.DuplicatedIDError, Message: "redeem promo: player has used all entries, playerID: " + playerID}
+	}
+	return t, n, nil
+}
+
+// grantTicket adds next player entry into tournament without paying deposit, deposit is added to prize like for paid entry
+func (m *Mongo) grantTicket(tourID, playerID string) error {
+	t, n, err := m.checkTicket(tourID, playerID)
+	if err != nil {
+		return err
+	}
+	entry := entity.Entry{PlayerID: playerID, Number: n + 1, Free: true}
+	err = m.tournaments.UpdateId(tourID, bson.M{"$push": bson.M{"entries": entry}, "$addToSet": bson.M{"participants": playerID}, "$inc": bson.M{"prize": t.Deposit}})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("redeem promo: ")
+	}
+	return m.participate(t, playerID, 0)
+}
+
+// grantPoints funds player the same way as fund does, player balance in currency is created, if player does not have it
+func (m *Mongo) grantPoints(playerID, currency string, points int) error {
+	_, err := m.GetPlayer(playerID, currency)
+	if err != nil {
+		_, err = m.CreatePlayer(playerID, currency, points)
+	} else {
+		err = m.UpdatePlayer(playerID, currency, points)
+	}
+	if err != nil {
+		return errors.Transform(err)
+	}
+	return nil
+}
+
+// checkPromo returns error, if player cannot redeem promo now
+func (m *Mongo) checkPromo(promo entity.Promo, playerID string, now time.Time) error {
+	if now.Before(promo.ValidFrom) || !promo.ValidTo.IsZero() && !now.Before(promo.ValidTo) {
+		return errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo is not valid now, code " + promo.Code}
+	}
+	if promo.MaxUses > 0 && promo.Uses >= promo.MaxUses {
+		return errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo has been used " + strconv.Itoa(promo.Uses) + " times, code " + promo.Code}
+	}
+	if !promo.NewPlayersOnly {
+		return nil
+	}
+	n, err := m.players.FindId(playerID).Count()
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("redeem promo: ")
+	}
+	if n > 0 {
+		return errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo is for new players only, id " + playerID}
+	}
+	return nil
+}
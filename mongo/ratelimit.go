@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tokenAttempts is number of attempts to take token, while bucket is changed by other instances
+const tokenAttempts = 10
+
+// bucket is token bucket document
+type bucket struct {
+	Key     string    `bson:"_id"`
+	Tokens  float64   `bson:"tokens"`
+	Updated time.Time `bson:"updated"`
+}
+
+// TakeToken takes token from bucket of key, which is filled by rule. Mongo has no transactions, so bucket is
+// updated only if it is not changed since it was read, otherwise token is taken again.
+func (m *Mongo) TakeToken(key string, rule ratelimit.Rule, now time.Time) (time.Duration, error) {
+	for i := 0; i < tokenAttempts; i++ {
+		var doc bucket
+		err := m.buckets.FindId(key).One(&doc)
+		if err == mgo.ErrNotFound {
+			b, wait := rule.Take(ratelimit.Bucket{}, now)
+			err = m.buckets.Insert(bucket{Key: key, Tokens: b.Tokens, Updated: b.Updated})
+			if mgo.IsDup(err) {
+				continue
+			}
+			if err != nil {
+				return 0, errors.Error{Code: errors.UnexpectedError, Message: "take token: " + err.Error()}
+			}
+			return wait, nil
+		}
+		if err != nil {
+			return 0, errors.Error{Code: errors.UnexpectedError, Message: "take token: " + err.Error()}
+		}
+		b, wait := rule.Take(ratelimit.Bucket{Tokens: doc.Tokens, Updated: doc.Updated}, now)
+		err = m.buckets.Update(bson.M{"_id": key, "tokens": doc.Tokens, "updated": doc.Updated}, bson.M{"$set": bson.M{"tokens": b.Tokens, "updated": b.Updated}})
+		if err == mgo.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return 0, errors.Error{Code: errors.UnexpectedError, Message: "take token: " + err.Error()}
+		}
+		return wait, nil
+	}
+	return 0, errors.Error{Code: errors.TransactionError, Message: "take token: bucket is changed by other instances, key " + key}
+}
+
+// PruneBuckets deletes buckets, which were not updated since before
+func (m *Mongo) PruneBuckets(before time.Time) error {
+	_, err := m.buckets.RemoveAll(bson.M{"updated": bson.M{"$lt": before}})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "prune buckets: " + err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/mongo/logs"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CreateTeam creates team with its members
+func (m *Mongo) CreateTeam(team entity.Team) error {
+	err := m.teams.Insert(team)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create team: using duplicated id to create team, id " + team.ID}
+	}
+	return nil
+}
+
+// GetTeam returns team with its members
+func (m *Mongo) GetTeam(id string) (entity.Team, error) {
+	var team entity.Team
+	err := m.teams.FindId(id).One(&team)
+	if err != nil {
+		return entity.Team{}, errors.Error{Code: errors.NotFoundError, Message: "get team: team is not found, id " + id}
+	}
+	return team, nil
+}
+
+// CreateTeamTournament creates tournament with id and deposit in currency, which only teams can join
+func (m *Mongo) CreateTeamTournament(id, currency string, deposit int) error {
+	err := m.tournaments.Insert(bson.M{"_id": id, "deposit": deposit, "currency": currency, "isOpen": true, "isTeam": true, "teams": []string{}, "participants": []string{}, "entries": []entity.Entry{}, "maxEntries": 1, "prize": 0, "winner": entity.Winners{}, "created": time.Now().UTC()})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("create team tournament: ")
+	}
+	return nil
+}
+
+// IsTeamTournament returns true, if only teams can join tournament
+func (m *Mongo) IsTeamTournament(id string) (bool, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(id).Select(bson.M{"isTeam": 1}).One(&t)
+	if err != nil {
+		return false, errors.Error{Code: errors.NotFoundError, Message: "is team tournament: tournament is not found, id " + id}
+	}
+	return t.IsTeam, nil
+}
+
+// GetTeams returns teams, which have joined tournament
+func (m *Mongo) GetTeams(tourID string) ([]string, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(tourID).Select(bson.M{"teams": 1}).One(&t)
+	if err != nil {
+		return nil, errors.Error{Code: errors.NotFoundError, Message: "get teams: tournament is not found, id " + tourID}
+	}
+	return t.Teams, nil
+}
+
+// UpdateTourAndTeam adds team into tournament and takes deposit from team members
+func (m *Mongo) UpdateTourAndTeam(tourID, teamID string, captainPays bool) error {
+	team, err := m.GetTeam(teamID)
+	if err != nil {
+		return err
+	}
+	var t entity.Tournament
+	err = m.tournaments.FindId(tourID).One(&t)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "update tournament and team: tournament is not found, id " + tourID}
+	}
+	payers := []string{team.Captain}
+	parts := []int{t.Deposit}
+	if !captainPays {
+		payers = payers[:0]
+		for _, member := range team.Members {
+			payers = append(payers, member.PlayerID)
+		}
+		parts = team.Split(t.Deposit)
+	}
+	currency := tourCurrency(t)
+	for i := range payers {
+		err = m.getPoints(payers[i], currency, -parts[i])
+		if err != nil {
+			for j := 0; j < i; j++ {
+				m.rollback(payers[j], currency, parts[j])
+			}
+			return err
+		}
+	}
+	err = m.tournaments.UpdateId(tourID, bson.M{"$push": bson.M{"teams": teamID}, "$inc": bson.M{"prize": t.Deposit}})
+	if err != nil {
+		for i := range payers {
+			m.rollback(payers[i], currency, parts[i])
+		}
+		return errors.Error{Code: errors.RollbackError, Message: "update tournament and team: cannot add team, operation aborted", Info: err.Error()}
+	}
+	paid := make(map[string]int, len(payers))
+	for i := range payers {
+		paid[payers[i]] = parts[i]
+	}
+	for _, member := range team.Members {
+		err = m.participate(t, member.PlayerID, paid[member.PlayerID])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetTeamWinner splits tournament prize between winning team members
+func (m *Mongo) SetTeamWinner(tourID, teamID string) error {
+	team, err := m.GetTeam(teamID)
+	if err != nil {
+		return err
+	}
+	var t entity.Tournament
+	err = m.tournaments.FindId(tourID).One(&t)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "set team winner: tournament is not found, id " + tourID}
+	}
+	currency := tourCurrency(t)
+	var winners []entity.Winner
+	for i, part := range team.Split(t.Prize) {
+		id := team.Members[i].PlayerID
+		// member may not have balance in currency, if captain paid deposit, it is created by prize
+		player, _ := m.GetPlayer(id, currency)
+		err = m.players.UpdateId(id, bson.M{"$inc": bson.M{pointsKey(currency): part}})
+		if err != nil {
+			return errors.Error{Code: errors.NotFoundError, Message: "set team winner: player is not found, id " + id}
+		}
+		m.addLot(id, currency, part, time.Time{})
+		err = m.logBalance(id, currency, logger.Won, part)
+		if err != nil {
+			return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("set team winner: ")
+		}
+		err = m.place(t, id, 1, part)
+		if err != nil {
+			return err
+		}
+		winners = append(winners, entity.Winner{ID: id, Points: player.Points, Prize: part, Team: teamID})
+	}
+	err = m.tournaments.UpdateId(tourID, bson.M{"$set": bson.M{"winners": winners}})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("set team winner: ")
+	}
+	return nil
+}
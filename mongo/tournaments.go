@@ -1,23 +1,51 @@
 package mongo
 
 import (
+	"time"
+
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/mongo/logs"
 	"gopkg.in/mgo.v2/bson"
 )
 
-// CreateTournament creates tournament with id and deposit
-func (m *Mongo) CreateTournament(id string, deposit int) error {
-	err := m.tournaments.Insert(bson.M{"_id": id, "deposit": deposit, "isOpen": true, "participants": []string{}, "prize": 0, "winner": entity.Winners{}})
+// CreateTournament creates tournament with id, deposit in currency and max entries number of every player
+func (m *Mongo) CreateTournament(id, currency string, deposit, maxEntries int) error {
+	err := m.tournaments.Insert(bson.M{"_id": id, "deposit": deposit, "currency": currency, "isOpen": true, "participants": []string{}, "entries": []entity.Entry{}, "maxEntries": maxEntries, "prize": 0, "winner": entity.Winners{}, "created": time.Now().UTC()})
 	if err != nil {
 		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("create tournament: ")
 	}
 	return nil
 }
 
+// CreateSatellite creates satellite tournament with id and deposit, which awards seats in target tournament.
+// Satellite deposit is paid in target tournament currency, so its prize can pay target deposits.
+func (m *Mongo) CreateSatellite(id string, deposit int, targetID string, seats int) error {
+	currency, err := m.GetCurrency(targetID)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "create satellite: cannot create tournament with not existing target, targetID: " + targetID}
+	}
+	sat := entity.Satellite{TargetID: targetID, Seats: seats}
+	err = m.tournaments.Insert(bson.M{"_id": id, "deposit": deposit, "currency": currency, "isOpen": true, "participants": []string{}, "entries": []entity.Entry{}, "maxEntries": 1, "prize": 0, "winner": entity.Winners{}, "satellite": sat, "created": time.Now().UTC()})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("create satellite: ")
+	}
+	return nil
+}
+
+// GetSatellite returns satellite settings of tournament, which are empty for regular tournament
+func (m *Mongo) GetSatellite(id string) (entity.Satellite, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(id).Select(bson.M{"satellite": 1}).One(&t)
+	if err != nil {
+		return entity.Satellite{}, errors.Error{Code: errors.NotFoundError, Message: "get satellite: tournament is not found, id " + id}
+	}
+	return t.Satellite, nil
+}
+
 // CloseTournament closes tournament in transaction
 func (m *Mongo) CloseTournament(id string) error {
-	err := m.tournaments.UpdateId(id, bson.M{"$set": bson.M{"isOpen": false}})
+	err := m.tournaments.UpdateId(id, bson.M{"$set": bson.M{"isOpen": false, "closed": time.Now().UTC()}})
 	if err != nil {
 		return errors.Error{Code: errors.NotFoundError, Message: "close tournament: tournament is not found, id " + id}
 	}
@@ -34,6 +62,52 @@ func (m *Mongo) GetParticipants(id string) ([]string, error) {
 	return participants, nil
 }
 
+// GetEntries returns every tournament entry
+func (m *Mongo) GetEntries(id string) ([]entity.Entry, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(id).Select(bson.M{"entries": 1}).One(&t)
+	if err != nil {
+		return nil, errors.Error{Code: errors.NotFoundError, Message: "get entries: tournament is not found, id " + id}
+	}
+	return t.Entries, nil
+}
+
+// GetMaxEntries returns how many times every player can join tournament
+func (m *Mongo) GetMaxEntries(id string) (int, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(id).Select(bson.M{"maxEntries": 1}).One(&t)
+	if err != nil {
+		return 0, errors.Error{Code: errors.NotFoundError, Message: "get max entries: tournament is not found, id " + id}
+	}
+	return maxEntries(t), nil
+}
+
+// maxEntries returns max entries number of tournament, tournaments created before re-entries allow only one entry
+func maxEntries(t entity.Tournament) int {
+	if t.MaxEntries == 0 {
+		return 1
+	}
+	return t.MaxEntries
+}
+
+// GetCurrency returns currency of tournament deposit and prize
+func (m *Mongo) GetCurrency(id string) (string, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(id).Select(bson.M{"currency": 1}).One(&t)
+	if err != nil {
+		return "", errors.Error{Code: errors.NotFoundError, Message: "get currency: tournament is not found, id " + id}
+	}
+	return tourCurrency(t), nil
+}
+
+// tourCurrency returns default currency for tournaments, which were created before currencies were added
+func tourCurrency(t entity.Tournament) string {
+	if t.Currency == "" {
+		return entity.DefaultCurrency
+	}
+	return t.Currency
+}
+
 // GetTournamentState returns true, if tournament opens for joining
 func (m *Mongo) GetTournamentState(id string) (bool, error) {
 	var state bool
@@ -54,12 +128,99 @@ func (m *Mongo) GetWinner(id string) (entity.Winners, error) {
 	return winner, nil
 }
 
+// GetTournament returns tournament with its entries and winners
+func (m *Mongo) GetTournament(id string) (entity.Tournament, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(id).One(&t)
+	if err != nil {
+		return entity.Tournament{}, errors.Error{Code: errors.NotFoundError, Message: "get tournament: tournament is not found, id " + id}
+	}
+	t.Created = t.Created.UTC()
+	if t.Closed != nil {
+		closed := t.Closed.UTC()
+		t.Closed = &closed
+	}
+	return t, nil
+}
+
 // SetTournamentWinner sets winner in one transaction
 func (m *Mongo) SetTournamentWinner(id string, winner entity.Winner) error {
 
 	return nil
 }
 
+// SetSatelliteWinners registers first seats players from ranking into target tournament.
+// Every seat is paid from satellite prize, leftover points go to the next placed player.
+func (m *Mongo) SetSatelliteWinners(id string, ranking []string, seats int) error {
+	var sat, target entity.Tournament
+	err := m.tournaments.FindId(id).One(&sat)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "set satellite winners: tournament is not found, id " + id}
+	}
+	err = m.tournaments.FindId(sat.Satellite.TargetID).One(&target)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "set satellite winners: target tournament is not found, id " + sat.Satellite.TargetID}
+	}
+	if seats > len(ranking) {
+		seats = len(ranking)
+	}
+	if seats > sat.Prize/target.Deposit {
+		seats = sat.Prize / target.Deposit
+	}
+	currency := tourCurrency(target)
+	var winners []entity.Winner
+	for i, playerID := range ranking[:seats] {
+		player, err := m.GetPlayer(playerID, currency)
+		if err != nil {
+			return errors.Error{Code: errors.NotFoundError, Message: "set satellite winners: player is not found, id " + playerID}
+		}
+		entry := entity.Entry{PlayerID: playerID, Number: 1, Free: true}
+		err = m.tournaments.UpdateId(target.ID, bson.M{"$push": bson.M{"entries": entry}, "$addToSet": bson.M{"participants": playerID}, "$inc": bson.M{"prize": target.Deposit}})
+		if err != nil {
+			return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("set satellite winners: ")
+		}
+		err = m.participate(target, playerID, 0)
+		if err != nil {
+			return err
+		}
+		err = m.place(sat, playerID, i+1, target.Deposit)
+		if err != nil {
+			return err
+		}
+		winners = append(winners, entity.Winner{ID: playerID, Points: player.Points, Prize: target.Deposit, Seat: target.ID})
+	}
+	leftover := sat.Prize - seats*target.Deposit
+	if leftover > 0 && len(ranking) > 0 {
+		next, placing := ranking[0], 1
+		if seats < len(ranking) {
+			next, placing = ranking[seats], seats+1
+		}
+		player, err := m.GetPlayer(next, currency)
+		if err != nil {
+			return errors.Error{Code: errors.NotFoundError, Message: "set satellite winners: player is not found, id " + next}
+		}
+		err = m.players.UpdateId(next, bson.M{"$inc": bson.M{pointsKey(currency): leftover}})
+		if err != nil {
+			return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("set satellite winners: ")
+		}
+		m.addLot(next, currency, leftover, time.Time{})
+		err = m.logBalance(next, currency, logger.Won, leftover)
+		if err != nil {
+			return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("set satellite winners: ")
+		}
+		err = m.place(sat, next, placing, leftover)
+		if err != nil {
+			return err
+		}
+		winners = append(winners, entity.Winner{ID: next, Points: player.Points, Prize: leftover})
+	}
+	err = m.tournaments.UpdateId(id, bson.M{"$set": bson.M{"winners": winners}})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: err.Error()}.SetPrefix("set satellite winners: ")
+	}
+	return nil
+}
+
 // DeleteTournament deletes tournament
 func (m *Mongo) DeleteTournament(id string) error {
 	err := m.tournaments.RemoveId(id)
@@ -68,3 +229,13 @@ func (m *Mongo) DeleteTournament(id string) error {
 	}
 	return nil
 }
+
+// GetDeposit returns tournament deposit
+func (m *Mongo) GetDeposit(id string) (int, error) {
+	var t entity.Tournament
+	err := m.tournaments.FindId(id).Select(bson.M{"deposit": 1}).One(&t)
+	if err != nil {
+		return 0, errors.Error{Code: errors.NotFoundError, Message: "get deposit: tournament is not found, id " + id}
+	}
+	return t.Deposit, nil
+}
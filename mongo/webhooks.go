@@ -0,0 +1,158 @@
+package mongo
+
+import (
+	"log"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CreateWebhook creates webhook
+func (m *Mongo) CreateWebhook(webhook entity.Webhook) error {
+	err := m.webhooks.Insert(webhook)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create webhook: using duplicated id to create webhook, id " + webhook.ID}
+	}
+	return nil
+}
+
+// ListWebhooks returns every webhook ordered by id
+func (m *Mongo) ListWebhooks() ([]entity.Webhook, error) {
+	webhooks := []entity.Webhook{}
+	err := m.webhooks.Find(nil).Sort("_id").All(&webhooks)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list webhooks: " + err.Error()}
+	}
+	for i := range webhooks {
+		webhooks[i].Created = webhooks[i].Created.UTC()
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes webhook with its deliveries
+func (m *Mongo) DeleteWebhook(id string) error {
+	err := m.webhooks.RemoveId(id)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "delete webhook: webhook does not exist, id " + id}
+	}
+	_, err = m.deliveries.RemoveAll(bson.M{"webhookId": id})
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "delete webhook: " + err.Error()}
+	}
+	return nil
+}
+
+// ClaimDeliveries returns up to limit deliveries, which are due at now, in order they are due. Their next attempt
+// is moved by lease, so other dispatchers skip them, while they are being delivered.
+func (m *Mongo) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.Delivery, error) {
+	deliveries := []entity.Delivery{}
+	webhooks := make(map[string]entity.Webhook)
+	for len(deliveries) < limit {
+		var d entity.Delivery
+		// every delivery is claimed by one find and modify, so it is claimed by one dispatcher only
+		_, err := m.deliveries.Find(bson.M{"dead": false, "nextAttempt": bson.M{"$lte": now}}).Sort("nextAttempt", "_id").
+			Apply(mgo.Change{Update: bson.M{"$set": bson.M{"nextAttempt": now.Add(lease)}}, ReturnNew: true}, &d)
+		if err == mgo.ErrNotFound {
+			break
+		}
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "claim deliveries: " + err.Error()}
+		}
+		w, ok := webhooks[d.WebhookID]
+		if !ok {
+			err = m.webhooks.FindId(d.WebhookID).One(&w)
+			if err == mgo.ErrNotFound {
+				// webhook has been deleted after delivery was written
+				_ = m.deliveries.RemoveId(d.ID)
+				continue
+			}
+			if err != nil {
+				return nil, errors.Error{Code: errors.UnexpectedError, Message: "claim deliveries: " + err.Error()}
+			}
+			webhooks[d.WebhookID] = w
+		}
+		d.URL, d.Secret = w.URL, w.Secret
+		d.NextAttempt = d.NextAttempt.UTC()
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// CompleteDelivery deletes delivered delivery from outbox
+func (m *Mongo) CompleteDelivery(id string) error {
+	err := m.deliveries.RemoveId(id)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "complete delivery: delivery does not exist, id " + id}
+	}
+	return nil
+}
+
+// FailDelivery saves failed attempt of delivery, its next attempt and whether it is dead
+func (m *Mongo) FailDelivery(delivery entity.Delivery) error {
+	err := m.deliveries.UpdateId(delivery.ID, bson.M{"$set": bson.M{"attempts": delivery.Attempts, "nextAttempt": delivery.NextAttempt,
+		"lastError": delivery.LastError, "dead": delivery.Dead}})
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "fail delivery: delivery does not exist, id " + delivery.ID}
+	}
+	return nil
+}
+
+// ListDeadDeliveries returns dead deliveries in order they were written
+func (m *Mongo) ListDeadDeliveries() ([]entity.Delivery, error) {
+	deliveries := []entity.Delivery{}
+	err := m.deliveries.Find(bson.M{"dead": true}).Sort("event.created", "_id").All(&deliveries)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list dead deliveries: " + err.Error()}
+	}
+	for i := range deliveries {
+		var w entity.Webhook
+		if m.webhooks.FindId(deliveries[i].WebhookID).One(&w) == nil {
+			deliveries[i].URL = w.URL
+		}
+		deliveries[i].NextAttempt = deliveries[i].NextAttempt.UTC()
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery returns dead delivery into outbox, it is due now
+func (m *Mongo) RetryDelivery(id string) error {
+	err := m.deliveries.Update(bson.M{"_id": id, "dead": true}, bson.M{"$set": bson.M{"dead": false, "attempts": 0, "nextAttempt": time.Now().UTC()}})
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "retry delivery: dead delivery does not exist, id " + id}
+	}
+	return nil
+}
+
+// outbox writes delivery of event to every webhook, which gets events of its type. Mongo has no transactions,
+// so deliveries are written after change is saved, like log, and failed delivery is only logged.
+func (m *Mongo) outbox(e entity.Event) {
+	var webhooks []entity.Webhook
+	err := m.webhooks.Find(bson.M{"events": e.Type}).All(&webhooks)
+	if err != nil {
+		log.Println(errors.Error{Code: errors.UnexpectedError, Message: "outbox: cannot write event " + e.Type, Info: err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	e.Created = now
+	for _, w := range webhooks {
+		err = m.deliveries.Insert(entity.Delivery{ID: bson.NewObjectId().Hex(), WebhookID: w.ID, Event: e, NextAttempt: now})
+		if err != nil {
+			log.Println(errors.Error{Code: errors.UnexpectedError, Message: "outbox: cannot write event " + e.Type + ", webhook " + w.ID, Info: err.Error()})
+		}
+	}
+}
+
+// logBalance logs operation with player points in currency and writes change of balance into outbox
+func (m *Mongo) logBalance(id, currency, op string, points int) error {
+	err := m.logger.Log(id, logCurrency(currency), op, points)
+	if err != nil {
+		return err
+	}
+	if points != 0 {
+		m.outbox(entity.Event{Type: entity.EventBalanceChanged, PlayerID: id, Currency: currency, Points: points})
+	}
+	return nil
+}
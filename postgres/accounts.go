@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// CreateAccount registers player account
+func (p *Postgres) CreateAccount(account entity.Account) error {
+	metadata, err := json.Marshal(account.Metadata)
+	if err != nil {
+		return errors.Error{Code: errors.JSONError, Message: "create account: cannot marshal metadata, id " + account.ID, Info: err.Error()}
+	}
+	res, err := p.db.Exec("INSERT INTO accounts (id, name, created, metadata, status) values ($1, $2, $3, $4, $5)",
+		account.ID, account.Name, account.Created, metadata, account.Status)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create account: using duplicated id to create account, id " + account.ID}
+	}
+	return resultError(res, "create account: cannot create account, id "+account.ID)
+}
+
+// GetAccount returns player account
+func (p *Postgres) GetAccount(id string) (entity.Account, error) {
+	row := p.db.QueryRow("SELECT name, created, metadata, status FROM accounts WHERE id=$1", id)
+	account := entity.Account{ID: id}
+	var metadata []byte
+	err := row.Scan(&account.Name, &account.Created, &metadata, &account.Status)
+	if err == sql.ErrNoRows {
+		return entity.Account{}, errors.Error{Code: errors.NotFoundError, Message: "get account: cannot find account, id " + id}
+	}
+	if err != nil {
+		return entity.Account{}, errors.Error{Code: errors.UnexpectedError, Message: "get account: " + err.Error()}
+	}
+	err = json.Unmarshal(metadata, &account.Metadata)
+	if err != nil {
+		return entity.Account{}, errors.Error{Code: errors.JSONError, Message: "get account: cannot unmarshal metadata, id " + id, Info: err.Error()}
+	}
+	account.Created = account.Created.UTC()
+	return account, nil
+}
+
+// SetAccountStatus sets status of player account
+func (p *Postgres) SetAccountStatus(id, status string) error {
+	res, err := p.db.Exec("UPDATE accounts SET status=$1 WHERE id=$2", status, id)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "set account status: " + err.Error()}
+	}
+	return resultError(res, "set account status: cannot find account, id "+id)
+}
+
+// CloseAccount withdraws player from open tournaments, if withdraw is set, pays out their balances
+// and marks account closed in one transaction. Player, who was funded without registration, gets closed account.
+func (p *Postgres) CloseAccount(id string, withdraw bool) (entity.Closure, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return entity.Closure{}, errors.Error{Code: errors.UnexpectedError, Message: "close account: failed to start transaction", Info: err.Error()}
+	}
+	closure := entity.Closure{PlayerID: id, Payouts: []entity.Player{}}
+	var inTeam bool
+	err = tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM tournaments t JOIN team_members m ON m.teamId=ANY(t.teams)
+		WHERE t.isOpen AND m.playerId=$1)`, id).Scan(&inTeam)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}, err2)
+	}
+	if inTeam {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(errors.Error{Code: errors.OpenEntriesError, Message: "close account: player is in team of open tournament, id " + id}, err2)
+	}
+	closure.Withdrawn, err = withdrawTx(tx, id, withdraw)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(err, err2)
+	}
+	_, err = tx.Exec("DELETE FROM holds WHERE playerId=$1", id)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}, err2)
+	}
+	rows, err := tx.Query("SELECT currency, points FROM players WHERE id=$1 ORDER BY currency FOR UPDATE", id)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}, err2)
+	}
+	for rows.Next() {
+		payout := entity.Player{ID: id}
+		err = rows.Scan(&payout.Currency, &payout.Points)
+		if err != nil {
+			rows.Close()
+			err2 := tx.Rollback()
+			return entity.Closure{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}, err2)
+		}
+		closure.Payouts = append(closure.Payouts, payout)
+	}
+	rows.Close()
+	_, err = tx.Exec("UPDATE players SET points=0 WHERE id=$1", id)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}, err2)
+	}
+	for _, payout := range closure.Payouts {
+		if payout.Points == 0 {
+			continue
+		}
+		err = logTx(tx, id, payout.Currency, opPayout, -payout.Points, "")
+		if err != nil {
+			err2 := tx.Rollback()
+			return entity.Closure{}, errors.Join(err, err2)
+		}
+		err = balanceTx(tx, id, payout.Currency, -payout.Points)
+		if err != nil {
+			err2 := tx.Rollback()
+			return entity.Closure{}, errors.Join(err, err2)
+		}
+	}
+	_, err = tx.Exec("DELETE FROM lots WHERE playerId=$1", id)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}, err2)
+	}
+	res, err := tx.Exec(`INSERT INTO accounts (id, name, created, metadata, status)
+		SELECT $1, $1, now(), 'null', $2 WHERE $3 OR EXISTS(SELECT 1 FROM accounts WHERE id=$1)
+		ON CONFLICT (id) DO UPDATE SET status=EXCLUDED.status`, id, entity.StatusClosed, len(closure.Payouts) > 0)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}, err2)
+	}
+	err = resultError(res, "close account: cannot find player, id "+id)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Closure{}, errors.Join(err, err2)
+	}
+	return closure, tx.Commit()
+}
+
+// withdrawTx removes player entries from open tournaments and returns deposits, which they have paid, from prizes.
+// Tickets and seats are not refunded. If withdraw is not set, player with open entries gets error.
+func withdrawTx(tx *sql.Tx, id string, withdraw bool) ([]string, error) {
+	rows, err := tx.Query(`SELECT t.id, t.currency,
+		(SELECT COALESCE(sum(e.paid), 0) FROM tournament_entries e WHERE e.tournamentId=t.id AND e.playerId=$1 AND e.teamId IS NULL) AS paid
+		FROM tournaments t WHERE t.isOpen AND EXISTS(SELECT 1 FROM tournament_entries e WHERE e.tournamentId=t.id AND e.playerId=$1 AND e.teamId IS NULL)
+		ORDER BY t.id FOR UPDATE`, id)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}
+	}
+	type open struct {
+		tourID, currency string
+		paid             int
+	}
+	var tours []open
+	for rows.Next() {
+		var t open
+		err = rows.Scan(&t.tourID, &t.currency, &t.paid)
+		if err != nil {
+			rows.Close()
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}
+		}
+		tours = append(tours, t)
+	}
+	rows.Close()
+	if len(tours) > 0 && !withdraw {
+		return nil, errors.Error{Code: errors.OpenEntriesError, Message: "close account: player has entries in " + strconv.Itoa(len(tours)) + " open tournaments, id " + id}
+	}
+	var withdrawn []string
+	for _, t := range tours {
+		refund := t.paid
+		_, err = tx.Exec("UPDATE tournaments SET prize=prize-$1 WHERE id=$2", refund, t.tourID)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}
+		}
+		_, err = tx.Exec("DELETE FROM tournament_entries WHERE tournamentId=$1 AND playerId=$2", t.tourID, id)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "close account: " + err.Error()}
+		}
+		if refund > 0 {
+			_, err = fundTxPlayer(tx, id, t.currency, refund, time.Time{})
+			if err != nil {
+				return nil, err
+			}
+			err = logTx(tx, id, t.currency, opRefund, refund, "")
+			if err != nil {
+				return nil, err
+			}
+		}
+		withdrawn = append(withdrawn, t.tourID)
+	}
+	return withdrawn, nil
+}
+
+// DeleteAccount deletes player account
+func (p *Postgres) DeleteAccount(id string) error {
+	res, err := p.db.Exec("DELETE FROM accounts WHERE id=$1", id)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "delete account: " + err.Error()}
+	}
+	return resultError(res, "delete account: cannot find account, id "+id)
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// ApplyBatch applies every operation in one transaction, nothing is applied, if one of them fails.
+// Index of failed operation is returned with its error, it is -1, if batch has failed as whole.
+func (p *Postgres) ApplyBatch(ops []entity.Operation) (int, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return -1, errors.Error{Code: errors.UnexpectedError, Message: "apply batch: failed to start transaction", Info: err.Error()}
+	}
+	for i, op := range ops {
+		err = applyTx(tx, op)
+		if err != nil {
+			err2 := tx.Rollback()
+			return i, errors.Join(err, err2)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return -1, errors.Error{Code: errors.TransactionError, Message: "apply batch: failed to commit transaction", Info: err.Error()}
+	}
+	return -1, nil
+}
+
+// ApplyEach applies every operation independently and returns their errors in order of operations.
+// Every operation is applied in its own transaction, so locks of applied operations are not held until the whole
+// batch is applied. Failure of transaction is error of its operation, because previous operations are committed.
+func (p *Postgres) ApplyEach(ops []entity.Operation) ([]error, error) {
+	errs := make([]error, len(ops))
+	for i, op := range ops {
+		errs[i] = p.apply(op)
+	}
+	return errs, nil
+}
+
+// apply applies operation in its own transaction
+func (p *Postgres) apply(op entity.Operation) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "apply operation: failed to start transaction", Info: err.Error()}
+	}
+	err = applyTx(tx, op)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(err, err2)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return errors.Error{Code: errors.TransactionError, Message: "apply operation: failed to commit transaction", Info: err.Error()}
+	}
+	return nil
+}
+
+// applyTx applies fund, take or join operation in transaction
+func applyTx(tx *sql.Tx, op entity.Operation) error {
+	switch op.Type {
+	case entity.OpFund:
+		_, err := fundTxPlayer(tx, op.PlayerID, op.Currency, op.Points, time.Time{})
+		return err
+	case entity.OpTake:
+		err := updateTxPlayer(tx, op.PlayerID, op.Currency, -op.Points)
+		if err != nil || op.Points == 0 {
+			return err
+		}
+		return logTx(tx, op.PlayerID, op.Currency, opTake, -op.Points, "")
+	case entity.OpJoin:
+		return joinTx(tx, op.TournamentID, op.PlayerID)
+	}
+	return errors.Error{Code: errors.InvalidBatchError, Message: "apply operation: unknown operation type, id: " + op.PlayerID, Info: op.Type}
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// GetHistory returns every tournament, which player has participated in, the latest ones first
+func (p *Postgres) GetHistory(playerID string) ([]entity.Participation, error) {
+	rows, err := p.db.Query(`SELECT e.tournamentId, t.currency, t.isOpen, sum(e.paid), min(e.joined),
+		COALESCE((SELECT min(r.placing) FROM tournament_results r WHERE r.tournamentId=e.tournamentId AND r.playerId=e.playerId), 0),
+		COALESCE((SELECT sum(r.prize) FROM tournament_results r WHERE r.tournamentId=e.tournamentId AND r.playerId=e.playerId), 0)
+		FROM tournament_entries e JOIN tournaments t ON t.id=e.tournamentId WHERE e.playerId=$1
+		GROUP BY e.tournamentId, e.playerId, t.currency, t.isOpen ORDER BY min(e.joined) DESC, e.tournamentId`, playerID)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get history: " + err.Error()}
+	}
+	defer rows.Close()
+	history := []entity.Participation{}
+	for rows.Next() {
+		part := entity.Participation{PlayerID: playerID}
+		err = rows.Scan(&part.TournamentID, &part.Currency, &part.IsOpen, &part.Paid, &part.Joined, &part.Placing, &part.Prize)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "get history: " + err.Error()}
+		}
+		part.Joined = part.Joined.UTC()
+		history = append(history, part)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get history: " + err.Error()}
+	}
+	return history, nil
+}
+
+// getResults returns tournament winners in order, they have been placed
+func (p *Postgres) getResults(id string) ([]entity.Winner, error) {
+	rows, err := p.db.Query(`SELECT playerId, points, prize, COALESCE(seat, ''), COALESCE(entry, 0), COALESCE(teamId, '')
+		FROM tournament_results WHERE tournamentId=$1 ORDER BY id`, id)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get results: " + err.Error()}
+	}
+	defer rows.Close()
+	var winners []entity.Winner
+	for rows.Next() {
+		var w entity.Winner
+		err = rows.Scan(&w.ID, &w.Points, &w.Prize, &w.Seat, &w.Entry, &w.Team)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "get results: " + err.Error()}
+		}
+		winners = append(winners, w)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get results: " + err.Error()}
+	}
+	return winners, nil
+}
+
+// addTxMemberEntry adds entry of team member into tournament, paid is part of deposit, which member has paid
+func addTxMemberEntry(tx *sql.Tx, tourID, playerID, teamID string, paid int) error {
+	_, err := tx.Exec(`INSERT INTO tournament_entries (tournamentId, playerId, currency, entry, teamId, paid)
+		SELECT t.id, $2, t.currency, (SELECT COALESCE(max(e.entry), 0)+1 FROM tournament_entries e WHERE e.tournamentId=t.id AND e.playerId=$2),
+		NULLIF($3, ''), $4 FROM tournaments t WHERE t.id=$1`, tourID, playerID, teamID, paid)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "add entry: cannot add entry, playerID: " + playerID, Info: err.Error()}
+	}
+	return nil
+}
+
+// ensureTxPlayer creates empty player balance in tournament currency, if player does not have it,
+// so entries of players, who have not paid, reference existing balance
+func ensureTxPlayer(tx *sql.Tx, id, tourID string) error {
+	_, err := tx.Exec(`INSERT INTO players (id, currency, points) SELECT $1, currency, 0 FROM tournaments WHERE id=$2
+		ON CONFLICT (id, currency) DO NOTHING`, id, tourID)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "ensure player: cannot create balance, id " + id, Info: err.Error()}
+	}
+	return nil
+}
+
+// addTxResult records winner placing and prize in tournament results and writes chosen winner into outbox
+func addTxResult(tx *sql.Tx, tourID, currency string, placing int, w entity.Winner) error {
+	_, err := tx.Exec(`INSERT INTO tournament_results (tournamentId, playerId, currency, placing, points, prize, seat, entry, teamId)
+		values ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, 0), NULLIF($9, ''))`,
+		tourID, w.ID, currency, placing, w.Points, w.Prize, w.Seat, w.Entry, w.Team)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "add result: cannot record result, playerID: " + w.ID, Info: err.Error()}
+	}
+	return outboxTx(tx, entity.Event{Type: entity.EventWinnerChosen, TournamentID: tourID, PlayerID: w.ID, TeamID: w.Team, Points: w.Prize, Currency: currency})
+}
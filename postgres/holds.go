@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// CreateHold reserves player points, if player has enough available points
+func (p *Postgres) CreateHold(hold entity.Hold) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "create hold: failed to start transaction", Info: err.Error()}
+	}
+	// player row is locked, so concurrent holds and takes cannot spend the same points
+	row := tx.QueryRow(`SELECT points - (SELECT COALESCE(sum(points), 0) FROM holds WHERE playerId=$1 AND currency=$2 AND expires > now())
+		FROM players WHERE id=$1 AND currency=$2 FOR UPDATE`, hold.PlayerID, hold.Currency)
+	var available int
+	err = row.Scan(&available)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(errors.Error{Code: errors.NotFoundError, Message: "create hold: cannot find player, id " + hold.PlayerID}, err2)
+	}
+	if available < hold.Points {
+		err2 := tx.Rollback()
+		return errors.Join(errors.Error{Code: errors.NegativePointsNumberError, Message: "create hold: player doesn't have enough available points, id " + hold.PlayerID}, err2)
+	}
+	_, err = tx.Exec("INSERT INTO holds (id, playerId, currency, points, expires) values ($1, $2, $3, $4, $5)", hold.ID, hold.PlayerID, hold.Currency, hold.Points, hold.Expires)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(errors.Error{Code: errors.DuplicatedIDError, Message: "create hold: using duplicated id to create hold, id " + hold.ID}, err2)
+	}
+	return tx.Commit()
+}
+
+// GetHolds returns player holds in currency, which have not expired yet
+func (p *Postgres) GetHolds(playerID, currency string) ([]entity.Hold, error) {
+	rows, err := p.db.Query("SELECT id, points, expires FROM holds WHERE playerId=$1 AND currency=$2 AND expires > now() ORDER BY expires, id", playerID, currency)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get holds: " + err.Error()}
+	}
+	defer rows.Close()
+	var holds []entity.Hold
+	for rows.Next() {
+		h := entity.Hold{PlayerID: playerID, Currency: currency}
+		err = rows.Scan(&h.ID, &h.Points, &h.Expires)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "get holds: " + err.Error()}
+		}
+		h.Expires = h.Expires.UTC()
+		holds = append(holds, h)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get holds: " + err.Error()}
+	}
+	return holds, nil
+}
+
+// CaptureHold takes held points from player in one transaction, writes it into ledger and returns captured hold
+func (p *Postgres) CaptureHold(id string) (entity.Hold, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return entity.Hold{}, errors.Error{Code: errors.UnexpectedError, Message: "capture hold: failed to start transaction", Info: err.Error()}
+	}
+	row := tx.QueryRow("DELETE FROM holds WHERE id=$1 AND expires > now() RETURNING playerId, currency, points, expires", id)
+	hold := entity.Hold{ID: id}
+	err = row.Scan(&hold.PlayerID, &hold.Currency, &hold.Points, &hold.Expires)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Hold{}, errors.Join(errors.Error{Code: errors.NotFoundError, Message: "capture hold: hold does not exist or has expired, id " + id}, err2)
+	}
+	err = updateTxPlayer(tx, hold.PlayerID, hold.Currency, -hold.Points)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Hold{}, errors.Join(err, err2).SetPrefix("capture hold: ")
+	}
+	err = logTx(tx, hold.PlayerID, hold.Currency, opCapture, -hold.Points, "")
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Hold{}, errors.Join(err, err2)
+	}
+	hold.Expires = hold.Expires.UTC()
+	return hold, tx.Commit()
+}
+
+// ReleaseHold deletes hold, so player can spend held points again
+func (p *Postgres) ReleaseHold(id string) error {
+	res, err := p.db.Exec("DELETE FROM holds WHERE id=$1 AND expires > now()", id)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "release hold: " + err.Error()}
+	}
+	return resultError(res, "release hold: hold does not exist or has expired, id "+id)
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// CreateAPIKey creates API key
+func (p *Postgres) CreateAPIKey(key entity.APIKey) error {
+	res, err := p.db.Exec("INSERT INTO api_keys (id, name, role, hash, created) values ($1, $2, $3, $4, $5)",
+		key.ID, key.Name, key.Role, key.Hash, key.Created)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create api key: using duplicated id to create api key, id " + key.ID}
+	}
+	return resultError(res, "create api key: cannot create api key, id "+key.ID)
+}
+
+// GetAPIKey returns API key by its hash
+func (p *Postgres) GetAPIKey(hash string) (entity.APIKey, error) {
+	var key entity.APIKey
+	err := p.db.QueryRow("SELECT id, name, role, hash, created FROM api_keys WHERE hash=$1", hash).Scan(&key.ID, &key.Name, &key.Role, &key.Hash, &key.Created)
+	if err == sql.ErrNoRows {
+		return entity.APIKey{}, errors.Error{Code: errors.NotFoundError, Message: "get api key: cannot find api key"}
+	}
+	if err != nil {
+		return entity.APIKey{}, errors.Error{Code: errors.UnexpectedError, Message: "get api key: " + err.Error()}
+	}
+	key.Created = key.Created.UTC()
+	return key, nil
+}
+
+// ListAPIKeys returns every API key ordered by creation time
+func (p *Postgres) ListAPIKeys() ([]entity.APIKey, error) {
+	rows, err := p.db.Query("SELECT id, name, role, hash, created FROM api_keys ORDER BY created, id")
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list api keys: " + err.Error()}
+	}
+	defer rows.Close()
+	keys := []entity.APIKey{}
+	for rows.Next() {
+		var key entity.APIKey
+		err = rows.Scan(&key.ID, &key.Name, &key.Role, &key.Hash, &key.Created)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "list api keys: " + err.Error()}
+		}
+		key.Created = key.Created.UTC()
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list api keys: " + err.Error()}
+	}
+	return keys, nil
+}
+
+// DeleteAPIKey deletes API key
+func (p *Postgres) DeleteAPIKey(id string) error {
+	res, err := p.db.Exec("DELETE FROM api_keys WHERE id=$1", id)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "delete api key: " + err.Error()}
+	}
+	return resultError(res, "delete api key: api key does not exist, id "+id)
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// Block of operations, that are written to ledger
+const (
+	opTransfer = "transfer"
+	opCapture  = "capture"
+	opExpire   = "expire"
+	opPromo    = "promo"
+	opTake     = "take"
+	opDeposit  = "deposit"
+	opRefund   = "refund"
+	opPayout   = "payout"
+)
+
+// spendOps are operations, which are counted in player spending
+var spendOps = []string{opTake, opDeposit, opCapture}
+
+// logTx writes operation with player points in currency into ledger, counterparty is other player of operation if it has one
+func logTx(tx *sql.Tx, playerID, currency, op string, points int, counterparty string) error {
+	_, err := tx.Exec("INSERT INTO ledger (playerId, currency, operation, points, counterparty) values ($1, $2, $3, $4, NULLIF($5, ''))", playerID, currency, op, points, counterparty)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "log: cannot write operation " + op + " into ledger, id " + playerID, Info: err.Error()}
+	}
+	return nil
+}
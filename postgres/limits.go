@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/lib/pq"
+)
+
+// GetLimits returns responsible play limits of player
+func (p *Postgres) GetLimits(playerID string) (entity.Limits, error) {
+	row := p.db.QueryRow("SELECT daily, weekly, pending, excludedUntil FROM limits WHERE playerId=$1", playerID)
+	limits := entity.Limits{PlayerID: playerID}
+	var pending []byte
+	var excludedUntil sql.NullTime
+	err := row.Scan(&limits.Daily, &limits.Weekly, &pending, &excludedUntil)
+	if err == sql.ErrNoRows {
+		return entity.Limits{}, errors.Error{Code: errors.NotFoundError, Message: "get limits: player has no limits, id " + playerID}
+	}
+	if err != nil {
+		return entity.Limits{}, errors.Error{Code: errors.UnexpectedError, Message: "get limits: " + err.Error()}
+	}
+	if pending != nil {
+		err = json.Unmarshal(pending, &limits.Pending)
+		if err != nil {
+			return entity.Limits{}, errors.Error{Code: errors.JSONError, Message: "get limits: cannot unmarshal pending limits, id " + playerID, Info: err.Error()}
+		}
+	}
+	limits.ExcludedUntil = excludedUntil.Time.UTC()
+	return limits, nil
+}
+
+// SetLimits creates or replaces responsible play limits of player
+func (p *Postgres) SetLimits(limits entity.Limits) error {
+	var pending []byte
+	if limits.Pending != nil {
+		var err error
+		pending, err = json.Marshal(limits.Pending)
+		if err != nil {
+			return errors.Error{Code: errors.JSONError, Message: "set limits: cannot marshal pending limits, id " + limits.PlayerID, Info: err.Error()}
+		}
+	}
+	_, err := p.db.Exec(`INSERT INTO limits (playerId, daily, weekly, pending, excludedUntil) values ($1, $2, $3, $4, $5)
+		ON CONFLICT (playerId) DO UPDATE SET daily=EXCLUDED.daily, weekly=EXCLUDED.weekly, pending=EXCLUDED.pending, excludedUntil=EXCLUDED.excludedUntil`,
+		limits.PlayerID, limits.Daily, limits.Weekly, pending, nullTime(limits.ExcludedUntil))
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "set limits: " + err.Error()}
+	}
+	return nil
+}
+
+// GetSpent returns points, which player has spent in all currencies since the time. Points of open holds
+// are counted as spent, because they are spent, when holds are captured.
+func (p *Postgres) GetSpent(playerID string, since time.Time) (int, error) {
+	row := p.db.QueryRow(`SELECT COALESCE(-sum(points), 0) + (SELECT COALESCE(sum(points), 0) FROM holds WHERE playerId=$1 AND expires > now())
+		FROM ledger WHERE playerId=$1 AND operation=ANY($2) AND created >= $3`, playerID, pq.Array(spendOps), since)
+	var spent int
+	err := row.Scan(&spent)
+	if err != nil {
+		return 0, errors.Error{Code: errors.UnexpectedError, Message: "get spent: " + err.Error()}
+	}
+	return spent, nil
+}
+
+// DeleteLimits deletes responsible play limits of player
+func (p *Postgres) DeleteLimits(playerID string) error {
+	res, err := p.db.Exec("DELETE FROM limits WHERE playerId=$1", playerID)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "delete limits: " + err.Error()}
+	}
+	return resultError(res, "delete limits: player has no limits, id "+playerID)
+}
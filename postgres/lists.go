@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/lib/pq"
+)
+
+// Block of columns, which lists can be sorted by, with types of cursor values
+var (
+	accountSorts = map[string]string{"id": "text", "name": "text", "created": "timestamptz"}
+	tourSorts    = map[string]string{"id": "text", "deposit": "integer", "created": "timestamptz"}
+)
+
+// listQuery builds conditions of list query with numbered arguments
+type listQuery struct {
+	conds []string
+	args  []interface{}
+}
+
+// where adds condition, every ? in it is replaced with next argument
+func (q *listQuery) where(cond string, args ...interface{}) {
+	for _, arg := range args {
+		q.args = append(q.args, arg)
+		cond = strings.Replace(cond, "?", "$"+strconv.Itoa(len(q.args)), 1)
+	}
+	q.conds = append(q.conds, cond)
+}
+
+// page returns where, order and limit clauses of keyset pagination, items after cursor are selected
+func (q *listQuery) page(column, cast string, desc bool, after *entity.Cursor, limit int) string {
+	op, order := ">", " ASC"
+	if desc {
+		op, order = "<", " DESC"
+	}
+	if after != nil {
+		q.where("("+column+", id) "+op+" (?::"+cast+", ?)", after.Value, after.ID)
+	}
+	var clause string
+	if len(q.conds) > 0 {
+		clause = " WHERE " + strings.Join(q.conds, " AND ")
+	}
+	return clause + " ORDER BY " + column + order + ", id" + order + " LIMIT " + strconv.Itoa(limit)
+}
+
+// playersTable has players, who are registered or have balance, players without account have empty name, metadata
+// and status and zero created time
+const playersTable = `(SELECT COALESCE(a.id, p.id) AS id, COALESCE(a.name, '') AS name,
+	COALESCE(a.created, '0001-01-01 00:00:00Z') AS created, COALESCE(a.metadata, 'null') AS metadata, COALESCE(a.status, '') AS status
+	FROM accounts a FULL JOIN (SELECT DISTINCT id FROM players) p ON p.id=a.id) players`
+
+// ListAccounts returns players, registered or with balance, which match filter, sorted by filter sort column
+func (p *Postgres) ListAccounts(filter entity.PlayerFilter) ([]entity.Account, error) {
+	cast, ok := accountSorts[filter.Sort]
+	if !ok {
+		return nil, errors.Error{Code: errors.InvalidFilterError, Message: "list accounts: cannot sort by " + filter.Sort}
+	}
+	var q listQuery
+	if filter.Status != "" {
+		q.where("status=?", filter.Status)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		q.where("created>=?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		q.where("created<?", filter.CreatedTo)
+	}
+	clauses := q.page(filter.Sort, cast, filter.Desc, filter.After, filter.Limit)
+	rows, err := p.db.Query("SELECT id, name, created, metadata, status FROM "+playersTable+clauses, q.args...)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list accounts: " + err.Error()}
+	}
+	defer rows.Close()
+	accounts := []entity.Account{}
+	for rows.Next() {
+		var a entity.Account
+		var metadata []byte
+		err = rows.Scan(&a.ID, &a.Name, &a.Created, &metadata, &a.Status)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "list accounts: " + err.Error()}
+		}
+		err = json.Unmarshal(metadata, &a.Metadata)
+		if err != nil {
+			return nil, errors.Error{Code: errors.JSONError, Message: "list accounts: cannot unmarshal metadata, id " + a.ID, Info: err.Error()}
+		}
+		a.Created = a.Created.UTC()
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+// ListTournaments returns tournaments, which match filter, sorted by filter sort column
+func (p *Postgres) ListTournaments(filter entity.TourFilter) ([]entity.Tournament, error) {
+	cast, ok := tourSorts[filter.Sort]
+	if !ok {
+		return nil, errors.Error{Code: errors.InvalidFilterError, Message: "list tournaments: cannot sort by " + filter.Sort}
+	}
+	var q listQuery
+	if filter.Status != "" {
+		q.where("isOpen=?", filter.Status == "open")
+	}
+	if filter.MinDeposit > 0 {
+		q.where("deposit>=?", filter.MinDeposit)
+	}
+	if filter.MaxDeposit > 0 {
+		q.where("deposit<=?", filter.MaxDeposit)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		q.where("created>=?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		q.where("created<?", filter.CreatedTo)
+	}
+	if filter.ParticipantID != "" {
+		q.where("id IN (SELECT tournamentId FROM tournament_entries WHERE playerId=?)", filter.ParticipantID)
+	}
+	clauses := q.page(filter.Sort, cast, filter.Desc, filter.After, filter.Limit)
+	rows, err := p.db.Query("SELECT "+tourColumns+" FROM tournaments"+clauses, q.args...)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list tournaments: " + err.Error()}
+	}
+	defer rows.Close()
+	tours := []entity.Tournament{}
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "list tournaments: " + err.Error()}
+		}
+		tours = append(tours, t)
+	}
+	return tours, rows.Err()
+}
+
+// tourColumns are tournament columns, which are scanned by scanTournament, participants are collected from entries
+const tourColumns = `id, deposit, currency, prize,
+	ARRAY(SELECT e.playerId FROM tournament_entries e WHERE e.tournamentId=tournaments.id AND e.teamId IS NULL
+	GROUP BY e.playerId ORDER BY min(e.joined), e.playerId), isOpen, COALESCE(targetId, ''), COALESCE(seats, 0),
+	maxEntries, isTeam, teams, created, closed`
+
+// scanner is implemented by sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTournament scans tournament columns, which are selected by tourColumns
+func scanTournament(row scanner) (entity.Tournament, error) {
+	var (
+		t      entity.Tournament
+		closed pq.NullTime
+	)
+	err := row.Scan(&t.ID, &t.Deposit, &t.Currency, &t.Prize, pq.Array(&t.Participants), &t.IsOpen, &t.Satellite.TargetID, &t.Satellite.Seats,
+		&t.MaxEntries, &t.IsTeam, pq.Array(&t.Teams), &t.Created, &closed)
+	if err != nil {
+		return entity.Tournament{}, err
+	}
+	t.Created = t.Created.UTC()
+	if closed.Valid {
+		c := closed.Time.UTC()
+		t.Closed = &c
+	}
+	return t, nil
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// FundLot adds lot points to player balance in one transaction, lot points expire with lot
+func (p *Postgres) FundLot(lot entity.Lot) (entity.Player, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return entity.Player{}, errors.Error{Code: errors.UnexpectedError, Message: "fund lot: failed to start transaction", Info: err.Error()}
+	}
+	points, err := fundTxPlayer(tx, lot.PlayerID, lot.Currency, lot.Points, lot.Expires)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Player{}, errors.Join(err, err2).SetPrefix("fund lot: ")
+	}
+	err = tx.Commit()
+	if err != nil {
+		return entity.Player{}, err
+	}
+	return entity.Player{ID: lot.PlayerID, Points: points + lot.Points, Currency: lot.Currency}, nil
+}
+
+// GetExpiringLots returns player lots in currency, which will expire, ordered by expiry time
+func (p *Postgres) GetExpiringLots(playerID, currency string) ([]entity.Lot, error) {
+	rows, err := p.db.Query(`SELECT id::text, points, created, expires FROM lots
+		WHERE playerId=$1 AND currency=$2 AND expires IS NOT NULL ORDER BY expires, id`, playerID, currency)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get expiring lots: " + err.Error()}
+	}
+	defer rows.Close()
+	var lots []entity.Lot
+	for rows.Next() {
+		l := entity.Lot{PlayerID: playerID, Currency: currency}
+		err = rows.Scan(&l.ID, &l.Points, &l.Created, &l.Expires)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "get expiring lots: " + err.Error()}
+		}
+		l.Created, l.Expires = l.Created.UTC(), l.Expires.UTC()
+		lots = append(lots, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get expiring lots: " + err.Error()}
+	}
+	return lots, nil
+}
+
+// ExpireLots takes points of expired lots from players in one transaction, writes it into ledger
+// and returns expired lots
+func (p *Postgres) ExpireLots() ([]entity.Lot, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "expire lots: failed to start transaction", Info: err.Error()}
+	}
+	rows, err := tx.Query("DELETE FROM lots WHERE expires <= now() RETURNING playerId, currency, points")
+	if err != nil {
+		err2 := tx.Rollback()
+		return nil, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "expire lots: " + err.Error()}, err2)
+	}
+	var expired []entity.Lot
+	for rows.Next() {
+		var l entity.Lot
+		err = rows.Scan(&l.PlayerID, &l.Currency, &l.Points)
+		if err != nil {
+			rows.Close()
+			err2 := tx.Rollback()
+			return nil, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "expire lots: " + err.Error()}, err2)
+		}
+		expired = append(expired, l)
+	}
+	rows.Close()
+	for _, l := range expired {
+		// expired points are taken even if they are held, so balance is not checked against holds
+		_, err = tx.Exec("UPDATE players SET points=points-$1 WHERE id=$2 AND currency=$3", l.Points, l.PlayerID, l.Currency)
+		if err != nil {
+			err2 := tx.Rollback()
+			return nil, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "expire lots: cannot take points, id " + l.PlayerID, Info: err.Error()}, err2)
+		}
+		err = logTx(tx, l.PlayerID, l.Currency, opExpire, -l.Points, "")
+		if err != nil {
+			err2 := tx.Rollback()
+			return nil, errors.Join(err, err2)
+		}
+		err = balanceTx(tx, l.PlayerID, l.Currency, -l.Points)
+		if err != nil {
+			err2 := tx.Rollback()
+			return nil, errors.Join(err, err2)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// addTxLot adds lot with points to player, lot with zero expires never expires
+func addTxLot(tx *sql.Tx, id, currency string, points int, expires time.Time) error {
+	if points == 0 {
+		return nil
+	}
+	_, err := tx.Exec("INSERT INTO lots (playerId, currency, points, expires) values ($1, $2, $3, $4)", id, currency, points, nullTime(expires))
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "add lot: cannot add lot, id " + id, Info: err.Error()}
+	}
+	return nil
+}
+
+// consumeTxLots spends points from the oldest player lots. Points of balances, which were funded before lots were added,
+// are not in any lot, they are the oldest ones, so they are spent first.
+func consumeTxLots(tx *sql.Tx, id, currency string, points int) error {
+	_, err := tx.Exec(`WITH untracked AS (
+			SELECT p.points + $3::int - COALESCE((SELECT sum(points) FROM lots WHERE playerId=$1 AND currency=$2), 0) AS points
+			FROM players p WHERE p.id=$1 AND p.currency=$2
+		), ordered AS (
+			SELECT id, points, sum(points) OVER (ORDER BY created, id) AS running FROM lots WHERE playerId=$1 AND currency=$2
+		)
+		UPDATE lots l SET points=GREATEST(o.running - GREATEST($3::int - u.points, 0), 0) FROM ordered o, untracked u
+		WHERE l.id=o.id AND o.running - o.points < $3::int - u.points`, id, currency, points)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "consume lots: cannot spend lots, id " + id, Info: err.Error()}
+	}
+	_, err = tx.Exec("DELETE FROM lots WHERE playerId=$1 AND currency=$2 AND points=0", id, currency)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "consume lots: cannot delete spent lots, id " + id, Info: err.Error()}
+	}
+	return nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,92 @@
+-- Creates participations table and records participations in tournaments, which were played before it was introduced,
+-- from participants, entries, teams and winners of tournaments. Tables and columns, which were added with satellite,
+-- team, re-entry and currency tournaments, are created if database is older than them. Migration runs in one
+-- transaction, so it is applied completely or not at all.
+BEGIN;
+
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS targetId text,
+	ADD COLUMN IF NOT EXISTS seats integer,
+	ADD COLUMN IF NOT EXISTS winners json,
+	ADD COLUMN IF NOT EXISTS maxEntries integer NOT NULL DEFAULT 1,
+	ADD COLUMN IF NOT EXISTS isTeam bool NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS teams text[],
+	ADD COLUMN IF NOT EXISTS currency text NOT NULL DEFAULT 'points',
+	ADD COLUMN IF NOT EXISTS created timestamptz NOT NULL DEFAULT now(),
+	ADD COLUMN IF NOT EXISTS closed timestamptz;
+
+CREATE TABLE IF NOT EXISTS entries (
+	tournamentId text REFERENCES tournaments ON DELETE CASCADE,
+	playerId text,
+	entry integer,
+	paid integer,
+	PRIMARY KEY (tournamentId, playerId, entry)
+);
+-- paid is not set for entries made before it was recorded, they are counted as paid deposits
+ALTER TABLE entries ADD COLUMN IF NOT EXISTS paid integer;
+
+CREATE TABLE IF NOT EXISTS teams (
+	id text PRIMARY KEY,
+	captain text
+);
+CREATE TABLE IF NOT EXISTS team_members (
+	teamId text REFERENCES teams ON DELETE CASCADE,
+	playerId text,
+	share integer,
+	PRIMARY KEY (teamId, playerId)
+);
+
+CREATE TABLE IF NOT EXISTS participations (
+	tournamentId text REFERENCES tournaments ON DELETE CASCADE,
+	playerId text,
+	paid integer NOT NULL DEFAULT 0,
+	placing integer,
+	prize integer NOT NULL DEFAULT 0,
+	joined timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (tournamentId, playerId)
+);
+CREATE INDEX IF NOT EXISTS participations_player ON participations (playerId, joined);
+
+-- players, who have entered tournaments, with deposits they have paid for entries
+INSERT INTO participations (tournamentId, playerId, paid, joined)
+SELECT e.tournamentId, e.playerId, sum(COALESCE(e.paid, t.deposit)), min(t.created)
+FROM entries e JOIN tournaments t ON t.id=e.tournamentId
+GROUP BY e.tournamentId, e.playerId
+ON CONFLICT (tournamentId, playerId) DO NOTHING;
+
+-- team members with their shares of deposit, captain pays remainder of split like on join
+INSERT INTO participations (tournamentId, playerId, paid, joined)
+SELECT s.tournamentId, s.playerId, sum(s.paid), min(s.created)
+FROM (
+	SELECT t.id AS tournamentId, m.playerId, t.created,
+		t.deposit*m.share/100 + CASE WHEN m.playerId=tm.captain
+			THEN t.deposit - sum(t.deposit*m.share/100) OVER (PARTITION BY t.id, m.teamId) ELSE 0 END AS paid
+	FROM tournaments t JOIN team_members m ON m.teamId=ANY(t.teams) JOIN teams tm ON tm.id=m.teamId
+) s
+GROUP BY s.tournamentId, s.playerId
+ON CONFLICT (tournamentId, playerId) DO NOTHING;
+
+-- participants, who have joined before entries were introduced, have paid deposit once
+INSERT INTO participations (tournamentId, playerId, paid, joined)
+SELECT DISTINCT t.id, p.playerId, t.deposit, t.created
+FROM tournaments t CROSS JOIN LATERAL unnest(t.participants) AS p(playerId)
+WHERE p.playerId <> ''
+ON CONFLICT (tournamentId, playerId) DO NOTHING;
+
+-- winners of regular tournaments, participations, which already have placing, have been recorded after results
+INSERT INTO participations (tournamentId, playerId, placing, prize, joined)
+SELECT t.id, t.winner->>'id', 1, COALESCE((t.winner->>'prize')::integer, 0), t.created
+FROM tournaments t WHERE t.winners IS NULL AND COALESCE(t.winner->>'id', '') <> ''
+ON CONFLICT (tournamentId, playerId) DO UPDATE SET placing=1, prize=participations.prize+EXCLUDED.prize
+WHERE participations.placing IS NULL;
+
+-- winners of satellite and team tournaments, placing is ranking position of satellite places and 1 for team winners
+INSERT INTO participations (tournamentId, playerId, placing, prize, joined)
+SELECT t.id, w.value->>'id', min(CASE WHEN t.isTeam THEN 1 ELSE w.ordinality END),
+	sum(COALESCE((w.value->>'prize')::integer, 0)), min(t.created)
+FROM tournaments t CROSS JOIN LATERAL json_array_elements(t.winners->'winners') WITH ORDINALITY AS w(value, ordinality)
+WHERE json_typeof(t.winners->'winners')='array' AND COALESCE(w.value->>'id', '') <> ''
+GROUP BY t.id, w.value->>'id'
+ON CONFLICT (tournamentId, playerId) DO UPDATE SET placing=EXCLUDED.placing, prize=participations.prize+EXCLUDED.prize
+WHERE participations.placing IS NULL;
+
+COMMIT;
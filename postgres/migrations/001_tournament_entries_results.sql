@@ -0,0 +1,134 @@
+-- Moves tournament participants, entries and winners from tournaments row into tournament_entries
+-- and tournament_results tables. Tables and columns, which were added with satellite, team, re-entry, currency
+-- tournaments and participations, are created if database is older than them, so it migrates any earlier database.
+-- Migration runs in one transaction, so it is applied completely or not at all.
+BEGIN;
+
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS targetId text,
+	ADD COLUMN IF NOT EXISTS seats integer,
+	ADD COLUMN IF NOT EXISTS winners json,
+	ADD COLUMN IF NOT EXISTS maxEntries integer NOT NULL DEFAULT 1,
+	ADD COLUMN IF NOT EXISTS isTeam bool NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS teams text[],
+	ADD COLUMN IF NOT EXISTS currency text NOT NULL DEFAULT 'points',
+	ADD COLUMN IF NOT EXISTS created timestamptz NOT NULL DEFAULT now(),
+	ADD COLUMN IF NOT EXISTS closed timestamptz;
+
+-- player balances are kept by currency since currency tournaments
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='players' AND column_name='currency') THEN
+		ALTER TABLE players ADD COLUMN currency text NOT NULL DEFAULT 'points';
+		ALTER TABLE players DROP CONSTRAINT players_pkey, ADD PRIMARY KEY (id, currency);
+	END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS entries (
+	tournamentId text REFERENCES tournaments ON DELETE CASCADE,
+	playerId text,
+	entry integer,
+	paid integer,
+	PRIMARY KEY (tournamentId, playerId, entry)
+);
+ALTER TABLE entries ADD COLUMN IF NOT EXISTS paid integer;
+CREATE TABLE IF NOT EXISTS teams (
+	id text PRIMARY KEY,
+	captain text
+);
+CREATE TABLE IF NOT EXISTS team_members (
+	teamId text REFERENCES teams ON DELETE CASCADE,
+	playerId text,
+	share integer,
+	PRIMARY KEY (teamId, playerId)
+);
+CREATE TABLE IF NOT EXISTS participations (
+	tournamentId text REFERENCES tournaments ON DELETE CASCADE,
+	playerId text,
+	paid integer NOT NULL DEFAULT 0,
+	placing integer,
+	prize integer NOT NULL DEFAULT 0,
+	joined timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (tournamentId, playerId)
+);
+
+CREATE TABLE tournament_entries (
+	tournamentId text NOT NULL REFERENCES tournaments ON DELETE CASCADE,
+	playerId text NOT NULL,
+	currency text NOT NULL,
+	entry integer NOT NULL CHECK (entry > 0),
+	teamId text REFERENCES teams ON DELETE CASCADE,
+	paid integer NOT NULL DEFAULT 0 CHECK (paid >= 0),
+	joined timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (tournamentId, playerId, entry),
+	FOREIGN KEY (playerId, currency) REFERENCES players ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
+CREATE INDEX tournament_entries_player ON tournament_entries (playerId, joined);
+
+CREATE TABLE tournament_results (
+	id bigserial PRIMARY KEY,
+	tournamentId text NOT NULL REFERENCES tournaments ON DELETE CASCADE,
+	playerId text NOT NULL,
+	currency text NOT NULL,
+	placing integer NOT NULL CHECK (placing > 0),
+	points integer NOT NULL,
+	prize integer NOT NULL CHECK (prize >= 0),
+	seat text REFERENCES tournaments ON DELETE SET NULL,
+	entry integer,
+	teamId text REFERENCES teams ON DELETE SET NULL,
+	FOREIGN KEY (playerId, currency) REFERENCES players ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
+CREATE INDEX tournament_results_tournament ON tournament_results (tournamentId);
+CREATE INDEX tournament_results_player ON tournament_results (playerId, tournamentId);
+
+-- player entries, entries made before paid was recorded are counted as paid deposits
+INSERT INTO tournament_entries (tournamentId, playerId, currency, entry, paid, joined)
+SELECT e.tournamentId, e.playerId, t.currency, e.entry, COALESCE(e.paid, t.deposit), COALESCE(p.joined, t.created)
+FROM entries e JOIN tournaments t ON t.id=e.tournamentId
+LEFT JOIN participations p ON p.tournamentId=e.tournamentId AND p.playerId=e.playerId;
+
+-- entries of team members, who were stored by teams of tournament only, with their shares of deposit, captain pays
+-- remainder of split like on join
+INSERT INTO tournament_entries (tournamentId, playerId, currency, entry, teamId, paid, joined)
+SELECT t.id, m.playerId, t.currency, row_number() OVER (PARTITION BY t.id, m.playerId ORDER BY m.teamId), m.teamId,
+	t.deposit*m.share/100 + CASE WHEN m.playerId=tm.captain
+		THEN t.deposit - sum(t.deposit*m.share/100) OVER (PARTITION BY t.id, m.teamId) ELSE 0 END,
+	COALESCE(p.joined, t.created)
+FROM tournaments t JOIN team_members m ON m.teamId=ANY(t.teams) JOIN teams tm ON tm.id=m.teamId
+LEFT JOIN participations p ON p.tournamentId=t.id AND p.playerId=m.playerId;
+
+-- participants, who have joined before entries were introduced, have paid deposit once
+INSERT INTO tournament_entries (tournamentId, playerId, currency, entry, paid, joined)
+SELECT DISTINCT t.id, u.playerId, t.currency, 1, t.deposit, t.created
+FROM tournaments t CROSS JOIN LATERAL unnest(t.participants) AS u(playerId)
+WHERE u.playerId <> '' AND NOT EXISTS (
+	SELECT 1 FROM tournament_entries te WHERE te.tournamentId=t.id AND te.playerId=u.playerId
+);
+
+-- winners of regular tournaments
+INSERT INTO tournament_results (tournamentId, playerId, currency, placing, points, prize, entry)
+SELECT t.id, t.winner->>'id', t.currency, 1, (t.winner->>'points')::integer, (t.winner->>'prize')::integer,
+	NULLIF((t.winner->>'entry')::integer, 0)
+FROM tournaments t WHERE t.winners IS NULL AND COALESCE(t.winner->>'id', '') <> ''
+ORDER BY t.id;
+
+-- winners of satellite and team tournaments in order, they have been placed
+INSERT INTO tournament_results (tournamentId, playerId, currency, placing, points, prize, seat, teamId)
+SELECT t.id, w.value->>'id', t.currency,
+	CASE WHEN t.isTeam THEN 1 ELSE COALESCE(p.placing, w.ordinality) END,
+	(w.value->>'points')::integer, (w.value->>'prize')::integer, NULLIF(w.value->>'seat', ''), NULLIF(w.value->>'team', '')
+FROM tournaments t CROSS JOIN LATERAL json_array_elements(t.winners->'winners') WITH ORDINALITY AS w(value, ordinality)
+LEFT JOIN participations p ON p.tournamentId=t.id AND p.playerId=w.value->>'id'
+WHERE json_typeof(t.winners->'winners')='array' AND COALESCE(w.value->>'id', '') <> ''
+ORDER BY t.id, w.ordinality;
+
+-- entries and results reference player balances, members, who have never paid or won, get empty ones
+INSERT INTO players (id, currency, points)
+SELECT playerId, currency, 0 FROM tournament_entries
+UNION SELECT playerId, currency, 0 FROM tournament_results
+ON CONFLICT (id, currency) DO NOTHING;
+
+ALTER TABLE tournaments DROP COLUMN participants, DROP COLUMN winner, DROP COLUMN winners;
+DROP TABLE entries;
+DROP TABLE participations;
+
+COMMIT;
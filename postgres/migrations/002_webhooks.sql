@@ -0,0 +1,24 @@
+-- Adds webhooks and their outbox of deliveries. Deliveries are written in the same transaction as the change,
+-- which produces event, dispatcher deletes delivered ones and marks ones, which have failed every attempt, dead.
+BEGIN;
+
+CREATE TABLE webhooks (
+	id text PRIMARY KEY,
+	url text NOT NULL,
+	events text[] NOT NULL,
+	secret text NOT NULL,
+	created timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_deliveries (
+	id bigserial PRIMARY KEY,
+	webhookId text NOT NULL REFERENCES webhooks ON DELETE CASCADE,
+	event json NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	nextAttempt timestamptz NOT NULL DEFAULT now(),
+	lastError text,
+	dead bool NOT NULL DEFAULT false
+);
+CREATE INDEX webhook_deliveries_due ON webhook_deliveries (nextAttempt, id) WHERE NOT dead;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Adds API keys of clients. Only hashes of keys are stored, keys are found by them.
+BEGIN;
+
+CREATE TABLE api_keys (
+	id text PRIMARY KEY,
+	name text NOT NULL,
+	role text NOT NULL,
+	hash text NOT NULL UNIQUE,
+	created timestamptz NOT NULL DEFAULT now()
+);
+
+COMMIT;
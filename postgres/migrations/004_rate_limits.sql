@@ -0,0 +1,12 @@
+-- Adds token buckets of rate limits, which are shared by instances. Buckets are pruned by updated time.
+BEGIN;
+
+CREATE TABLE rate_limits (
+	key text PRIMARY KEY,
+	tokens double precision NOT NULL,
+	updated timestamptz NOT NULL
+);
+
+CREATE INDEX rate_limits_updated ON rate_limits (updated);
+
+COMMIT;
@@ -3,57 +3,206 @@ package postgres
 import (
 	"database/sql"
 	"strconv"
+	"time"
 
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
 )
 
-// CreatePlayer creates new player with id and points
-func (p *Postgres) CreatePlayer(id string, points int) (entity.Player, error) {
-	res, err := p.db.Exec("INSERT INTO players (id, points) values ($1, $2)", id, points)
+// CreatePlayer creates new player balance with id and points in currency
+func (p *Postgres) CreatePlayer(id, currency string, points int) (entity.Player, error) {
+	tx, err := p.db.Begin()
 	if err != nil {
-		return entity.Player{}, errors.Error{Code: errors.DuplicatedIDError, Message: "create player: using duplicated id to create player, id " + id}
+		return entity.Player{}, errors.Error{Code: errors.UnexpectedError, Message: "create player: failed to start transaction", Info: err.Error()}
+	}
+	res, err := tx.Exec("INSERT INTO players (id, currency, points) values ($1, $2, $3)", id, currency, points)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Player{}, errors.Join(errors.Error{Code: errors.DuplicatedIDError, Message: "create player: using duplicated id to create player, id " + id}, err2)
 	}
 	err = resultError(res, "creating player: cannot create player, id "+id)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Player{}, errors.Join(err, err2)
+	}
+	err = addTxLot(tx, id, currency, points, time.Time{})
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Player{}, errors.Join(err, err2)
+	}
+	err = balanceTx(tx, id, currency, points)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Player{}, errors.Join(err, err2)
+	}
+	err = tx.Commit()
 	if err != nil {
 		return entity.Player{}, err
 	}
-	return entity.Player{ID: id, Points: points}, nil
+	return entity.Player{ID: id, Points: points, Currency: currency}, nil
 }
 
-// GetPlayer returns player by its id
-func (p *Postgres) GetPlayer(id string) (entity.Player, error) {
-	row := p.db.QueryRow("SELECT points FROM players WHERE id=$1", id)
+// GetPlayer returns player points in currency by player id
+func (p *Postgres) GetPlayer(id, currency string) (entity.Player, error) {
+	row := p.db.QueryRow("SELECT points FROM players WHERE id=$1 AND currency=$2", id, currency)
 	var points int
 	err := row.Scan(&points)
 	if err != nil {
 		return entity.Player{}, errors.Error{Code: errors.NotFoundError, Message: "get player: cannot find player, id " + id}
 	}
-	return entity.Player{ID: id, Points: points}, nil
+	return entity.Player{ID: id, Points: points, Currency: currency}, nil
 }
 
-// UpdatePlayer updates player points
-func (p *Postgres) UpdatePlayer(id string, dif int) error {
-	res, err := p.db.Exec("UPDATE players SET points=points+$1 WHERE id=$2", dif, id)
+// GetBalances returns player points in every currency, player has
+func (p *Postgres) GetBalances(id string) ([]entity.Player, error) {
+	rows, err := p.db.Query("SELECT currency, points FROM players WHERE id=$1 ORDER BY currency", id)
 	if err != nil {
-		return errors.Error{Code: errors.NegativePointsNumberError, Message: "update player: cannot update points numbers, dif " + strconv.Itoa(dif)}
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get balances: " + err.Error()}
+	}
+	defer rows.Close()
+	var balances []entity.Player
+	for rows.Next() {
+		b := entity.Player{ID: id}
+		err = rows.Scan(&b.Currency, &b.Points)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "get balances: " + err.Error()}
+		}
+		balances = append(balances, b)
 	}
-	return resultError(res, "update player: cannot find player, id "+id)
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get balances: " + err.Error()}
+	}
+	if len(balances) == 0 {
+		return nil, errors.Error{Code: errors.NotFoundError, Message: "get balances: cannot find player, id " + id}
+	}
+	return balances, nil
 }
 
-func updateTxPlayer(tx *sql.Tx, id string, dif int) error {
-	res, err := tx.Exec("UPDATE players SET points=points+$1 WHERE id=$2", dif, id)
+// UpdatePlayer updates player points in currency, held points cannot be taken
+func (p *Postgres) UpdatePlayer(id, currency string, dif int) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "update player: failed to start transaction", Info: err.Error()}
+	}
+	err = updateTxPlayer(tx, id, currency, dif)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(err, err2)
+	}
+	if dif < 0 {
+		err = logTx(tx, id, currency, opTake, dif, "")
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2)
+		}
+	}
+	return tx.Commit()
+}
+
+// updateTxPlayer updates player points in currency, added points become new lot,
+// taken points are spent from the oldest lots
+func updateTxPlayer(tx *sql.Tx, id, currency string, dif int) error {
+	res, err := tx.Exec(`UPDATE players SET points=points+$1 WHERE id=$2 AND currency=$3 AND ($1 >= 0 OR
+		points+$1 >= (SELECT COALESCE(sum(points), 0) FROM holds WHERE playerId=$2 AND currency=$3 AND expires > now()))`, dif, id, currency)
 	if err != nil {
 		return errors.Error{Code: errors.NegativePointsNumberError, Message: "update player: cannot update points numbers, dif " + strconv.Itoa(dif)}
 	}
-	return resultError(res, "update player: cannot find player, id "+id)
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		err = balanceTx(tx, id, currency, dif)
+		if err != nil {
+			return err
+		}
+		if dif < 0 {
+			return consumeTxLots(tx, id, currency, -dif)
+		}
+		return addTxLot(tx, id, currency, dif, time.Time{})
+	}
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id=$1 AND currency=$2)", id, currency).Scan(&exists)
+	if err != nil || !exists {
+		return errors.Error{Code: errors.NotFoundError, Message: "update player: cannot find player, id " + id}
+	}
+	return errors.Error{Code: errors.NegativePointsNumberError, Message: "update player: cannot update points numbers, dif " + strconv.Itoa(dif)}
+}
+
+// TransferPoints sends points in currency from one player to another in one transaction and writes it into ledger
+func (p *Postgres) TransferPoints(from, to, currency string, points int) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "transfer points: failed to start transaction", Info: err.Error()}
+	}
+	// players are updated in the same order by every transfer, so opposite transfers cannot deadlock
+	ids, difs := []string{from, to}, []int{-points, points}
+	if to < from {
+		ids[0], ids[1], difs[0], difs[1] = to, from, points, -points
+	}
+	for i := range ids {
+		err = updateTxPlayer(tx, ids[i], currency, difs[i])
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("transfer points: ")
+		}
+	}
+	err = logTx(tx, from, currency, opTransfer, -points, to)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(err, err2)
+	}
+	err = logTx(tx, to, currency, opTransfer, points, from)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(err, err2)
+	}
+	return tx.Commit()
 }
 
-// DeletePlayer deletes player from database
+func getTxPoints(tx *sql.Tx, id, currency string) (int, error) {
+	row := tx.QueryRow("SELECT points FROM players WHERE id=$1 AND currency=$2", id, currency)
+	var points int
+	err := row.Scan(&points)
+	if err != nil {
+		return 0, errors.Error{Code: errors.NotFoundError, Message: "get player: cannot find player, id " + id}
+	}
+	return points, nil
+}
+
+// fundTxPlayer adds points to player in currency as new lot, which expires at expires, if it is set,
+// and returns points, player had before. Player gets balance in currency, if they have not had it yet.
+func fundTxPlayer(tx *sql.Tx, id, currency string, points int, expires time.Time) (int, error) {
+	row := tx.QueryRow(`INSERT INTO players (id, currency, points) values ($1, $2, $3)
+		ON CONFLICT (id, currency) DO UPDATE SET points=players.points+excluded.points RETURNING points`, id, currency, points)
+	var total int
+	err := row.Scan(&total)
+	if err != nil {
+		return 0, errors.Error{Code: errors.UnexpectedError, Message: "fund player: cannot fund player, id " + id, Info: err.Error()}
+	}
+	err = addTxLot(tx, id, currency, points, expires)
+	if err != nil {
+		return 0, err
+	}
+	err = balanceTx(tx, id, currency, points)
+	if err != nil {
+		return 0, err
+	}
+	return total - points, nil
+}
+
+// DeletePlayer deletes player with balances in every currency from database
 func (p *Postgres) DeletePlayer(id string) error {
 	res, err := p.db.Exec("DELETE FROM players WHERE id=$1", id)
 	if err != nil {
 		return errors.Error{Code: errors.UnexpectedError, Message: "delete player: " + err.Error()}
 	}
-	return resultError(res, "delete player: player does not exist, id "+id)
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.Error{Code: errors.NotFoundError, Message: "delete player: player does not exist, id " + id}
+	}
+	return nil
 }
@@ -39,22 +39,33 @@ func (p *Postgres) UpdateTourAndPlayer(tourID, playerID string) error {
 	if err != nil {
 		return errors.Error{Code: errors.UnexpectedError, Message: "update tournament and player: failed to start transaction", Info: err.Error()}
 	}
-	err = updateTxParticipants(tx, tourID, playerID)
+	err = joinTx(tx, tourID, playerID)
 	if err != nil {
 		err2 := tx.Rollback()
 		return errors.Join(err, err2)
 	}
-	dep, err := p.getDeposit(tourID)
+	return tx.Commit()
+}
+
+// joinTx adds next player entry into tournament and takes deposit from player
+func joinTx(tx *sql.Tx, tourID, playerID string) error {
+	err := updateTxParticipants(tx, tourID, playerID, true)
 	if err != nil {
-		err2 := tx.Rollback()
-		return errors.Join(err, err2)
+		return err
 	}
-	err = updateTxPlayer(tx, playerID, -1*dep)
+	var (
+		dep      int
+		currency string
+	)
+	err = tx.QueryRow("SELECT deposit, currency FROM tournaments WHERE id=$1", tourID).Scan(&dep, &currency)
 	if err != nil {
-		err2 := tx.Rollback()
-		return errors.Join(err, err2)
+		return errors.Error{Code: errors.NotFoundError, Message: "get deposit: cannot get deposit from not existing tournament, id: " + tourID}
 	}
-	return tx.Commit()
+	err = updateTxPlayer(tx, playerID, currency, -1*dep)
+	if err != nil {
+		return err
+	}
+	return logTx(tx, playerID, currency, opDeposit, -1*dep, "")
 }
 
 func resultError(res sql.Result, possibleErr string) error {
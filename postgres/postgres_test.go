@@ -11,6 +11,7 @@ import (
 
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,9 +31,9 @@ func TestMain(m *testing.M) {
 
 func TestPlayer_CreatePlayer(t *testing.T) {
 	players := []entity.Player{
-		{ID: "createplayer_1", Points: 200},
-		{ID: "createplayer_2", Points: 200},
-		{ID: "createplayer_3", Points: 200},
+		{ID: "createplayer_1", Points: 200, Currency: entity.DefaultCurrency},
+		{ID: "createplayer_2", Points: 200, Currency: entity.DefaultCurrency},
+		{ID: "createplayer_3", Points: 200, Currency: entity.DefaultCurrency},
 	}
 	tt := []struct {
 		name          string
@@ -73,7 +74,7 @@ func TestPlayer_CreatePlayer(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			player, err := p.CreatePlayer(tc.player.ID, tc.player.Points)
+			player, err := p.CreatePlayer(tc.player.ID, entity.DefaultCurrency, tc.player.Points)
 			assert.Equal(t, tc.expectedError, err)
 			if err == nil {
 				assert.Equal(t, tc.player, player)
@@ -88,12 +89,12 @@ func TestPlayer_CreatePlayer(t *testing.T) {
 
 func TestPlayer_GetPlayer(t *testing.T) {
 	players := []entity.Player{
-		{ID: "getplayer_1", Points: 200},
-		{ID: "getplayer_2", Points: 200},
-		{ID: "getplayer_3", Points: 200},
+		{ID: "getplayer_1", Points: 200, Currency: entity.DefaultCurrency},
+		{ID: "getplayer_2", Points: 200, Currency: entity.DefaultCurrency},
+		{ID: "getplayer_3", Points: 200, Currency: entity.DefaultCurrency},
 	}
 	for i := range players {
-		_, err := p.CreatePlayer(players[i].ID, players[i].Points)
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeletePlayer(players[i].ID)
@@ -134,7 +135,7 @@ func TestPlayer_GetPlayer(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			p, err := p.GetPlayer(tc.id)
+			p, err := p.GetPlayer(tc.id, entity.DefaultCurrency)
 			assert.Equal(t, tc.expectedPlayer, p)
 			assert.Equal(t, tc.expectedError, err)
 		})
@@ -148,7 +149,7 @@ func TestPlayer_UpdatePlayer(t *testing.T) {
 		{ID: "updateplayer_3", Points: 200},
 	}
 	for i := range players {
-		_, err := p.CreatePlayer(players[i].ID, players[i].Points)
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeletePlayer(players[i].ID)
@@ -225,12 +226,80 @@ func TestPlayer_UpdatePlayer(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			err := p.UpdatePlayer(tc.id, tc.dif)
+			err := p.UpdatePlayer(tc.id, entity.DefaultCurrency, tc.dif)
 			assert.Equal(t, tc.expectedError, err)
 		})
 	}
 }
 
+func TestPlayer_TransferPoints(t *testing.T) {
+	players := []entity.Player{
+		{ID: "transferpoints_1", Points: 200},
+		{ID: "transferpoints_2", Points: 200},
+	}
+	for i := range players {
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
+		require.NoError(t, err)
+		defer func(i int) {
+			err = p.DeletePlayer(players[i].ID)
+			require.NoError(t, err)
+		}(i)
+	}
+	tt := []struct {
+		name           string
+		from           string
+		to             string
+		points         int
+		expectedPoints []int
+		expectedError  error
+	}{
+		{
+			name:           "transfer points: ok",
+			from:           players[0].ID,
+			to:             players[1].ID,
+			points:         150,
+			expectedPoints: []int{50, 350},
+			expectedError:  nil,
+		},
+		{
+			name:           "transfer points: back",
+			from:           players[1].ID,
+			to:             players[0].ID,
+			points:         50,
+			expectedPoints: []int{100, 300},
+			expectedError:  nil,
+		},
+		{
+			name:           "transfer points: not enough points",
+			from:           players[0].ID,
+			to:             players[1].ID,
+			points:         150,
+			expectedPoints: []int{100, 300},
+			expectedError:  errors.Error{Code: errors.NegativePointsNumberError, Message: "transfer points: update player: cannot update points numbers, dif -150"},
+		},
+		{
+			name:           "transfer points: not existing player",
+			from:           players[0].ID,
+			to:             "transferpoints_fake",
+			points:         50,
+			expectedPoints: []int{100, 300},
+			expectedError:  errors.Error{Code: errors.NotFoundError, Message: "transfer points: update player: cannot find player, id transferpoints_fake"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := p.TransferPoints(tc.from, tc.to, entity.DefaultCurrency, tc.points)
+			assert.Equal(t, tc.expectedError, err)
+			for i := range players {
+				player, err := p.GetPlayer(players[i].ID, entity.DefaultCurrency)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedPoints[i], player.Points)
+			}
+		})
+	}
+}
+
 func TestPlayer_DeletePlayer(t *testing.T) {
 	players := []entity.Player{
 		{ID: "deleteplayer_1", Points: 200},
@@ -238,7 +307,7 @@ func TestPlayer_DeletePlayer(t *testing.T) {
 		{ID: "deleteplayer_3", Points: 200},
 	}
 	for i := range players {
-		_, err := p.CreatePlayer(players[i].ID, players[i].Points)
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
 		require.NoError(t, err)
 	}
 	tt := []struct {
@@ -340,7 +409,7 @@ func TestTournament_CreateTournament(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			err := p.CreateTournament(tc.tournament.ID, tc.tournament.Deposit)
+			err := p.CreateTournament(tc.tournament.ID, entity.DefaultCurrency, tc.tournament.Deposit, 1)
 			assert.Equal(t, tc.expectedError, err)
 		})
 	}
@@ -357,7 +426,7 @@ func TestTournament_DeleteTournament(t *testing.T) {
 		{ID: "deletetournament_3", Deposit: 100},
 	}
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 	}
 	tt := []struct {
@@ -411,7 +480,7 @@ func TestTournament_CloseTournament(t *testing.T) {
 		{ID: "closetournament_3", Deposit: 100},
 	}
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeleteTournament(tournaments[i].ID)
@@ -481,14 +550,14 @@ func TestTournament_GetParticipants(t *testing.T) {
 	}
 	expParticipants := [][]string{nil, nil, nil}
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeleteTournament(tournaments[i].ID)
 		}(i)
 	}
 	for i := range players {
-		_, err := p.CreatePlayer(players[i].ID, players[i].Points)
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeletePlayer(players[i].ID)
@@ -551,7 +620,7 @@ func TestTournament_GetDeposit(t *testing.T) {
 		{ID: "getdeposit_3", Deposit: 300},
 	}
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeleteTournament(tournaments[i].ID)
@@ -591,7 +660,7 @@ func TestTournament_GetDeposit(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			dep, err := p.getDeposit(tc.id)
+			dep, err := p.GetDeposit(tc.id)
 			assert.Equal(t, tc.expectedError, err)
 			assert.Equal(t, tc.expectedDeposit, dep)
 		})
@@ -607,7 +676,7 @@ func TestTournament_GetState(t *testing.T) {
 	states := []bool{}
 	rand.Seed(time.Now().UnixNano())
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeleteTournament(tournaments[i].ID)
@@ -677,14 +746,14 @@ func TestTournament_GetWinner(t *testing.T) {
 	}
 	var expWinner []entity.Winner
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeleteTournament(tournaments[i].ID)
 		}(i)
 	}
 	for i := range winners {
-		_, err := p.CreatePlayer(winners[i].ID, winners[i].Prize)
+		_, err := p.CreatePlayer(winners[i].ID, entity.DefaultCurrency, winners[i].Prize)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeletePlayer(winners[i].ID)
@@ -750,14 +819,14 @@ func TestTournament_SetWinner(t *testing.T) {
 		{ID: "setwinner_3", Points: 200},
 	}
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeleteTournament(tournaments[i].ID)
 		}(i)
 	}
 	for i := range winners {
-		_, err := p.CreatePlayer(winners[i].ID, winners[i].Prize)
+		_, err := p.CreatePlayer(winners[i].ID, entity.DefaultCurrency, winners[i].Prize)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeletePlayer(winners[i].ID)
@@ -813,6 +882,142 @@ func TestTournament_SetWinner(t *testing.T) {
 	}
 }
 
+func TestTournament_SetSatelliteWinners(t *testing.T) {
+	target := entity.Tournament{ID: "setsatellite_target", Deposit: 100}
+	satellite := entity.Tournament{ID: "setsatellite_1", Deposit: 50, Satellite: entity.Satellite{TargetID: target.ID, Seats: 2}}
+	players := []entity.Player{
+		{ID: "setsatellite_1", Points: 50},
+		{ID: "setsatellite_2", Points: 50},
+		{ID: "setsatellite_3", Points: 50},
+		{ID: "setsatellite_4", Points: 50},
+		{ID: "setsatellite_5", Points: 50},
+	}
+	err := p.CreateTournament(target.ID, entity.DefaultCurrency, target.Deposit, 1)
+	require.NoError(t, err)
+	defer p.DeleteTournament(target.ID)
+	err = p.CreateSatellite(satellite.ID, satellite.Deposit, satellite.Satellite.TargetID, satellite.Satellite.Seats)
+	require.NoError(t, err)
+	defer p.DeleteTournament(satellite.ID)
+	var ranking []string
+	for i := range players {
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
+		require.NoError(t, err)
+		defer func(i int) {
+			err = p.DeletePlayer(players[i].ID)
+		}(i)
+		err = p.UpdateTourAndPlayer(satellite.ID, players[i].ID)
+		require.NoError(t, err)
+		ranking = append(ranking, players[i].ID)
+	}
+	sat, err := p.GetSatellite(satellite.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, satellite.Satellite, sat)
+
+	err = p.SetSatelliteWinners(satellite.ID, ranking, satellite.Satellite.Seats)
+	assert.NoError(t, err)
+	winners, err := p.GetWinner(satellite.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Winners{Winners: []entity.Winner{
+		{ID: players[0].ID, Points: 0, Prize: target.Deposit, Seat: target.ID},
+		{ID: players[1].ID, Points: 0, Prize: target.Deposit, Seat: target.ID},
+		{ID: players[2].ID, Points: 0, Prize: 50},
+	}}, winners)
+	part, err := p.GetParticipants(target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{players[0].ID, players[1].ID}, part)
+	player, err := p.GetPlayer(players[2].ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, player.Points)
+}
+
+func TestTournament_GetEntries(t *testing.T) {
+	tournament := entity.Tournament{ID: "getentries_1", Deposit: 50, MaxEntries: 2}
+	players := []entity.Player{
+		{ID: "getentries_1", Points: 200},
+		{ID: "getentries_2", Points: 200},
+	}
+	err := p.CreateTournament(tournament.ID, entity.DefaultCurrency, tournament.Deposit, tournament.MaxEntries)
+	require.NoError(t, err)
+	defer p.DeleteTournament(tournament.ID)
+	for i := range players {
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
+		require.NoError(t, err)
+		defer func(i int) {
+			err = p.DeletePlayer(players[i].ID)
+		}(i)
+	}
+	maxEntries, err := p.GetMaxEntries(tournament.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, tournament.MaxEntries, maxEntries)
+
+	expectedErrors := []error{nil, nil, nil, errors.Error{Code: errors.DuplicatedIDError, Message: "update participiants: player has used all entries, playerID: " + players[0].ID}}
+	for i, id := range []string{players[0].ID, players[1].ID, players[0].ID, players[0].ID} {
+		err = p.UpdateTourAndPlayer(tournament.ID, id)
+		assert.Equal(t, expectedErrors[i], err)
+	}
+	entries, err := p.GetEntries(tournament.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Entry{{PlayerID: players[0].ID, Number: 1}, {PlayerID: players[1].ID, Number: 1}, {PlayerID: players[0].ID, Number: 2}}, entries)
+	part, err := p.GetParticipants(tournament.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{players[0].ID, players[1].ID}, part)
+	player, err := p.GetPlayer(players[0].ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, player.Points)
+
+	_, err = p.GetEntries("getentries_fake")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "get entries: cannot get entries from not existing tournament, id: getentries_fake"}, err)
+}
+
+func TestTeam_TeamTournament(t *testing.T) {
+	players := []entity.Player{
+		{ID: "teamtour_1", Points: 100},
+		{ID: "teamtour_2", Points: 100},
+	}
+	team := entity.Team{ID: "teamtour_1", Captain: players[0].ID, Members: []entity.TeamMember{{PlayerID: players[0].ID, Share: 75}, {PlayerID: players[1].ID, Share: 25}}}
+	tournament := entity.Tournament{ID: "teamtour_1", Deposit: 50}
+	for i := range players {
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
+		require.NoError(t, err)
+		defer func(i int) {
+			err = p.DeletePlayer(players[i].ID)
+		}(i)
+	}
+	err := p.CreateTeam(team)
+	require.NoError(t, err)
+	defer p.DeleteTeam(team.ID)
+	err = p.CreateTeamTournament(tournament.ID, entity.DefaultCurrency, tournament.Deposit)
+	require.NoError(t, err)
+	defer p.DeleteTournament(tournament.ID)
+
+	gotTeam, err := p.GetTeam(team.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, team, gotTeam)
+	isTeam, err := p.IsTeamTournament(tournament.ID)
+	assert.NoError(t, err)
+	assert.True(t, isTeam)
+
+	err = p.UpdateTourAndTeam(tournament.ID, team.ID, false)
+	assert.NoError(t, err)
+	teams, err := p.GetTeams(tournament.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{team.ID}, teams)
+	for i, points := range []int{62, 88} {
+		player, err := p.GetPlayer(players[i].ID, entity.DefaultCurrency)
+		assert.NoError(t, err)
+		assert.Equal(t, points, player.Points)
+	}
+
+	err = p.SetTeamWinner(tournament.ID, team.ID)
+	assert.NoError(t, err)
+	winners, err := p.GetWinner(tournament.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Winners{Winners: []entity.Winner{
+		{ID: players[0].ID, Points: 62, Prize: 38, Team: team.ID},
+		{ID: players[1].ID, Points: 88, Prize: 12, Team: team.ID},
+	}}, winners)
+}
+
 func TestGama_UpdateTourAndPlayer(t *testing.T) {
 	tournaments := []entity.Tournament{
 		{ID: "updategame_1", Deposit: 50},
@@ -825,14 +1030,14 @@ func TestGama_UpdateTourAndPlayer(t *testing.T) {
 		{ID: "updategame_3", Points: 150},
 	}
 	for i := range tournaments {
-		err := p.CreateTournament(tournaments[i].ID, tournaments[i].Deposit)
+		err := p.CreateTournament(tournaments[i].ID, entity.DefaultCurrency, tournaments[i].Deposit, 1)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeleteTournament(tournaments[i].ID)
 		}(i)
 	}
 	for i := range players {
-		_, err := p.CreatePlayer(players[i].ID, players[i].Points)
+		_, err := p.CreatePlayer(players[i].ID, entity.DefaultCurrency, players[i].Points)
 		require.NoError(t, err)
 		defer func(i int) {
 			err = p.DeletePlayer(players[i].ID)
@@ -900,10 +1105,609 @@ func TestGama_UpdateTourAndPlayer(t *testing.T) {
 			assert.Equal(t, tc.expectedGetPartError, err)
 			assert.Equal(t, tc.expectedParticipants, part)
 			for i, v := range tc.participants {
-				player, err := p.GetPlayer(v.ID)
+				player, err := p.GetPlayer(v.ID, entity.DefaultCurrency)
 				assert.Equal(t, tc.expectedGetPlayError[i], err)
 				assert.Equal(t, tc.expectedPoints[i], player.Points)
 			}
 		})
 	}
 }
+
+func TestHold_CaptureRelease(t *testing.T) {
+	player := entity.Player{ID: "hold_player", Points: 200}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	expires := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	holds := []entity.Hold{
+		{ID: "hold_capture", PlayerID: player.ID, Points: 100, Currency: entity.DefaultCurrency, Expires: expires},
+		{ID: "hold_release", PlayerID: player.ID, Points: 50, Currency: entity.DefaultCurrency, Expires: expires},
+	}
+	for _, h := range holds {
+		require.NoError(t, p.CreateHold(h))
+	}
+
+	err = p.CreateHold(entity.Hold{ID: "hold_too_much", PlayerID: player.ID, Points: 100, Currency: entity.DefaultCurrency, Expires: expires})
+	assert.Equal(t, errors.Error{Code: errors.NegativePointsNumberError, Message: "create hold: player doesn't have enough available points, id " + player.ID}, err)
+	err = p.UpdatePlayer(player.ID, entity.DefaultCurrency, -100)
+	assert.Equal(t, errors.Error{Code: errors.NegativePointsNumberError, Message: "update player: cannot update points numbers, dif -100"}, err)
+	active, err := p.GetHolds(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, holds, active)
+
+	captured, err := p.CaptureHold(holds[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, holds[0], captured)
+	_, err = p.CaptureHold(holds[0].ID)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "capture hold: hold does not exist or has expired, id " + holds[0].ID}, err)
+	assert.NoError(t, p.ReleaseHold(holds[1].ID))
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "release hold: hold does not exist or has expired, id " + holds[1].ID}, p.ReleaseHold(holds[1].ID))
+	got, err := p.GetPlayer(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, got.Points)
+	active, err = p.GetHolds(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Empty(t, active)
+}
+
+func TestPlayer_Currencies(t *testing.T) {
+	player := entity.Player{ID: "currencies_player", Points: 200}
+	for _, currency := range []string{entity.DefaultCurrency, "coins"} {
+		_, err := p.CreatePlayer(player.ID, currency, player.Points)
+		require.NoError(t, err)
+	}
+	defer func() {
+		err := p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	tournament := entity.Tournament{ID: "currencies_tournament", Deposit: 150, Currency: "coins"}
+	err := p.CreateTournament(tournament.ID, tournament.Currency, tournament.Deposit, 1)
+	require.NoError(t, err)
+	defer func() {
+		err := p.DeleteTournament(tournament.ID)
+		require.NoError(t, err)
+	}()
+
+	err = p.UpdateTourAndPlayer(tournament.ID, player.ID)
+	assert.NoError(t, err)
+	balances, err := p.GetBalances(player.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Player{
+		{ID: player.ID, Points: 50, Currency: "coins"},
+		{ID: player.ID, Points: 200, Currency: entity.DefaultCurrency},
+	}, balances)
+	err = p.UpdatePlayer(player.ID, "coins", -100)
+	assert.Equal(t, errors.Error{Code: errors.NegativePointsNumberError, Message: "update player: cannot update points numbers, dif -100"}, err)
+	_, err = p.GetPlayer(player.ID, "gems")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "get player: cannot find player, id " + player.ID}, err)
+}
+
+func TestLot_ExpireLots(t *testing.T) {
+	player := entity.Player{ID: "lots_player", Points: 100}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	bonus := entity.Lot{PlayerID: player.ID, Currency: entity.DefaultCurrency, Points: 50, Expires: time.Now().Add(time.Second).UTC().Truncate(time.Second)}
+	got, err := p.FundLot(bonus)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Player{ID: player.ID, Points: 150, Currency: entity.DefaultCurrency}, got)
+
+	// the oldest lot is spent first, so bonus lot keeps 30 points
+	err = p.UpdatePlayer(player.ID, entity.DefaultCurrency, -120)
+	assert.NoError(t, err)
+	lots, err := p.GetExpiringLots(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	require.Len(t, lots, 1)
+	assert.Equal(t, 30, lots[0].Points)
+	assert.Equal(t, bonus.Expires, lots[0].Expires)
+
+	time.Sleep(2 * time.Second)
+	expired, err := p.ExpireLots()
+	assert.NoError(t, err)
+	assert.Contains(t, expired, entity.Lot{PlayerID: player.ID, Currency: entity.DefaultCurrency, Points: 30})
+	got, err = p.GetPlayer(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, got.Points)
+	lots, err = p.GetExpiringLots(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Empty(t, lots)
+}
+
+func TestPromo_RedeemPromo(t *testing.T) {
+	player := entity.Player{ID: "promo_player", Points: 100}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	now := time.Now().UTC().Truncate(time.Second)
+	promo := entity.Promo{Code: "promo_code", Points: 50, Currency: entity.DefaultCurrency, MaxUses: 1, MaxPerPlayer: 1, ValidFrom: now.Add(-time.Minute)}
+	require.NoError(t, p.CreatePromo(promo))
+	defer func() {
+		err = p.DeletePromo(promo.Code)
+		require.NoError(t, err)
+	}()
+	err = p.CreatePromo(promo)
+	assert.Equal(t, errors.Error{Code: errors.DuplicatedIDError, Message: "create promo: using duplicated code to create promo, code " + promo.Code}, err)
+
+	red, err := p.RedeemPromo(promo.Code, player.ID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Redemption{Code: promo.Code, PlayerID: player.ID, Use: 1, Points: 50, Currency: entity.DefaultCurrency, Redeemed: now}, red)
+	// retried redemption returns the same result without granting points again
+	again, err := p.RedeemPromo(promo.Code, player.ID, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, red, again)
+	got, err := p.GetPlayer(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 150, got.Points)
+
+	_, err = p.RedeemPromo(promo.Code, "promo_other_player", now)
+	assert.Equal(t, errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo has been used 1 times, code " + promo.Code}, err)
+	_, err = p.RedeemPromo("promo_not_exists", player.ID, now)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "redeem promo: promo does not exist, code promo_not_exists"}, err)
+}
+
+func TestLimits_GetSpent(t *testing.T) {
+	player := entity.Player{ID: "limits_player", Points: 100}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	since := time.Now().Add(-time.Minute)
+	require.NoError(t, p.UpdatePlayer(player.ID, entity.DefaultCurrency, -30))
+	spent, err := p.GetSpent(player.ID, since)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, spent)
+	// open hold is counted as spent
+	require.NoError(t, p.CreateHold(entity.Hold{ID: "limits_hold", PlayerID: player.ID, Points: 20, Currency: entity.DefaultCurrency, Expires: time.Now().Add(time.Minute)}))
+	spent, err = p.GetSpent(player.ID, since)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, spent)
+	require.NoError(t, p.ReleaseHold("limits_hold"))
+
+	_, err = p.GetLimits(player.ID)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "get limits: player has no limits, id " + player.ID}, err)
+	limits := entity.Limits{
+		PlayerID:      player.ID,
+		Daily:         100,
+		Pending:       &entity.PendingLimits{Daily: 200, Applies: time.Now().Add(time.Hour).UTC().Truncate(time.Second)},
+		ExcludedUntil: time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+	}
+	require.NoError(t, p.SetLimits(limits))
+	defer func() {
+		err = p.DeleteLimits(player.ID)
+		require.NoError(t, err)
+	}()
+	got, err := p.GetLimits(player.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, limits, got)
+	limits.Pending, limits.ExcludedUntil = nil, time.Time{}
+	require.NoError(t, p.SetLimits(limits))
+	got, err = p.GetLimits(player.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, limits, got)
+}
+
+func TestAccount_SetAccountStatus(t *testing.T) {
+	account := entity.Account{ID: "account_player", Name: "Player", Created: time.Now().UTC().Truncate(time.Second), Metadata: map[string]string{"country": "DE"}, Status: entity.StatusActive}
+	require.NoError(t, p.CreateAccount(account))
+	defer func() {
+		err := p.DeleteAccount(account.ID)
+		require.NoError(t, err)
+	}()
+	err := p.CreateAccount(account)
+	assert.Equal(t, errors.Error{Code: errors.DuplicatedIDError, Message: "create account: using duplicated id to create account, id " + account.ID}, err)
+	got, err := p.GetAccount(account.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account, got)
+
+	assert.NoError(t, p.SetAccountStatus(account.ID, entity.StatusSuspended))
+	got, err = p.GetAccount(account.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusSuspended, got.Status)
+	err = p.SetAccountStatus("account_not_exists", entity.StatusSuspended)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "set account status: cannot find account, id account_not_exists"}, err)
+}
+
+func TestAccount_CloseAccount(t *testing.T) {
+	player := entity.Player{ID: "close_player", Points: 200}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+		err = p.DeleteAccount(player.ID)
+		require.NoError(t, err)
+	}()
+	tournament := entity.Tournament{ID: "close_tournament", Deposit: 50}
+	require.NoError(t, p.CreateTournament(tournament.ID, entity.DefaultCurrency, tournament.Deposit, 1))
+	defer func() {
+		err = p.DeleteTournament(tournament.ID)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, p.UpdateTourAndPlayer(tournament.ID, player.ID))
+	// ticket entry is withdrawn without refund
+	ticket := entity.Tournament{ID: "close_ticket_tournament", Deposit: 70}
+	require.NoError(t, p.CreateTournament(ticket.ID, entity.DefaultCurrency, ticket.Deposit, 1))
+	defer func() {
+		err = p.DeleteTournament(ticket.ID)
+		require.NoError(t, err)
+	}()
+	promo := entity.Promo{Code: "close_ticket", Currency: entity.DefaultCurrency, TournamentID: ticket.ID, MaxPerPlayer: 1, ValidFrom: time.Now().Add(-time.Minute)}
+	require.NoError(t, p.CreatePromo(promo))
+	defer func() {
+		err = p.DeletePromo(promo.Code)
+		require.NoError(t, err)
+	}()
+	_, err = p.RedeemPromo(promo.Code, player.ID, time.Now())
+	require.NoError(t, err)
+
+	_, err = p.CloseAccount(player.ID, false)
+	assert.Equal(t, errors.Error{Code: errors.OpenEntriesError, Message: "close account: player has entries in 2 open tournaments, id " + player.ID}, err)
+	closure, err := p.CloseAccount(player.ID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Closure{PlayerID: player.ID, Withdrawn: []string{ticket.ID, tournament.ID}, Payouts: []entity.Player{{ID: player.ID, Points: 200, Currency: entity.DefaultCurrency}}}, closure)
+	participants, err := p.GetParticipants(tournament.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, participants)
+	got, err := p.GetPlayer(player.ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, got.Points)
+	account, err := p.GetAccount(player.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusClosed, account.Status)
+
+	_, err = p.CloseAccount("close_not_exists", false)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "close account: cannot find player, id close_not_exists"}, err)
+}
+
+func TestAccount_ListAccounts(t *testing.T) {
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	account := entity.Account{ID: "list_account_registered", Name: "A", Created: created, Status: entity.StatusActive}
+	require.NoError(t, p.CreateAccount(account))
+	defer func() {
+		err := p.DeleteAccount(account.ID)
+		require.NoError(t, err)
+	}()
+	_, err := p.CreatePlayer("list_account_funded", entity.DefaultCurrency, 100)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer("list_account_funded")
+		require.NoError(t, err)
+	}()
+
+	got, err := p.ListAccounts(entity.PlayerFilter{Sort: "id", After: &entity.Cursor{Value: "list_account_", ID: "list_account_"}, Limit: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, entity.Account{ID: "list_account_funded", Created: time.Time{}}, got[0])
+		assert.Equal(t, account.ID, got[1].ID)
+	}
+	got, err = p.ListAccounts(entity.PlayerFilter{Status: entity.StatusActive, CreatedFrom: created, Sort: "id", Limit: 10})
+	assert.NoError(t, err)
+	for _, a := range got {
+		assert.NotEqual(t, "list_account_funded", a.ID)
+	}
+}
+
+func TestTournament_ListTournaments(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "list_tournament_1", Deposit: 300},
+		{ID: "list_tournament_2", Deposit: 100},
+		{ID: "list_tournament_3", Deposit: 200},
+	}
+	for _, tour := range tournaments {
+		require.NoError(t, p.CreateTournament(tour.ID, entity.DefaultCurrency, tour.Deposit, 1))
+	}
+	defer func() {
+		for _, tour := range tournaments {
+			err := p.DeleteTournament(tour.ID)
+			require.NoError(t, err)
+		}
+	}()
+	player := entity.Player{ID: "list_player", Points: 1000}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, p.UpdateTourAndPlayer(tournaments[2].ID, player.ID))
+
+	ids := func(tours []entity.Tournament) []string {
+		var ids []string
+		for _, tour := range tours {
+			ids = append(ids, tour.ID)
+		}
+		return ids
+	}
+	filter := entity.TourFilter{MinDeposit: 100, MaxDeposit: 300, Sort: "deposit", Limit: 2}
+	got, err := p.ListTournaments(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tournaments[1].ID, tournaments[2].ID}, ids(got))
+	filter.After = &entity.Cursor{Value: "200", ID: tournaments[2].ID}
+	got, err = p.ListTournaments(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tournaments[0].ID}, ids(got))
+
+	got, err = p.ListTournaments(entity.TourFilter{Status: "open", ParticipantID: player.ID, Sort: "id", Limit: 10})
+	assert.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, tournaments[2].ID, got[0].ID)
+	assert.Equal(t, 200, got[0].Prize)
+	assert.Equal(t, []string{player.ID}, got[0].Participants)
+}
+
+func TestTournament_GetTournament(t *testing.T) {
+	tournament := entity.Tournament{ID: "details_tournament", Deposit: 100, MaxEntries: 2}
+	require.NoError(t, p.CreateTournament(tournament.ID, entity.DefaultCurrency, tournament.Deposit, tournament.MaxEntries))
+	defer func() {
+		err := p.DeleteTournament(tournament.ID)
+		require.NoError(t, err)
+	}()
+	player := entity.Player{ID: "details_player", Points: 500}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, p.UpdateTourAndPlayer(tournament.ID, player.ID))
+	require.NoError(t, p.UpdateTourAndPlayer(tournament.ID, player.ID))
+
+	got, err := p.GetTournament(tournament.ID)
+	assert.NoError(t, err)
+	assert.True(t, got.IsOpen)
+	assert.Nil(t, got.Closed)
+	assert.Equal(t, 200, got.Prize)
+	assert.Equal(t, []string{player.ID}, got.Participants)
+	assert.Equal(t, []entity.Entry{{PlayerID: player.ID, Number: 1}, {PlayerID: player.ID, Number: 2}}, got.Entries)
+
+	require.NoError(t, p.CloseTournament(tournament.ID))
+	require.NoError(t, p.SetTournamentWinner(tournament.ID, entity.Winner{ID: player.ID, Points: 300, Entry: 2}))
+	got, err = p.GetTournament(tournament.ID)
+	assert.NoError(t, err)
+	assert.False(t, got.IsOpen)
+	assert.NotNil(t, got.Closed)
+	assert.Equal(t, entity.Winner{ID: player.ID, Points: 300, Prize: 200, Entry: 2}, got.Winner)
+
+	_, err = p.GetTournament("details_fake")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "get tournament: cannot get not existing tournament, id: details_fake"}, err)
+}
+
+func TestHistory_GetHistory(t *testing.T) {
+	tournaments := []entity.Tournament{
+		{ID: "history_tournament_1", Deposit: 100, MaxEntries: 2},
+		{ID: "history_tournament_2", Deposit: 50, MaxEntries: 1},
+	}
+	for _, tour := range tournaments {
+		require.NoError(t, p.CreateTournament(tour.ID, entity.DefaultCurrency, tour.Deposit, tour.MaxEntries))
+	}
+	defer func() {
+		for _, tour := range tournaments {
+			err := p.DeleteTournament(tour.ID)
+			require.NoError(t, err)
+		}
+	}()
+	player := entity.Player{ID: "history_player", Points: 500}
+	_, err := p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, p.UpdateTourAndPlayer(tournaments[0].ID, player.ID))
+	require.NoError(t, p.UpdateTourAndPlayer(tournaments[0].ID, player.ID))
+	require.NoError(t, p.CloseTournament(tournaments[0].ID))
+	require.NoError(t, p.SetTournamentWinner(tournaments[0].ID, entity.Winner{ID: player.ID, Points: 300, Entry: 1}))
+	require.NoError(t, p.UpdateTourAndPlayer(tournaments[1].ID, player.ID))
+
+	history, err := p.GetHistory(player.ID)
+	assert.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, tournaments[1].ID, history[0].TournamentID)
+	assert.True(t, history[0].IsOpen)
+	assert.Equal(t, 50, history[0].Paid)
+	assert.Equal(t, 0, history[0].Placing)
+	assert.Equal(t, tournaments[0].ID, history[1].TournamentID)
+	assert.False(t, history[1].IsOpen)
+	assert.Equal(t, 200, history[1].Paid)
+	assert.Equal(t, 1, history[1].Placing)
+	assert.Equal(t, 200, history[1].Prize)
+
+	history, err = p.GetHistory("history_fake")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestWebhook_Outbox(t *testing.T) {
+	webhook := entity.Webhook{ID: "webhook_balance", URL: "http://localhost/hook", Events: []string{entity.EventBalanceChanged}, Secret: "secret",
+		Created: time.Now().UTC().Truncate(time.Second)}
+	require.NoError(t, p.CreateWebhook(webhook))
+	defer func() {
+		err := p.DeleteWebhook(webhook.ID)
+		require.NoError(t, err)
+	}()
+	err := p.CreateWebhook(webhook)
+	assert.Equal(t, errors.Error{Code: errors.DuplicatedIDError, Message: "create webhook: using duplicated id to create webhook, id " + webhook.ID}, err)
+	webhooks, err := p.ListWebhooks()
+	assert.NoError(t, err)
+	assert.Contains(t, webhooks, webhook)
+
+	player := entity.Player{ID: "webhook_player", Points: 100}
+	_, err = p.CreatePlayer(player.ID, entity.DefaultCurrency, player.Points)
+	require.NoError(t, err)
+	defer func() {
+		err = p.DeletePlayer(player.ID)
+		require.NoError(t, err)
+	}()
+	// failed change is rolled back with its deliveries
+	err = p.UpdatePlayer(player.ID, entity.DefaultCurrency, -500)
+	assert.Error(t, err)
+	require.NoError(t, p.UpdatePlayer(player.ID, entity.DefaultCurrency, -30))
+
+	now := time.Now().UTC()
+	deliveries, err := p.ClaimDeliveries(now, time.Minute, 100)
+	assert.NoError(t, err)
+	var points []int
+	for _, d := range deliveries {
+		if d.WebhookID != webhook.ID {
+			continue
+		}
+		assert.Equal(t, webhook.URL, d.URL)
+		assert.Equal(t, webhook.Secret, d.Secret)
+		assert.Equal(t, entity.EventBalanceChanged, d.Event.Type)
+		assert.Equal(t, player.ID, d.Event.PlayerID)
+		points = append(points, d.Event.Points)
+	}
+	assert.ElementsMatch(t, []int{100, -30}, points)
+	// claimed deliveries are leased
+	again, err := p.ClaimDeliveries(now, time.Minute, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, again)
+
+	require.True(t, len(deliveries) >= 2)
+	assert.NoError(t, p.CompleteDelivery(deliveries[0].ID))
+	dead := deliveries[1]
+	dead.Attempts, dead.LastError, dead.Dead = 8, "webhook answered with status 500", true
+	assert.NoError(t, p.FailDelivery(dead))
+	deadList, err := p.ListDeadDeliveries()
+	assert.NoError(t, err)
+	if assert.Len(t, deadList, 1) {
+		assert.Equal(t, dead.ID, deadList[0].ID)
+		assert.Equal(t, dead.LastError, deadList[0].LastError)
+	}
+	assert.NoError(t, p.RetryDelivery(dead.ID))
+	err = p.RetryDelivery(dead.ID)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "retry delivery: dead delivery does not exist, id " + dead.ID}, err)
+	deliveries, err = p.ClaimDeliveries(time.Now().UTC().Add(time.Second), time.Minute, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, dead.ID, deliveries[0].ID)
+		assert.Equal(t, 0, deliveries[0].Attempts)
+	}
+}
+
+func TestBatch_ApplyBatch(t *testing.T) {
+	tour := entity.Tournament{ID: "batch_tournament", Deposit: 100, MaxEntries: 1}
+	require.NoError(t, p.CreateTournament(tour.ID, entity.DefaultCurrency, tour.Deposit, tour.MaxEntries))
+	defer func() {
+		err := p.DeleteTournament(tour.ID)
+		require.NoError(t, err)
+	}()
+	players := []entity.Player{{ID: "batch_player_1", Points: 500}, {ID: "batch_player_2"}}
+	_, err := p.CreatePlayer(players[0].ID, entity.DefaultCurrency, players[0].Points)
+	require.NoError(t, err)
+	defer func() {
+		for _, player := range players {
+			err = p.DeletePlayer(player.ID)
+			require.NoError(t, err)
+		}
+	}()
+
+	// second join fails, so the whole batch is rolled back
+	n, err := p.ApplyBatch([]entity.Operation{
+		{Type: entity.OpFund, PlayerID: players[1].ID, Currency: entity.DefaultCurrency, Points: 200},
+		{Type: entity.OpJoin, PlayerID: players[0].ID, TournamentID: tour.ID},
+		{Type: entity.OpJoin, PlayerID: players[0].ID, TournamentID: tour.ID},
+	})
+	assert.Equal(t, 2, n)
+	assert.Equal(t, errors.DuplicatedIDError, errors.Transform(err).Code)
+	_, err = p.GetPlayer(players[1].ID, entity.DefaultCurrency)
+	assert.Error(t, err)
+	entries, err := p.GetEntries(tour.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	n, err = p.ApplyBatch([]entity.Operation{
+		{Type: entity.OpFund, PlayerID: players[1].ID, Currency: entity.DefaultCurrency, Points: 200},
+		{Type: entity.OpTake, PlayerID: players[0].ID, Currency: entity.DefaultCurrency, Points: 50},
+		{Type: entity.OpJoin, PlayerID: players[0].ID, TournamentID: tour.ID},
+	})
+	assert.Equal(t, -1, n)
+	assert.NoError(t, err)
+	player, err := p.GetPlayer(players[0].ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 350, player.Points)
+	player, err = p.GetPlayer(players[1].ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, player.Points)
+
+	// failed operations are rolled back, others are applied
+	errs, err := p.ApplyEach([]entity.Operation{
+		{Type: entity.OpTake, PlayerID: players[1].ID, Currency: entity.DefaultCurrency, Points: 500},
+		{Type: entity.OpJoin, PlayerID: players[1].ID, TournamentID: tour.ID},
+		{Type: entity.OpJoin, PlayerID: players[0].ID, TournamentID: tour.ID},
+		{Type: entity.OpFund, PlayerID: players[0].ID, Currency: entity.DefaultCurrency, Points: 10},
+	})
+	assert.NoError(t, err)
+	require.Len(t, errs, 4)
+	assert.Equal(t, errors.NegativePointsNumberError, errors.Transform(errs[0]).Code)
+	assert.NoError(t, errs[1])
+	assert.Equal(t, errors.DuplicatedIDError, errors.Transform(errs[2]).Code)
+	assert.NoError(t, errs[3])
+	player, err = p.GetPlayer(players[0].ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 360, player.Points)
+	player, err = p.GetPlayer(players[1].ID, entity.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, player.Points)
+	entries, err = p.GetEntries(tour.ID)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestKey_APIKeys(t *testing.T) {
+	key := entity.APIKey{ID: "key_admin", Name: "nightly jobs", Role: entity.RoleAdmin, Hash: "key_hash", Created: time.Now().UTC().Truncate(time.Second)}
+	require.NoError(t, p.CreateAPIKey(key))
+	err := p.CreateAPIKey(key)
+	assert.Equal(t, errors.Error{Code: errors.DuplicatedIDError, Message: "create api key: using duplicated id to create api key, id " + key.ID}, err)
+
+	found, err := p.GetAPIKey(key.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, key, found)
+	_, err = p.GetAPIKey("key_fake")
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "get api key: cannot find api key"}, err)
+	keys, err := p.ListAPIKeys()
+	assert.NoError(t, err)
+	assert.Contains(t, keys, key)
+
+	require.NoError(t, p.DeleteAPIKey(key.ID))
+	_, err = p.GetAPIKey(key.Hash)
+	assert.Error(t, err)
+	err = p.DeleteAPIKey(key.ID)
+	assert.Equal(t, errors.Error{Code: errors.NotFoundError, Message: "delete api key: api key does not exist, id " + key.ID}, err)
+}
+
+func TestRateLimit_TakeToken(t *testing.T) {
+	rule := ratelimit.Rule{Rate: 1, Burst: 2}
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, expected := range []time.Duration{0, 0, time.Second} {
+		wait, err := p.TakeToken("ratelimit_key", rule, now)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, wait)
+	}
+	wait, err := p.TakeToken("ratelimit_key", rule, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+	wait, err = p.TakeToken("ratelimit_other", rule, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+
+	require.NoError(t, p.PruneBuckets(now.Add(time.Second)))
+	wait, err = p.TakeToken("ratelimit_key", rule, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, wait)
+	// pruned bucket is full again
+	wait, err = p.TakeToken("ratelimit_other", rule, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// CreatePromo creates promotion code
+func (p *Postgres) CreatePromo(promo entity.Promo) error {
+	res, err := p.db.Exec(`INSERT INTO promos (code, points, currency, tournamentId, maxUses, maxPerPlayer, validFrom, validTo, newPlayersOnly)
+		values ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9)`, promo.Code, promo.Points, promo.Currency, promo.TournamentID,
+		promo.MaxUses, promo.MaxPerPlayer, promo.ValidFrom, nullTime(promo.ValidTo), promo.NewPlayersOnly)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create promo: using duplicated code to create promo, code " + promo.Code}
+	}
+	return resultError(res, "create promo: cannot create promo, code "+promo.Code)
+}
+
+// RedeemPromo grants promo reward to player and writes redemption in one transaction.
+// Promo row is locked, so concurrent redemptions cannot exceed usage limits.
+func (p *Postgres) RedeemPromo(code, playerID string, now time.Time) (entity.Redemption, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return entity.Redemption{}, errors.Error{Code: errors.UnexpectedError, Message: "redeem promo: failed to start transaction", Info: err.Error()}
+	}
+	row := tx.QueryRow(`SELECT points, currency, COALESCE(tournamentId, ''), maxUses, maxPerPlayer, validFrom, validTo, newPlayersOnly, uses
+		FROM promos WHERE code=$1 FOR UPDATE`, code)
+	promo := entity.Promo{Code: code}
+	var validTo sql.NullTime
+	err = row.Scan(&promo.Points, &promo.Currency, &promo.TournamentID, &promo.MaxUses, &promo.MaxPerPlayer, &promo.ValidFrom, &validTo, &promo.NewPlayersOnly, &promo.Uses)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Redemption{}, errors.Join(errors.Error{Code: errors.NotFoundError, Message: "redeem promo: promo does not exist, code " + code}, err2)
+	}
+	red := entity.Redemption{Code: code, PlayerID: playerID, Points: promo.Points, Currency: promo.Currency, TournamentID: promo.TournamentID}
+	row = tx.QueryRow("SELECT count(*), max(redeemed) FROM redemptions WHERE code=$1 AND playerId=$2", code, playerID)
+	var last sql.NullTime
+	err = row.Scan(&red.Use, &last)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Redemption{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "redeem promo: " + err.Error()}, err2)
+	}
+	// repeated redemption returns the last one, so retried request does not grant reward again
+	if red.Use >= promo.MaxPerPlayer {
+		red.Redeemed = last.Time.UTC()
+		return red, tx.Rollback()
+	}
+	err = checkPromo(tx, promo, validTo, playerID, now)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Redemption{}, errors.Join(err, err2)
+	}
+	if promo.Points > 0 {
+		_, err = fundTxPlayer(tx, playerID, promo.Currency, promo.Points, time.Time{})
+		if err != nil {
+			err2 := tx.Rollback()
+			return entity.Redemption{}, errors.Join(err, err2).SetPrefix("redeem promo: ")
+		}
+		err = logTx(tx, playerID, promo.Currency, opPromo, promo.Points, "")
+		if err != nil {
+			err2 := tx.Rollback()
+			return entity.Redemption{}, errors.Join(err, err2)
+		}
+	}
+	if promo.TournamentID != "" {
+		err = ensureTxPlayer(tx, playerID, promo.TournamentID)
+		if err != nil {
+			err2 := tx.Rollback()
+			return entity.Redemption{}, errors.Join(err, err2).SetPrefix("redeem promo: ")
+		}
+		err = updateTxParticipants(tx, promo.TournamentID, playerID, false)
+		if err != nil {
+			err2 := tx.Rollback()
+			return entity.Redemption{}, errors.Join(err, err2).SetPrefix("redeem promo: ")
+		}
+	}
+	red.Use++
+	red.Redeemed = now
+	_, err = tx.Exec("INSERT INTO redemptions (code, playerId, use, redeemed) values ($1, $2, $3, $4)", code, playerID, red.Use, now)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Redemption{}, errors.Join(errors.Error{Code: errors.DuplicatedIDError, Message: "redeem promo: promo is being redeemed by player, id " + playerID}, err2)
+	}
+	_, err = tx.Exec("UPDATE promos SET uses=uses+1 WHERE code=$1", code)
+	if err != nil {
+		err2 := tx.Rollback()
+		return entity.Redemption{}, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "redeem promo: " + err.Error()}, err2)
+	}
+	return red, tx.Commit()
+}
+
+// checkPromo returns error, if player cannot redeem promo now
+func checkPromo(tx *sql.Tx, promo entity.Promo, validTo sql.NullTime, playerID string, now time.Time) error {
+	if now.Before(promo.ValidFrom) || validTo.Valid && !now.Before(validTo.Time) {
+		return errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo is not valid now, code " + promo.Code}
+	}
+	if promo.MaxUses > 0 && promo.Uses >= promo.MaxUses {
+		return errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo has been used " + strconv.Itoa(promo.Uses) + " times, code " + promo.Code}
+	}
+	if promo.TournamentID != "" {
+		var isOpen bool
+		err := tx.QueryRow("SELECT isOpen FROM tournaments WHERE id=$1 FOR UPDATE", promo.TournamentID).Scan(&isOpen)
+		if err != nil {
+			return errors.Error{Code: errors.NotFoundError, Message: "redeem promo: ticket tournament does not exist, id " + promo.TournamentID}
+		}
+		if !isOpen {
+			return errors.Error{Code: errors.ClosedTournamentError, Message: "redeem promo: ticket tournament is closed, id " + promo.TournamentID}
+		}
+	}
+	if !promo.NewPlayersOnly {
+		return nil
+	}
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id=$1)", playerID).Scan(&exists)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "redeem promo: " + err.Error()}
+	}
+	if exists {
+		return errors.Error{Code: errors.PromoUnavailableError, Message: "redeem promo: promo is for new players only, id " + playerID}
+	}
+	return nil
+}
+
+// DeletePromo deletes promo with its redemptions
+func (p *Postgres) DeletePromo(code string) error {
+	res, err := p.db.Exec("DELETE FROM promos WHERE code=$1", code)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "delete promo: " + err.Error()}
+	}
+	return resultError(res, "delete promo: promo does not exist, code "+code)
+}
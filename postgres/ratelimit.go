@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
+)
+
+// TakeToken takes token from bucket of key, which is filled by rule. Bucket is locked, while token is taken,
+// so instances, which share database, take tokens from it one by one.
+func (p *Postgres) TakeToken(key string, rule ratelimit.Rule, now time.Time) (time.Duration, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, errors.Error{Code: errors.UnexpectedError, Message: "take token: failed to start transaction", Info: err.Error()}
+	}
+	_, err = tx.Exec("INSERT INTO rate_limits (key, tokens, updated) VALUES ($1, $2, $3) ON CONFLICT (key) DO NOTHING", key, rule.Burst, now)
+	if err != nil {
+		err2 := tx.Rollback()
+		return 0, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "take token: " + err.Error()}, err2)
+	}
+	var b ratelimit.Bucket
+	err = tx.QueryRow("SELECT tokens, updated FROM rate_limits WHERE key=$1 FOR UPDATE", key).Scan(&b.Tokens, &b.Updated)
+	if err != nil {
+		err2 := tx.Rollback()
+		return 0, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "take token: " + err.Error()}, err2)
+	}
+	b, wait := rule.Take(b, now)
+	_, err = tx.Exec("UPDATE rate_limits SET tokens=$2, updated=$3 WHERE key=$1", key, b.Tokens, b.Updated)
+	if err != nil {
+		err2 := tx.Rollback()
+		return 0, errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "take token: " + err.Error()}, err2)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, errors.Error{Code: errors.TransactionError, Message: "take token: failed to commit transaction", Info: err.Error()}
+	}
+	return wait, nil
+}
+
+// PruneBuckets deletes buckets, which were not updated since before
+func (p *Postgres) PruneBuckets(before time.Time) error {
+	_, err := p.db.Exec("DELETE FROM rate_limits WHERE updated < $1", before)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "prune buckets: " + err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// CreateTeam creates team with its members in one transaction
+func (p *Postgres) CreateTeam(team entity.Team) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "create team: failed to start transaction", Info: err.Error()}
+	}
+	_, err = tx.Exec("INSERT INTO teams (id, captain) values ($1, $2)", team.ID, team.Captain)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(errors.Error{Code: errors.DuplicatedIDError, Message: "create team: using duplicated id to create team, id " + team.ID}, err2)
+	}
+	for _, m := range team.Members {
+		_, err = tx.Exec("INSERT INTO team_members (teamId, playerId, share) values ($1, $2, $3)", team.ID, m.PlayerID, m.Share)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(errors.Error{Code: errors.UnexpectedError, Message: "create team: cannot add member, playerID: " + m.PlayerID, Info: err.Error()}, err2)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTeam returns team with its members
+func (p *Postgres) GetTeam(id string) (entity.Team, error) {
+	row := p.db.QueryRow("SELECT captain FROM teams WHERE id=$1", id)
+	team := entity.Team{ID: id}
+	err := row.Scan(&team.Captain)
+	if err != nil {
+		return entity.Team{}, errors.Error{Code: errors.NotFoundError, Message: "get team: cannot find team, id " + id}
+	}
+	rows, err := p.db.Query("SELECT playerId, share FROM team_members WHERE teamId=$1 ORDER BY playerId", id)
+	if err != nil {
+		return entity.Team{}, errors.Error{Code: errors.UnexpectedError, Message: "get team: " + err.Error()}
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m entity.TeamMember
+		err = rows.Scan(&m.PlayerID, &m.Share)
+		if err != nil {
+			return entity.Team{}, errors.Error{Code: errors.UnexpectedError, Message: "get team: " + err.Error()}
+		}
+		team.Members = append(team.Members, m)
+	}
+	if err = rows.Err(); err != nil {
+		return entity.Team{}, errors.Error{Code: errors.UnexpectedError, Message: "get team: " + err.Error()}
+	}
+	return team, nil
+}
+
+// DeleteTeam deletes team with its members
+func (p *Postgres) DeleteTeam(id string) error {
+	res, err := p.db.Exec("DELETE FROM teams WHERE id=$1", id)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "delete team: " + err.Error()}
+	}
+	return resultError(res, "delete team: team does not exist, id "+id)
+}
+
+// CreateTeamTournament creates tournament with id and deposit in currency, which only teams can join
+func (p *Postgres) CreateTeamTournament(id, currency string, deposit int) error {
+	res, err := p.db.Exec("INSERT INTO tournaments (id, currency, deposit, prize, isOpen, isTeam) values ($1, $2, $3, '0', 'true', 'true')", id, currency, deposit)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create team tournament: using duplicated id to create tournament, id: " + id}
+	}
+	return resultError(res, "create team tournament: cannot create tournament with id "+id)
+}
+
+// IsTeamTournament returns true, if only teams can join tournament
+func (p *Postgres) IsTeamTournament(id string) (bool, error) {
+	row := p.db.QueryRow("SELECT isTeam FROM tournaments WHERE id=$1", id)
+	var isTeam bool
+	err := row.Scan(&isTeam)
+	if err != nil {
+		return false, errors.Error{Code: errors.NotFoundError, Message: "is team tournament: cannot get tournament kind from not existing tournament, id: " + id}
+	}
+	return isTeam, nil
+}
+
+// GetTeams returns teams, which have joined tournament
+func (p *Postgres) GetTeams(tourID string) ([]string, error) {
+	row := p.db.QueryRow("SELECT teams FROM tournaments WHERE id=$1", tourID)
+	var teamIDs []string
+	err := row.Scan(pq.Array(&teamIDs))
+	if err != nil {
+		return nil, errors.Error{Code: errors.NotFoundError, Message: "get teams: cannot get teams from not existing tournament, id: " + tourID}
+	}
+	return teamIDs, nil
+}
+
+// UpdateTourAndTeam adds team into tournament and takes deposit from team members in one transaction
+func (p *Postgres) UpdateTourAndTeam(tourID, teamID string, captainPays bool) error {
+	team, err := p.GetTeam(teamID)
+	if err != nil {
+		return err
+	}
+	dep, err := p.GetDeposit(tourID)
+	if err != nil {
+		return err
+	}
+	currency, err := p.GetCurrency(tourID)
+	if err != nil {
+		return err
+	}
+	tx, err := p.db.Begin()
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "update tournament and team: failed to start transaction", Info: err.Error()}
+	}
+	res, err := tx.Exec("UPDATE tournaments SET teams=array_append(teams, $1), prize=prize+deposit WHERE id=$2 AND isTeam", teamID, tourID)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(err, err2)
+	}
+	err = resultError(res, "update teams: cannot update teams in not existing team tournament, id: "+tourID)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(err, err2)
+	}
+	payers, parts := []string{team.Captain}, []int{dep}
+	if !captainPays {
+		payers, parts = payers[:0], team.Split(dep)
+		for _, m := range team.Members {
+			payers = append(payers, m.PlayerID)
+		}
+	}
+	for i := range payers {
+		err = updateTxPlayer(tx, payers[i], currency, -1*parts[i])
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2)
+		}
+		err = logTx(tx, payers[i], currency, opDeposit, -1*parts[i], "")
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2)
+		}
+	}
+	paid := make(map[string]int, len(payers))
+	for i := range payers {
+		paid[payers[i]] = parts[i]
+	}
+	// members, who have not paid deposit, get entry too, their balance is created for it
+	for _, m := range team.Members {
+		err = ensureTxPlayer(tx, m.PlayerID, tourID)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2)
+		}
+		err = addTxMemberEntry(tx, tourID, m.PlayerID, teamID, paid[m.PlayerID])
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2)
+		}
+	}
+	return tx.Commit()
+}
+
+// SetTeamWinner splits tournament prize between winning team members in one transaction
+func (p *Postgres) SetTeamWinner(tourID, teamID string) error {
+	team, err := p.GetTeam(teamID)
+	if err != nil {
+		return err
+	}
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	row := tx.QueryRow("SELECT prize, currency FROM tournaments WHERE id=$1", tourID)
+	var (
+		prize    int
+		currency string
+	)
+	err = row.Scan(&prize, &currency)
+	if err != nil {
+		err2 := tx.Rollback()
+		return errors.Join(err, err2).SetPrefix("set team winner: tournament not exist, id: " + tourID + "\n").SetCode(errors.NotFoundError)
+	}
+	for i, part := range team.Split(prize) {
+		id := team.Members[i].PlayerID
+		winner, err := setTxMemberPrize(tx, id, currency, part)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set team winner: ")
+		}
+		winner.Team = teamID
+		err = addTxResult(tx, tourID, currency, 1, winner)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set team winner: ")
+		}
+	}
+	return tx.Commit()
+}
+
+// setTxMemberPrize pays prize to team member, member may not have balance in currency, if captain paid deposit
+func setTxMemberPrize(tx *sql.Tx, id, currency string, prize int) (entity.Winner, error) {
+	points, err := fundTxPlayer(tx, id, currency, prize, time.Time{})
+	if err != nil {
+		return entity.Winner{}, err
+	}
+	return entity.Winner{ID: id, Points: points, Prize: prize}, nil
+}
@@ -2,9 +2,6 @@ package postgres
 
 import (
 	"database/sql"
-	"encoding/json"
-
-	"github.com/lib/pq"
 
 	"github.com/dmitriyomelyusik/Tournament/entity"
 	"github.com/dmitriyomelyusik/Tournament/errors"
@@ -12,33 +9,133 @@ import (
 
 // CloseTournament closes tournament in transaction
 func (p *Postgres) CloseTournament(id string) error {
-	res, err := p.db.Exec("UPDATE tournaments SET isOpen='false' WHERE id=$1", id)
+	res, err := p.db.Exec("UPDATE tournaments SET isOpen='false', closed=now() WHERE id=$1", id)
 	if err != nil {
 		return err
 	}
 	return resultError(res, "close tournament: cannot close not existing tournament, id: "+id)
 }
 
-// CreateTournament creates tournament with id and deposit
-func (p *Postgres) CreateTournament(id string, deposit int) error {
-	res, err := p.db.Exec("INSERT INTO tournaments (id, deposit, prize, isOpen) values ($1, $2, '0', 'true')", id, deposit)
+// CreateTournament creates tournament with id, deposit in currency and max entries number of every player
+func (p *Postgres) CreateTournament(id, currency string, deposit, maxEntries int) error {
+	res, err := p.db.Exec("INSERT INTO tournaments (id, currency, deposit, prize, isOpen, maxEntries) values ($1, $2, $3, '0', 'true', $4)", id, currency, deposit, maxEntries)
 	if err != nil {
 		return errors.Error{Code: errors.DuplicatedIDError, Message: "create tournament: using duplicated id to create tournament, id: " + id}
 	}
 	return resultError(res, "create tournament: cannot create tournament with id "+id)
 }
 
-// GetParticipants returns tournament participants
+// CreateSatellite creates satellite tournament with id and deposit, which awards seats in target tournament.
+// Satellite deposit is paid in target tournament currency, so its prize can pay target deposits.
+func (p *Postgres) CreateSatellite(id string, deposit int, targetID string, seats int) error {
+	res, err := p.db.Exec(`INSERT INTO tournaments (id, currency, deposit, prize, isOpen, targetId, seats)
+		SELECT $1, currency, $2, '0', 'true', id, $4 FROM tournaments WHERE id=$3`, id, deposit, targetID, seats)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create satellite: using duplicated id to create tournament, id: " + id}
+	}
+	return resultError(res, "create satellite: cannot create tournament with not existing target, targetID: "+targetID)
+}
+
+// GetSatellite returns satellite settings of tournament, which are empty for regular tournament
+func (p *Postgres) GetSatellite(id string) (entity.Satellite, error) {
+	row := p.db.QueryRow("SELECT COALESCE(targetId, ''), COALESCE(seats, 0) FROM tournaments WHERE id=$1", id)
+	var sat entity.Satellite
+	err := row.Scan(&sat.TargetID, &sat.Seats)
+	if err != nil {
+		return entity.Satellite{}, errors.Error{Code: errors.NotFoundError, Message: "get satellite: cannot get satellite from not existing tournament, id: " + id}
+	}
+	return sat, nil
+}
+
+// GetParticipants returns tournament participants in order, they have joined
 func (p *Postgres) GetParticipants(id string) ([]string, error) {
-	row := p.db.QueryRow("SELECT participants FROM tournaments WHERE id=$1", id)
-	var playerIDs []string
-	err := row.Scan(pq.Array(&playerIDs))
+	rows, err := p.db.Query(`SELECT e.playerId FROM tournaments t LEFT JOIN tournament_entries e ON e.tournamentId=t.id AND e.teamId IS NULL
+		WHERE t.id=$1 GROUP BY e.playerId ORDER BY min(e.joined), e.playerId`, id)
 	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get participants: " + err.Error()}
+	}
+	defer rows.Close()
+	var (
+		playerIDs []string
+		found     bool
+	)
+	for rows.Next() {
+		found = true
+		var playerID sql.NullString
+		err = rows.Scan(&playerID)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "get participants: " + err.Error()}
+		}
+		if playerID.Valid {
+			playerIDs = append(playerIDs, playerID.String)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get participants: " + err.Error()}
+	}
+	if !found {
 		return nil, errors.Error{Code: errors.NotFoundError, Message: "get participants: cannot get participants from not existing tournament, id: " + id}
 	}
 	return playerIDs, nil
 }
 
+// GetEntries returns every tournament entry
+func (p *Postgres) GetEntries(id string) ([]entity.Entry, error) {
+	rows, err := p.db.Query(`SELECT e.playerId, e.entry FROM tournaments t LEFT JOIN tournament_entries e ON e.tournamentId=t.id AND e.teamId IS NULL
+		WHERE t.id=$1 ORDER BY e.entry, e.playerId`, id)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get entries: " + err.Error()}
+	}
+	defer rows.Close()
+	var (
+		entries []entity.Entry
+		found   bool
+	)
+	for rows.Next() {
+		found = true
+		var (
+			playerID sql.NullString
+			number   sql.NullInt64
+		)
+		err = rows.Scan(&playerID, &number)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "get entries: " + err.Error()}
+		}
+		if playerID.Valid {
+			entries = append(entries, entity.Entry{PlayerID: playerID.String, Number: int(number.Int64)})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "get entries: " + err.Error()}
+	}
+	if !found {
+		return nil, errors.Error{Code: errors.NotFoundError, Message: "get entries: cannot get entries from not existing tournament, id: " + id}
+	}
+	return entries, nil
+}
+
+// GetMaxEntries returns how many times every player can join tournament
+func (p *Postgres) GetMaxEntries(id string) (int, error) {
+	row := p.db.QueryRow("SELECT maxEntries FROM tournaments WHERE id=$1", id)
+	var maxEntries int
+	err := row.Scan(&maxEntries)
+	if err != nil {
+		return 0, errors.Error{Code: errors.NotFoundError, Message: "get max entries: cannot get max entries from not existing tournament, id: " + id}
+	}
+	return maxEntries, nil
+}
+
+// GetCurrency returns currency of tournament deposit and prize
+func (p *Postgres) GetCurrency(id string) (string, error) {
+	row := p.db.QueryRow("SELECT currency FROM tournaments WHERE id=$1", id)
+	var currency string
+	err := row.Scan(&currency)
+	if err != nil {
+		return "", errors.Error{Code: errors.NotFoundError, Message: "get currency: cannot get currency from not existing tournament, id: " + id}
+	}
+	return currency, nil
+}
+
 // GetTournamentState returns true, if tournament opens for joining
 func (p *Postgres) GetTournamentState(id string) (bool, error) {
 	row := p.db.QueryRow("SELECT isOpen FROM tournaments WHERE id=$1", id)
@@ -50,26 +147,57 @@ func (p *Postgres) GetTournamentState(id string) (bool, error) {
 	return isOpen, nil
 }
 
-// GetWinner returns tournament winner
+// GetWinner returns tournament winners in order, they have been placed
 func (p *Postgres) GetWinner(id string) (entity.Winners, error) {
-	row := p.db.QueryRow("SELECT winner FROM tournaments WHERE id=$1", id)
-	var rawWinner []byte
-	err := row.Scan(&rawWinner)
+	var exists bool
+	err := p.db.QueryRow("SELECT EXISTS(SELECT 1 FROM tournaments WHERE id=$1)", id).Scan(&exists)
 	if err != nil {
+		return entity.Winners{}, errors.Error{Code: errors.UnexpectedError, Message: "get winner: " + err.Error()}
+	}
+	if !exists {
 		return entity.Winners{}, errors.Error{Code: errors.NotFoundError, Message: "get winner: cannot get winner from not existing tournament, id: " + id}
 	}
-	var winner entity.Winner
-	err = json.Unmarshal(rawWinner, &winner)
+	winners, err := p.getResults(id)
 	if err != nil {
-		if rawWinner == nil {
-			return entity.Winners{}, errors.Error{Code: errors.NoneParticipantsError, Message: "get winner: tournaments has been ended without participant, cannot select winner, tourID: " + id}
-		}
-		return entity.Winners{}, errors.Error{Code: errors.JSONError, Message: "get winner: cannot unmarshal winner, id: " + winner.ID, Info: err.Error()}
+		return entity.Winners{}, err
+	}
+	if len(winners) == 0 {
+		return entity.Winners{}, errors.Error{Code: errors.NoneParticipantsError, Message: "get winner: tournaments has been ended without participant, cannot select winner, tourID: " + id}
+	}
+	return entity.Winners{Winners: winners}, nil
+}
+
+// GetTournament returns tournament with its entries and winners
+func (p *Postgres) GetTournament(id string) (entity.Tournament, error) {
+	t, err := scanTournament(p.db.QueryRow("SELECT "+tourColumns+" FROM tournaments WHERE id=$1", id))
+	if err == sql.ErrNoRows {
+		return entity.Tournament{}, errors.Error{Code: errors.NotFoundError, Message: "get tournament: cannot get not existing tournament, id: " + id}
 	}
-	return entity.Winners{Winners: []entity.Winner{winner}}, nil
+	if err != nil {
+		return entity.Tournament{}, errors.Error{Code: errors.UnexpectedError, Message: "get tournament: " + err.Error()}
+	}
+	t.Entries, err = p.GetEntries(id)
+	if err != nil {
+		return entity.Tournament{}, err
+	}
+	if t.Entries == nil {
+		t.Entries = []entity.Entry{}
+	}
+	winners, err := p.getResults(id)
+	if err != nil {
+		return entity.Tournament{}, err
+	}
+	switch {
+	case t.Satellite.TargetID != "" || t.IsTeam:
+		t.Winners = winners
+	case len(winners) > 0:
+		t.Winner = winners[0]
+	}
+	return t, nil
 }
 
-func (p *Postgres) getDeposit(id string) (int, error) {
+// GetDeposit returns tournament deposit
+func (p *Postgres) GetDeposit(id string) (int, error) {
 	row := p.db.QueryRow("SELECT deposit FROM tournaments WHERE id=$1", id)
 	var deposit int
 	err := row.Scan(&deposit)
@@ -85,43 +213,120 @@ func (p *Postgres) SetTournamentWinner(id string, winner entity.Winner) error {
 	if err != nil {
 		return err
 	}
-	row := tx.QueryRow("SELECT prize FROM tournaments WHERE id=$1", id)
-	var prize int
-	err = row.Scan(&prize)
+	row := tx.QueryRow("SELECT prize, currency FROM tournaments WHERE id=$1", id)
+	var (
+		prize    int
+		currency string
+	)
+	err = row.Scan(&prize, &currency)
 	if err != nil {
 		err2 := tx.Rollback()
 		return errors.Join(err, err2).SetPrefix("set winner: tournament not exist, id: " + id + "\n").SetCode(errors.NotFoundError)
 	}
-	err = updateTxPlayer(tx, winner.ID, prize)
+	err = updateTxPlayer(tx, winner.ID, currency, prize)
 	if err != nil {
 		err2 := tx.Rollback()
 		return errors.Join(err, err2).SetPrefix("set winner: ")
 	}
 	winner.Prize = prize
-	rawWinner, err := json.Marshal(winner)
+	err = addTxResult(tx, id, currency, 1, winner)
 	if err != nil {
 		err2 := tx.Rollback()
-		return errors.Join(err, err2).SetPrefix("set winner: cannot marshal player").SetCode(errors.JSONError)
+		return errors.Join(err, err2).SetPrefix("set winner: ")
 	}
-	res, err := tx.Exec("UPDATE tournaments SET winner=$1 WHERE id=$2", rawWinner, id)
+	return tx.Commit()
+}
+
+// SetSatelliteWinners registers first seats players from ranking into target tournament in one transaction.
+// Every seat is paid from satellite prize, so less seats are awarded if prize does not cover them.
+// Leftover points go to the next placed player or to the first one, if every player got a seat.
+func (p *Postgres) SetSatelliteWinners(id string, ranking []string, seats int) error {
+	tx, err := p.db.Begin()
 	if err != nil {
-		err2 := tx.Rollback()
-		return errors.Join(err, err2).SetPrefix("set winner: ")
+		return err
 	}
-	err = resultError(res, "set winner: cannot close not existing tournament, id: "+id)
+	row := tx.QueryRow("SELECT s.prize, t.id, t.deposit, t.currency FROM tournaments s JOIN tournaments t ON t.id=s.targetId WHERE s.id=$1", id)
+	var prize, deposit int
+	var targetID, currency string
+	err = row.Scan(&prize, &targetID, &deposit, &currency)
 	if err != nil {
 		err2 := tx.Rollback()
-		return errors.Join(err, err2).SetPrefix("set winner: ")
+		return errors.Join(err, err2).SetPrefix("set satellite winners: satellite or its target not exist, id: " + id + "\n").SetCode(errors.NotFoundError)
+	}
+	if seats > len(ranking) {
+		seats = len(ranking)
+	}
+	if seats > prize/deposit {
+		seats = prize / deposit
+	}
+	for i, playerID := range ranking[:seats] {
+		points, err := getTxPoints(tx, playerID, currency)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set satellite winners: ")
+		}
+		err = updateTxParticipants(tx, targetID, playerID, false)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set satellite winners: ")
+		}
+		err = addTxResult(tx, id, currency, i+1, entity.Winner{ID: playerID, Points: points, Prize: deposit, Seat: targetID})
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set satellite winners: ")
+		}
+	}
+	leftover := prize - seats*deposit
+	if leftover > 0 && len(ranking) > 0 {
+		next, placing := ranking[0], 1
+		if seats < len(ranking) {
+			next, placing = ranking[seats], seats+1
+		}
+		points, err := getTxPoints(tx, next, currency)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set satellite winners: ")
+		}
+		err = updateTxPlayer(tx, next, currency, leftover)
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set satellite winners: ")
+		}
+		err = addTxResult(tx, id, currency, placing, entity.Winner{ID: next, Points: points, Prize: leftover})
+		if err != nil {
+			err2 := tx.Rollback()
+			return errors.Join(err, err2).SetPrefix("set satellite winners: ")
+		}
 	}
 	return tx.Commit()
 }
 
-func updateTxParticipants(tx *sql.Tx, tourID, playerID string) error {
-	res, err := tx.Exec("UPDATE tournaments SET participants=array_append(participants, $1), prize=prize+deposit WHERE id=$2", playerID, tourID)
+// updateTxParticipants adds next player entry into tournament, deposit is added to prize.
+// Entry is paid by deposit, if pays is set, otherwise it is free ticket or seat.
+func updateTxParticipants(tx *sql.Tx, tourID, playerID string, pays bool) error {
+	res, err := tx.Exec("UPDATE tournaments SET prize=prize+deposit WHERE id=$1", tourID)
 	if err != nil {
 		return err
 	}
-	return resultError(res, "update participiants: cannot update participants in not existing tournament, id: "+tourID)
+	err = resultError(res, "update participiants: cannot update participants in not existing tournament, id: "+tourID)
+	if err != nil {
+		return err
+	}
+	res, err = tx.Exec(`INSERT INTO tournament_entries (tournamentId, playerId, currency, entry, paid)
+		SELECT t.id, $2, t.currency, count(e.entry)+1, CASE WHEN $3::boolean THEN t.deposit ELSE 0 END
+		FROM tournaments t LEFT JOIN tournament_entries e ON e.tournamentId=t.id AND e.playerId=$2 AND e.teamId IS NULL
+		WHERE t.id=$1 GROUP BY t.id HAVING count(e.entry) < t.maxEntries`, tourID, playerID, pays)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "update participiants: cannot add entry, playerID: " + playerID, Info: err.Error()}
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "update participiants: player has used all entries, playerID: " + playerID}
+	}
+	return nil
 }
 
 // DeleteTournament deletes tournament
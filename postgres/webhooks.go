@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// deliveryColumns are columns of delivery joined with its webhook
+const deliveryColumns = "d.id::text, d.webhookId, w.url, w.secret, d.event, d.attempts, d.nextAttempt, COALESCE(d.lastError, ''), d.dead"
+
+// CreateWebhook creates webhook
+func (p *Postgres) CreateWebhook(webhook entity.Webhook) error {
+	res, err := p.db.Exec("INSERT INTO webhooks (id, url, events, secret, created) values ($1, $2, $3, $4, $5)",
+		webhook.ID, webhook.URL, pq.Array(webhook.Events), webhook.Secret, webhook.Created)
+	if err != nil {
+		return errors.Error{Code: errors.DuplicatedIDError, Message: "create webhook: using duplicated id to create webhook, id " + webhook.ID}
+	}
+	return resultError(res, "create webhook: cannot create webhook, id "+webhook.ID)
+}
+
+// ListWebhooks returns every webhook ordered by id
+func (p *Postgres) ListWebhooks() ([]entity.Webhook, error) {
+	rows, err := p.db.Query("SELECT id, url, events, secret, created FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list webhooks: " + err.Error()}
+	}
+	defer rows.Close()
+	webhooks := []entity.Webhook{}
+	for rows.Next() {
+		var w entity.Webhook
+		err = rows.Scan(&w.ID, &w.URL, pq.Array(&w.Events), &w.Secret, &w.Created)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "list webhooks: " + err.Error()}
+		}
+		w.Created = w.Created.UTC()
+		webhooks = append(webhooks, w)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list webhooks: " + err.Error()}
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes webhook with its deliveries
+func (p *Postgres) DeleteWebhook(id string) error {
+	res, err := p.db.Exec("DELETE FROM webhooks WHERE id=$1", id)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "delete webhook: " + err.Error()}
+	}
+	return resultError(res, "delete webhook: webhook does not exist, id "+id)
+}
+
+// ClaimDeliveries returns up to limit deliveries, which are due at now, in order they are due. Their next attempt
+// is moved by lease, so other dispatchers skip them, while they are being delivered.
+func (p *Postgres) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.Delivery, error) {
+	rows, err := p.db.Query(`UPDATE webhook_deliveries d SET nextAttempt=$2 FROM webhooks w
+		WHERE w.id=d.webhookId AND d.id IN (SELECT id FROM webhook_deliveries WHERE NOT dead AND nextAttempt <= $1
+			ORDER BY nextAttempt, id LIMIT $3 FOR UPDATE SKIP LOCKED)
+		RETURNING `+deliveryColumns, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "claim deliveries: " + err.Error()}
+	}
+	return scanDeliveries(rows, "claim deliveries: ")
+}
+
+// CompleteDelivery deletes delivered delivery from outbox
+func (p *Postgres) CompleteDelivery(id string) error {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "complete delivery: delivery does not exist, id " + id}
+	}
+	res, err := p.db.Exec("DELETE FROM webhook_deliveries WHERE id=$1", n)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "complete delivery: " + err.Error()}
+	}
+	return resultError(res, "complete delivery: delivery does not exist, id "+id)
+}
+
+// FailDelivery saves failed attempt of delivery, its next attempt and whether it is dead
+func (p *Postgres) FailDelivery(delivery entity.Delivery) error {
+	n, err := strconv.ParseInt(delivery.ID, 10, 64)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "fail delivery: delivery does not exist, id " + delivery.ID}
+	}
+	res, err := p.db.Exec("UPDATE webhook_deliveries SET attempts=$2, nextAttempt=$3, lastError=NULLIF($4, ''), dead=$5 WHERE id=$1",
+		n, delivery.Attempts, delivery.NextAttempt, delivery.LastError, delivery.Dead)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "fail delivery: " + err.Error()}
+	}
+	return resultError(res, "fail delivery: delivery does not exist, id "+delivery.ID)
+}
+
+// ListDeadDeliveries returns dead deliveries in order they were written
+func (p *Postgres) ListDeadDeliveries() ([]entity.Delivery, error) {
+	rows, err := p.db.Query("SELECT " + deliveryColumns + " FROM webhook_deliveries d JOIN webhooks w ON w.id=d.webhookId WHERE d.dead ORDER BY d.id")
+	if err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: "list dead deliveries: " + err.Error()}
+	}
+	return scanDeliveries(rows, "list dead deliveries: ")
+}
+
+// RetryDelivery returns dead delivery into outbox, it is due now
+func (p *Postgres) RetryDelivery(id string) error {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return errors.Error{Code: errors.NotFoundError, Message: "retry delivery: dead delivery does not exist, id " + id}
+	}
+	res, err := p.db.Exec("UPDATE webhook_deliveries SET dead='false', attempts=0, nextAttempt=now() WHERE id=$1 AND dead", n)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "retry delivery: " + err.Error()}
+	}
+	return resultError(res, "retry delivery: dead delivery does not exist, id "+id)
+}
+
+func scanDeliveries(rows *sql.Rows, prefix string) ([]entity.Delivery, error) {
+	defer rows.Close()
+	deliveries := []entity.Delivery{}
+	for rows.Next() {
+		var (
+			d     entity.Delivery
+			event []byte
+		)
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Secret, &event, &d.Attempts, &d.NextAttempt, &d.LastError, &d.Dead)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: prefix + err.Error()}
+		}
+		err = json.Unmarshal(event, &d.Event)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: prefix + "cannot read event, id " + d.ID, Info: err.Error()}
+		}
+		d.NextAttempt = d.NextAttempt.UTC()
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Error{Code: errors.UnexpectedError, Message: prefix + err.Error()}
+	}
+	return deliveries, nil
+}
+
+// outboxTx writes delivery of event to every webhook, which gets events of its type
+func outboxTx(tx *sql.Tx, e entity.Event) error {
+	e.Created = time.Now().UTC()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "outbox: cannot write event " + e.Type, Info: err.Error()}
+	}
+	_, err = tx.Exec("INSERT INTO webhook_deliveries (webhookId, event) SELECT id, $1 FROM webhooks WHERE $2=ANY(events)", string(data), e.Type)
+	if err != nil {
+		return errors.Error{Code: errors.UnexpectedError, Message: "outbox: cannot write event " + e.Type, Info: err.Error()}
+	}
+	return nil
+}
+
+// balanceTx writes change of player balance in currency by points into outbox
+func balanceTx(tx *sql.Tx, id, currency string, points int) error {
+	if points == 0 {
+		return nil
+	}
+	return outboxTx(tx, entity.Event{Type: entity.EventBalanceChanged, PlayerID: id, Currency: currency, Points: points})
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Memory keeps buckets in memory of instance, so limits hold for every instance separately
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]Bucket
+}
+
+// NewMemory returns empty memory store
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]Bucket)}
+}
+
+// TakeToken takes token from bucket of key, which is filled by rule
+func (m *Memory) TakeToken(key string, rule Rule, now time.Time) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, wait := rule.Take(m.buckets[key], now)
+	m.buckets[key] = b
+	return wait, nil
+}
+
+// PruneBuckets deletes buckets, which were not updated since before
+func (m *Memory) PruneBuckets(before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, b := range m.buckets {
+		if b.Updated.Before(before) {
+			delete(m.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+// Package ratelimit limits rate of requests by token buckets. Every client has bucket in every route class, bucket is
+// filled with rate of rule of class up to its burst, and request takes one token. Buckets are kept in memory of instance
+// or in database, which is shared by instances, so limits hold across them.
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// DefaultClass is class, which rule is used for classes without own rule
+const DefaultClass = "default"
+
+// Block of classes of routes and methods, every class has its own rate limit
+const (
+	ClassJoin    = "join"
+	ClassResults = "results"
+	ClassWrite   = "write"
+	ClassRead    = "read"
+	// ClassIP limits every request of IP address before it is authenticated. It is limited only by own rule, not by default
+	// rule, because clients behind one NAT or proxy share address.
+	ClassIP = "ip"
+)
+
+// Rule is rule of bucket, it is filled with rate tokens per second and holds burst tokens at most
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// Bucket is state of token bucket, bucket, which was never updated, is full
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Take fills bucket for time since it was updated and takes token from it. Wait is zero, if token is taken,
+// otherwise it is time until bucket has token. Bucket is not updated back, if now is before its update.
+func (r Rule) Take(b Bucket, now time.Time) (Bucket, time.Duration) {
+	switch {
+	case b.Updated.IsZero():
+		b.Tokens, b.Updated = float64(r.Burst), now
+	case now.After(b.Updated):
+		b.Tokens = math.Min(float64(r.Burst), b.Tokens+now.Sub(b.Updated).Seconds()*r.Rate)
+		b.Updated = now
+	}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return b, 0
+	}
+	return b, time.Duration(math.Ceil((1 - b.Tokens) / r.Rate * float64(time.Second)))
+}
+
+// Refill returns time, which empty bucket needs to become full
+func (r Rule) Refill() time.Duration {
+	return time.Duration(math.Ceil(float64(r.Burst) / r.Rate * float64(time.Second)))
+}
+
+// Store is an interface for storage of buckets
+type Store interface {
+	// TakeToken takes token from bucket of key, which is filled by rule. Wait is zero, if token is taken,
+	// otherwise it is time until bucket has token.
+	TakeToken(key string, rule Rule, now time.Time) (time.Duration, error)
+	// PruneBuckets deletes buckets, which were not updated since before
+	PruneBuckets(before time.Time) error
+}
+
+// Limiter takes tokens of clients from buckets of route classes by rules of classes. Classes without rule and
+// without default rule and ip class without own rule are not limited.
+type Limiter struct {
+	Store Store
+	Rules map[string]Rule
+}
+
+// Take takes token of client key from bucket of class, wait is zero, if request is let in,
+// otherwise it is time until client can send request of class again
+func (l Limiter) Take(key, class string) (time.Duration, error) {
+	rule, ok := l.Rules[class]
+	if !ok && class != ClassIP {
+		rule, ok = l.Rules[DefaultClass]
+	}
+	if !ok {
+		return 0, nil
+	}
+	return l.Store.TakeToken(class+":"+key, rule, time.Now())
+}
+
+// Run prunes buckets every interval until stop is closed. Bucket is pruned, when it has been full for sure,
+// so pruning does not change limits.
+func (l Limiter) Run(interval time.Duration, stop <-chan struct{}) {
+	var idle time.Duration
+	for _, rule := range l.Rules {
+		if refill := rule.Refill(); refill > idle {
+			idle = refill
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			err := l.Store.PruneBuckets(now.Add(-idle))
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+// ParseRules parses rules of classes from list of class=rate:burst, which are separated by commas,
+// rate is number of tokens per second
+func ParseRules(s string) (map[string]Rule, error) {
+	rules := make(map[string]Rule)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		class, value, ok := strings.Cut(item, "=")
+		rate, burst, ok2 := strings.Cut(value, ":")
+		if !ok || !ok2 || class == "" {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "parse rate limits: rule is not class=rate:burst", Info: item}
+		}
+		r, err := strconv.ParseFloat(rate, 64)
+		if err != nil || !(r > 0) || math.IsInf(r, 0) {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "parse rate limits: rate must be positive number, class: " + class, Info: rate}
+		}
+		b, err := strconv.Atoi(burst)
+		if err != nil || b < 1 {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "parse rate limits: burst must be positive integer, class: " + class, Info: burst}
+		}
+		rules[class] = Rule{Rate: r, Burst: b}
+	}
+	return rules, nil
+}
+
+// Proxies are networks of trusted proxies, requests from them are limited by address of client, which they forward
+type Proxies []*net.IPNet
+
+// ParseProxies parses networks of trusted proxies from list of IP addresses or CIDR networks, which are separated by commas
+func ParseProxies(s string) (Proxies, error) {
+	var proxies Proxies
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, errors.Error{Code: errors.UnexpectedError, Message: "parse trusted proxies: proxy is not IP address or network", Info: item}
+			}
+			bits := 8 * len(ip.To4())
+			if bits == 0 {
+				bits = 8 * net.IPv6len
+			}
+			item += "/" + strconv.Itoa(bits)
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, errors.Error{Code: errors.UnexpectedError, Message: "parse trusted proxies: proxy is not IP address or network", Info: item}
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// trusts returns whether address is address of trusted proxy
+func (p Proxies) trusts(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns IP address of client, whose request comes from remote address. If remote address is trusted proxy,
+// it is the last address of X-Forwarded-For values, which is not trusted proxy, addresses before it could be forged by client.
+func (p Proxies) ClientIP(remote string, forwarded []string) string {
+	if !p.trusts(remote) {
+		return remote
+	}
+	addrs := strings.Split(strings.Join(forwarded, ","), ",")
+	for i := len(addrs) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(addrs[i])
+		if net.ParseIP(addr) == nil {
+			break
+		}
+		remote = addr
+		if !p.trusts(addr) {
+			break
+		}
+	}
+	return remote
+}
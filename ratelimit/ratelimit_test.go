@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+func TestRule_Take(t *testing.T) {
+	rule := Rule{Rate: 2, Burst: 3}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tt := []struct {
+		name           string
+		bucket         Bucket
+		now            time.Time
+		expectedBucket Bucket
+		expectedWait   time.Duration
+	}{
+		{name: "new bucket is full", now: now, expectedBucket: Bucket{Tokens: 2, Updated: now}},
+		{name: "bucket has token", bucket: Bucket{Tokens: 1.5, Updated: now}, now: now, expectedBucket: Bucket{Tokens: 0.5, Updated: now}},
+		{name: "empty bucket", bucket: Bucket{Tokens: 0.5, Updated: now}, now: now, expectedBucket: Bucket{Tokens: 0.5, Updated: now}, expectedWait: 250 * time.Millisecond},
+		{name: "bucket is filled", bucket: Bucket{Tokens: 0, Updated: now}, now: now.Add(time.Second), expectedBucket: Bucket{Tokens: 1, Updated: now.Add(time.Second)}},
+		{name: "bucket holds burst", bucket: Bucket{Tokens: 0, Updated: now}, now: now.Add(time.Hour), expectedBucket: Bucket{Tokens: 2, Updated: now.Add(time.Hour)}},
+		{name: "time before update", bucket: Bucket{Tokens: 0, Updated: now}, now: now.Add(-time.Second), expectedBucket: Bucket{Tokens: 0, Updated: now}, expectedWait: 500 * time.Millisecond},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, wait := rule.Take(tc.bucket, tc.now)
+			assert.Equal(t, tc.expectedBucket, b)
+			assert.Equal(t, tc.expectedWait, wait)
+		})
+	}
+	assert.Equal(t, 1500*time.Millisecond, rule.Refill())
+}
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	rule := Rule{Rate: 1, Burst: 2}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, expected := range []time.Duration{0, 0, time.Second} {
+		wait, err := m.TakeToken("key", rule, now)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, wait)
+	}
+	// other key has own bucket
+	wait, err := m.TakeToken("other", rule, now.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+	wait, err = m.TakeToken("key", rule, now.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+
+	assert.Nil(t, m.PruneBuckets(now.Add(time.Second)))
+	assert.Len(t, m.buckets, 2)
+	assert.Nil(t, m.PruneBuckets(now.Add(2*time.Second)))
+	assert.Len(t, m.buckets, 0)
+}
+
+func TestLimiter_Take(t *testing.T) {
+	l := Limiter{Store: NewMemory(), Rules: map[string]Rule{"join": {Rate: 1, Burst: 1}, DefaultClass: {Rate: 1, Burst: 2}}}
+	for i, expected := range []bool{true, false} {
+		wait, err := l.Take("client", "join")
+		assert.Nil(t, err)
+		assert.Equal(t, expected, wait == 0, "join request %v", i)
+	}
+	// default rule is used for class without rule, classes have separate buckets
+	for i, expected := range []bool{true, true, false} {
+		wait, err := l.Take("client", "write")
+		assert.Nil(t, err)
+		assert.Equal(t, expected, wait == 0, "write request %v", i)
+	}
+
+	// ip class is not limited by default rule
+	for i := 0; i < 10; i++ {
+		wait, err := l.Take("ip:10.0.0.1", ClassIP)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Duration(0), wait)
+	}
+
+	l = Limiter{Store: NewMemory(), Rules: map[string]Rule{"join": {Rate: 1, Burst: 1}}}
+	for i := 0; i < 10; i++ {
+		wait, err := l.Take("client", "read")
+		assert.Nil(t, err)
+		assert.Equal(t, time.Duration(0), wait)
+	}
+}
+
+func TestProxies_ClientIP(t *testing.T) {
+	proxies, err := ParseProxies("10.0.0.0/8, 192.168.1.1,::1")
+	assert.Nil(t, err)
+	tt := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		expected  string
+	}{
+		{name: "not proxy", remote: "203.0.113.1", forwarded: []string{"198.51.100.1"}, expected: "203.0.113.1"},
+		{name: "proxy", remote: "10.1.2.3", forwarded: []string{"198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "forged address", remote: "10.1.2.3", forwarded: []string{"198.51.100.2, 198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "several proxies", remote: "::1", forwarded: []string{"198.51.100.1, 192.168.1.1", "10.0.0.2"}, expected: "198.51.100.1"},
+		{name: "only proxies", remote: "10.1.2.3", forwarded: []string{"10.0.0.2"}, expected: "10.0.0.2"},
+		{name: "without header", remote: "192.168.1.1", expected: "192.168.1.1"},
+		{name: "not address", remote: "10.1.2.3", forwarded: []string{"198.51.100.1, unknown"}, expected: "10.1.2.3"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, proxies.ClientIP(tc.remote, tc.forwarded))
+		})
+	}
+}
+
+func TestParseProxies(t *testing.T) {
+	tt := []struct {
+		name          string
+		proxies       string
+		expected      int
+		expectedError bool
+	}{
+		{name: "empty", proxies: "", expected: 0},
+		{name: "proxies", proxies: "10.0.0.0/8, 192.168.1.1,::1,", expected: 3},
+		{name: "invalid network", proxies: "10.0.0.0/33", expectedError: true},
+		{name: "not address", proxies: "proxy", expectedError: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			proxies, err := ParseProxies(tc.proxies)
+			if tc.expectedError {
+				if assert.NotNil(t, err) {
+					assert.Equal(t, errors.UnexpectedError, errors.Transform(err).Code)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.Len(t, proxies, tc.expected)
+		})
+	}
+}
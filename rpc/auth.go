@@ -0,0 +1,196 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/dmitriyomelyusik/Tournament/auth"
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
+)
+
+// Block of metadata keys of API key, bearer token of player, time in seconds until call can be made again
+// and addresses, which proxies forward call from
+const (
+	APIKeyMetadata        = "x-api-key"
+	AuthorizationMetadata = "authorization"
+	RetryAfterMetadata    = "retry-after"
+	ForwardedForMetadata  = "x-forwarded-for"
+)
+
+// authenticator returns API key of key
+type authenticator interface {
+	Authenticate(key string) (entity.APIKey, error)
+}
+
+// tokenVerifier returns player principal of bearer token
+type tokenVerifier interface {
+	Verify(token string) (auth.Principal, error)
+}
+
+// rateLimiter takes token of client key from bucket of method class, wait is zero, if call is let in,
+// otherwise it is time until client can make call of class again
+type rateLimiter interface {
+	Take(key, class string) (time.Duration, error)
+}
+
+// readMethods only read, read-only role can use them
+var readMethods = map[string]bool{
+	TournamentService_GetBalance_FullMethodName: true, TournamentService_GetAccount_FullMethodName: true,
+	TournamentService_ListPlayers_FullMethodName: true, TournamentService_GetHistory_FullMethodName: true,
+	TournamentService_GetTournament_FullMethodName: true, TournamentService_ListTournaments_FullMethodName: true,
+}
+
+// clientMethods join tournaments, client role can use them and read methods. Other methods are admin methods.
+var clientMethods = map[string]bool{TournamentService_JoinTournament_FullMethodName: true}
+
+// playerMethods are methods, which player with token can use, they let player use only own id
+var playerMethods = map[string]bool{
+	TournamentService_GetBalance_FullMethodName: true, TournamentService_GetHistory_FullMethodName: true,
+	TournamentService_GetTournament_FullMethodName: true, TournamentService_ListTournaments_FullMethodName: true,
+	TournamentService_JoinTournament_FullMethodName: true,
+}
+
+// methodRole returns role, which method needs. Methods, which are not listed, need admin.
+func methodRole(method string) string {
+	switch {
+	case readMethods[method]:
+		return entity.RoleReadOnly
+	case clientMethods[method]:
+		return entity.RoleClient
+	}
+	return entity.RoleAdmin
+}
+
+// methodClass returns rate limit class of method
+func methodClass(method string) string {
+	switch {
+	case method == TournamentService_JoinTournament_FullMethodName:
+		return ratelimit.ClassJoin
+	case method == TournamentService_Results_FullMethodName:
+		return ratelimit.ClassResults
+	case readMethods[method]:
+		return ratelimit.ClassRead
+	}
+	return ratelimit.ClassWrite
+}
+
+// Authenticate is unary interceptor, which lets call in, if its API key has role, which method needs, or if its bearer
+// token is valid and method is player method, the same way HTTP routes are authenticated. Principal of call is put into
+// its context. Calls without valid key or token get Unauthenticated code, calls, which cannot use method, PermissionDenied.
+// Calls without token are let in without API key, if API keys are not authenticated.
+func (s Server) Authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	role := methodRole(info.FullMethod)
+	if token := auth.BearerToken(firstMetadata(ctx, AuthorizationMetadata)); token != "" && s.Tokens != nil {
+		p, err := s.Tokens.Verify(token)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		if !playerMethods[info.FullMethod] {
+			return nil, toStatus(errors.Error{Code: errors.ForbiddenError, Message: "authorize: player cannot use " + info.FullMethod + ", id: " + p.PlayerID, Info: role})
+		}
+		return handler(auth.NewContext(ctx, p), req)
+	}
+	if s.Auth == nil {
+		return handler(ctx, req)
+	}
+	key, err := s.Auth.Authenticate(firstMetadata(ctx, APIKeyMetadata))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if !auth.Allows(key.Role, role) {
+		return nil, toStatus(errors.Error{Code: errors.ForbiddenError, Message: "authorize: role " + key.Role + " cannot use " + info.FullMethod + ", id: " + key.ID, Info: role})
+	}
+	return handler(auth.NewContext(ctx, auth.Principal{Role: key.Role, KeyID: key.ID}), req)
+}
+
+// LimitRate is unary interceptor, which lets call in, if its client has not exceeded rate limit of method class. Call over
+// limit gets ResourceExhausted code and retry-after header in seconds. Call is let in, if limiter fails.
+func (s Server) LimitRate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := s.limit(ctx, s.clientKey(ctx), methodClass(info.FullMethod))
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// LimitIP is unary interceptor, which limits rate of every call of IP address before it is authenticated,
+// so calls with invalid API keys or tokens are limited too. Calls are limited only if ip class has own rule.
+func (s Server) LimitIP(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := s.limit(ctx, s.ipKey(ctx), ratelimit.ClassIP)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// limit takes token of client key from bucket of class, it returns status error, if call is over limit
+func (s Server) limit(ctx context.Context, key, class string) error {
+	wait, err := s.RateLimit.Take(key, class)
+	if err != nil {
+		log.Println(err)
+	}
+	if err != nil || wait <= 0 {
+		return nil
+	}
+	retry := int(math.Ceil(wait.Seconds()))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterMetadata, strconv.Itoa(retry)))
+	return toStatus(errors.Error{Code: errors.RateLimitError, Message: "rate limit: too many " + class + " requests, id: " + key, Info: retry})
+}
+
+// Interceptors returns unary interceptors of server, which are set, in order: IP rate limit, authentication
+// and client rate limit
+func (s Server) Interceptors() []grpc.UnaryServerInterceptor {
+	var interceptors []grpc.UnaryServerInterceptor
+	if s.RateLimit != nil {
+		interceptors = append(interceptors, s.LimitIP)
+	}
+	if s.Auth != nil || s.Tokens != nil {
+		interceptors = append(interceptors, s.Authenticate)
+	}
+	if s.RateLimit != nil {
+		interceptors = append(interceptors, s.LimitRate)
+	}
+	return interceptors
+}
+
+// clientKey returns key of client, who has made call: id of API key, id of player with token or IP address
+func (s Server) clientKey(ctx context.Context) string {
+	p, _ := auth.FromContext(ctx)
+	if key := p.Key(); key != "" {
+		return key
+	}
+	return s.ipKey(ctx)
+}
+
+// ipKey returns key of IP address, which call is made from, address of call from trusted proxy is forwarded one
+func (s Server) ipKey(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return "ip:"
+	}
+	host, _, err := net.SplitHostPort(pr.Addr.String())
+	if err != nil {
+		host = pr.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return "ip:" + s.TrustedProxies.ClientIP(host, md.Get(ForwardedForMetadata))
+}
+
+// firstMetadata returns first value of metadata key of incoming call
+func firstMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
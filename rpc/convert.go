@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+)
+
+// errorDomain is domain of error info details
+const errorDomain = "tournament"
+
+// Code returns gRPC code of error code: invalid requests get InvalidArgument, missing resources NotFound,
+// duplicated ids AlreadyExists, requests, which conflict with current state, FailedPrecondition, requests without valid
+// API key Unauthenticated, requests over player limits, of inactive players or without needed role PermissionDenied,
+// requests over rate limit ResourceExhausted, unavailable database Unavailable
+func Code(code errors.ErrCode) codes.Code {
+	switch code {
+	case errors.JSONError, errors.NotNumberError, errors.InvalidFilterError, errors.NegativePointsNumberError, errors.NegativeDepositError, errors.NegativeSeatsError, errors.NegativeEntriesError, errors.NegativeTTLError, errors.InvalidSplitError, errors.InvalidPromoError, errors.InvalidWebhookError, errors.InvalidBatchError, errors.InvalidRoleError:
+		return codes.InvalidArgument
+	case errors.NotFoundError:
+		return codes.NotFound
+	case errors.DuplicatedIDError:
+		return codes.AlreadyExists
+	case errors.ClosedTournamentError, errors.TeamTournamentError, errors.PromoUnavailableError, errors.OpenEntriesError, errors.NoneParticipantsError:
+		return codes.FailedPrecondition
+	case errors.UnauthorizedError:
+		return codes.Unauthenticated
+	case errors.LimitExceededError, errors.SelfExcludedError, errors.InactiveAccountError, errors.ForbiddenError:
+		return codes.PermissionDenied
+	case errors.RateLimitError:
+		return codes.ResourceExhausted
+	case errors.DatabaseOpenError, errors.DatabaseCreatingError, errors.DatabasePingError, errors.ConnectionError, errors.TransactionError:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus returns status error with code of error, error code and info are sent in error info details
+func toStatus(err error) error {
+	myErr := errors.Transform(err)
+	info := &errdetails.ErrorInfo{Reason: string(myErr.Code), Domain: errorDomain}
+	if myErr.Info != nil {
+		info.Metadata = map[string]string{"info": fmt.Sprint(myErr.Info)}
+	}
+	st, detailsErr := status.New(Code(myErr.Code), myErr.Message).WithDetails(info)
+	if detailsErr != nil {
+		return status.Error(Code(myErr.Code), myErr.Message)
+	}
+	return st.Err()
+}
+
+// toTimestamp returns nil for zero time, which means time is not set
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toPlayer(p entity.Player) *Player {
+	return &Player{Id: p.ID, Points: int64(p.Points), Currency: p.Currency}
+}
+
+func toBalance(b entity.Balance) *Balance {
+	res := &Balance{Id: b.ID, Points: int64(b.Points), Currency: b.Currency, Available: int64(b.Available)}
+	for _, h := range b.Holds {
+		res.Holds = append(res.Holds, &Hold{Id: h.ID, PlayerId: h.PlayerID, Points: int64(h.Points), Currency: h.Currency, Expires: toTimestamp(h.Expires)})
+	}
+	for _, l := range b.Expiring {
+		res.Expiring = append(res.Expiring, &Lot{Id: l.ID, PlayerId: l.PlayerID, Currency: l.Currency, Points: int64(l.Points),
+			Created: toTimestamp(l.Created), Expires: toTimestamp(l.Expires)})
+	}
+	return res
+}
+
+func toAccount(a entity.Account) *Account {
+	return &Account{Id: a.ID, Name: a.Name, Created: toTimestamp(a.Created), Metadata: a.Metadata, Status: a.Status}
+}
+
+func toClosure(c entity.Closure) *Closure {
+	res := &Closure{PlayerId: c.PlayerID, Withdrawn: c.Withdrawn}
+	for _, p := range c.Payouts {
+		res.Payouts = append(res.Payouts, toPlayer(p))
+	}
+	return res
+}
+
+func toHistory(h entity.History) *History {
+	res := &History{PlayerId: h.PlayerID}
+	for _, p := range h.Tournaments {
+		res.Tournaments = append(res.Tournaments, &Participation{TournamentId: p.TournamentID, PlayerId: p.PlayerID, Currency: p.Currency, IsOpen: p.IsOpen,
+			Paid: int64(p.Paid), Placing: int64(p.Placing), Prize: int64(p.Prize), Joined: toTimestamp(p.Joined)})
+	}
+	return res
+}
+
+func toWinner(w entity.Winner) *Winner {
+	return &Winner{Id: w.ID, Points: int64(w.Points), Prize: int64(w.Prize), Seat: w.Seat, Entry: int64(w.Entry), Team: w.Team}
+}
+
+func toTournament(t entity.Tournament) *Tournament {
+	res := &Tournament{
+		Id:           t.ID,
+		Deposit:      int64(t.Deposit),
+		Currency:     t.Currency,
+		Prize:        int64(t.Prize),
+		Participants: t.Participants,
+		IsOpen:       t.IsOpen,
+		MaxEntries:   int64(t.MaxEntries),
+		IsTeam:       t.IsTeam,
+		Teams:        t.Teams,
+		Created:      toTimestamp(t.Created),
+	}
+	if t.Winner != (entity.Winner{}) {
+		res.Winner = toWinner(t.Winner)
+	}
+	for _, w := range t.Winners {
+		res.Winners = append(res.Winners, toWinner(w))
+	}
+	if t.Satellite != (entity.Satellite{}) {
+		res.Satellite = &Satellite{TargetId: t.Satellite.TargetID, Seats: int64(t.Satellite.Seats)}
+	}
+	for _, e := range t.Entries {
+		res.Entries = append(res.Entries, &Entry{PlayerId: e.PlayerID, Number: int64(e.Number)})
+	}
+	if t.Closed != nil {
+		res.Closed = timestamppb.New(*t.Closed)
+	}
+	return res
+}
@@ -0,0 +1,254 @@
+// Package rpc serves gRPC API of tournament service, it uses the same controller methods as HTTP handlers
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tournament.proto
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dmitriyomelyusik/Tournament/auth"
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
+)
+
+type ctlr interface {
+	Fund(id, currency string, points int, expiresIn time.Duration) (entity.Player, error)
+	Take(id, currency string, points int) error
+	Transfer(from, to, currency string, points int) error
+	Balance(id, currency string) (entity.Balance, error)
+	AnnounceTournament(id, currency string, deposit, maxEntries int) error
+	AnnounceSatellite(id string, deposit int, targetID string, seats int) error
+	AnnounceTeamTournament(id, currency string, deposit int) error
+	JoinTournament(tourID, playerID string) error
+	JoinTeam(tourID, teamID string, captainPays bool) error
+	Results(tourID string) (entity.Winners, error)
+	Register(id, name string, metadata map[string]string) (entity.Account, error)
+	Account(id string) (entity.Account, error)
+	Suspend(id string) (entity.Account, error)
+	Reinstate(id string) (entity.Account, error)
+	CloseAccount(id string, withdraw bool) (entity.Closure, error)
+	ListPlayers(filter entity.PlayerFilter, cursor string) (entity.PlayerPage, error)
+	ListTournaments(filter entity.TourFilter, cursor string) (entity.TourPage, error)
+	Tournament(id string) (entity.Tournament, error)
+	History(playerID string) (entity.History, error)
+}
+
+// Server uses controller in handling gRPC methods
+type Server struct {
+	UnimplementedTournamentServiceServer
+	Controller ctlr
+	// Auth authenticates API keys, every method needs key with role, methods are open without it
+	Auth authenticator
+	// Tokens verifies bearer tokens of players, player can use only player methods and only with own id
+	Tokens tokenVerifier
+	// RateLimit limits rate of calls of every API key, player or IP address in every method class, calls are not limited without it
+	RateLimit rateLimiter
+	// TrustedProxies are proxies, calls from which are limited by client address of their x-forwarded-for metadata
+	TrustedProxies ratelimit.Proxies
+}
+
+// Fund funds player, new player is returned with created set
+func (s Server) Fund(ctx context.Context, req *FundRequest) (*FundResponse, error) {
+	player, err := s.Controller.Fund(req.PlayerId, req.Currency, int(req.Points), time.Duration(req.ExpireDays)*24*time.Hour)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if player == (entity.Player{}) {
+		return &FundResponse{}, nil
+	}
+	return &FundResponse{Created: true, Player: toPlayer(player)}, nil
+}
+
+// Take takes points from player
+func (s Server) Take(ctx context.Context, req *TakeRequest) (*emptypb.Empty, error) {
+	err := s.Controller.Take(req.PlayerId, req.Currency, int(req.Points))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Transfer transfers points between players
+func (s Server) Transfer(ctx context.Context, req *TransferRequest) (*emptypb.Empty, error) {
+	err := s.Controller.Transfer(req.From, req.To, req.Currency, int(req.Points))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetBalance returns player balance
+func (s Server) GetBalance(ctx context.Context, req *BalanceRequest) (*Balance, error) {
+	err := auth.CheckPlayer(ctx, req.PlayerId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	balance, err := s.Controller.Balance(req.PlayerId, req.Currency)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toBalance(balance), nil
+}
+
+// Register registers player account
+func (s Server) Register(ctx context.Context, req *RegisterRequest) (*Account, error) {
+	account, err := s.Controller.Register(req.PlayerId, req.Name, req.Metadata)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(account), nil
+}
+
+// GetAccount returns player account
+func (s Server) GetAccount(ctx context.Context, req *PlayerRequest) (*Account, error) {
+	return s.account(s.Controller.Account, req.PlayerId)
+}
+
+// Suspend suspends player account
+func (s Server) Suspend(ctx context.Context, req *PlayerRequest) (*Account, error) {
+	return s.account(s.Controller.Suspend, req.PlayerId)
+}
+
+// Reinstate reinstates player account
+func (s Server) Reinstate(ctx context.Context, req *PlayerRequest) (*Account, error) {
+	return s.account(s.Controller.Reinstate, req.PlayerId)
+}
+
+func (s Server) account(action func(id string) (entity.Account, error), id string) (*Account, error) {
+	account, err := action(id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(account), nil
+}
+
+// CloseAccount closes account, with withdraw player is withdrawn from open tournaments
+func (s Server) CloseAccount(ctx context.Context, req *CloseRequest) (*Closure, error) {
+	closure, err := s.Controller.CloseAccount(req.PlayerId, req.Withdraw)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toClosure(closure), nil
+}
+
+// ListPlayers returns page of registered players
+func (s Server) ListPlayers(ctx context.Context, req *ListPlayersRequest) (*PlayerPage, error) {
+	filter := entity.PlayerFilter{
+		Status:      req.Status,
+		CreatedFrom: fromTimestamp(req.CreatedFrom),
+		CreatedTo:   fromTimestamp(req.CreatedTo),
+		Sort:        req.Sort,
+		Desc:        req.Desc,
+		Limit:       int(req.Limit),
+	}
+	page, err := s.Controller.ListPlayers(filter, req.Cursor)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &PlayerPage{Next: page.Next}
+	for _, a := range page.Players {
+		res.Players = append(res.Players, toAccount(a))
+	}
+	return res, nil
+}
+
+// GetHistory returns tournaments, which player has participated in
+func (s Server) GetHistory(ctx context.Context, req *PlayerRequest) (*History, error) {
+	err := auth.CheckPlayer(ctx, req.PlayerId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	history, err := s.Controller.History(req.PlayerId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toHistory(history), nil
+}
+
+// AnnounceTournament announces tournament, tournament with target id is satellite,
+// tournament with teams can be joined by teams only
+func (s Server) AnnounceTournament(ctx context.Context, req *AnnounceRequest) (*emptypb.Empty, error) {
+	var err error
+	switch {
+	case req.TargetId != "":
+		err = s.Controller.AnnounceSatellite(req.TournamentId, int(req.Deposit), req.TargetId, int(req.Seats))
+	case req.Teams:
+		err = s.Controller.AnnounceTeamTournament(req.TournamentId, req.Currency, int(req.Deposit))
+	default:
+		maxEntries := int(req.MaxEntries)
+		if maxEntries == 0 {
+			maxEntries = 1
+		}
+		err = s.Controller.AnnounceTournament(req.TournamentId, req.Currency, int(req.Deposit), maxEntries)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// JoinTournament joins player or team into tournament
+func (s Server) JoinTournament(ctx context.Context, req *JoinRequest) (*emptypb.Empty, error) {
+	err := auth.CheckJoin(ctx, req.PlayerId, req.TeamId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if req.TeamId != "" {
+		err = s.Controller.JoinTeam(req.TournamentId, req.TeamId, req.CaptainPays)
+	} else {
+		err = s.Controller.JoinTournament(req.TournamentId, req.PlayerId)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetTournament returns tournament details
+func (s Server) GetTournament(ctx context.Context, req *TournamentRequest) (*Tournament, error) {
+	tour, err := s.Controller.Tournament(req.TournamentId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTournament(tour), nil
+}
+
+// ListTournaments returns page of tournaments
+func (s Server) ListTournaments(ctx context.Context, req *ListTournamentsRequest) (*TourPage, error) {
+	filter := entity.TourFilter{
+		Status:        req.Status,
+		MinDeposit:    int(req.MinDeposit),
+		MaxDeposit:    int(req.MaxDeposit),
+		CreatedFrom:   fromTimestamp(req.CreatedFrom),
+		CreatedTo:     fromTimestamp(req.CreatedTo),
+		ParticipantID: req.ParticipantId,
+		Sort:          req.Sort,
+		Desc:          req.Desc,
+		Limit:         int(req.Limit),
+	}
+	page, err := s.Controller.ListTournaments(filter, req.Cursor)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &TourPage{Next: page.Next}
+	for _, t := range page.Tournaments {
+		res.Tournaments = append(res.Tournaments, toTournament(t))
+	}
+	return res, nil
+}
+
+// Results closes tournament and chooses winners
+func (s Server) Results(ctx context.Context, req *TournamentRequest) (*Winners, error) {
+	winners, err := s.Controller.Results(req.TournamentId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &Winners{}
+	for _, w := range winners.Winners {
+		res.Winners = append(res.Winners, toWinner(w))
+	}
+	return res, nil
+}
@@ -0,0 +1,437 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dmitriyomelyusik/Tournament/auth"
+	"github.com/dmitriyomelyusik/Tournament/entity"
+	"github.com/dmitriyomelyusik/Tournament/errors"
+	"github.com/dmitriyomelyusik/Tournament/ratelimit"
+)
+
+var (
+	client     TournamentServiceClient
+	controller *mockCtlr
+)
+
+func TestMain(m *testing.M) {
+	controller = new(mockCtlr)
+	var stop func()
+	client, stop = serve(Server{Controller: controller})
+	code := m.Run()
+	stop()
+	os.Exit(code)
+}
+
+// serve serves server with its interceptors in memory and returns its client and function, which stops both
+func serve(server Server) (TournamentServiceClient, func()) {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(server.Interceptors()...))
+	RegisterTournamentServiceServer(s, server)
+	go s.Serve(lis)
+	conn, err := grpc.NewClient("passthrough:///bufnet", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+	return NewTournamentServiceClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func TestServer_Fund(t *testing.T) {
+	controller.On("Fund", "fund_new", "", 100, 24*time.Hour).Return(entity.Player{ID: "fund_new", Points: 100, Currency: entity.DefaultCurrency}, nil)
+	controller.On("Fund", "fund_player", "", 100, time.Duration(0)).Return(entity.Player{}, nil)
+	controller.On("Fund", "fund_player", "", -100, time.Duration(0)).Return(entity.Player{}, errors.Error{Code: errors.NegativePointsNumberError, Message: "fund: negative points", Info: -100})
+	tt := []struct {
+		name          string
+		req           *FundRequest
+		expected      *FundResponse
+		expectedCode  codes.Code
+		expectedError *errdetails.ErrorInfo
+	}{
+		{
+			name:     "fund: new player",
+			req:      &FundRequest{PlayerId: "fund_new", Points: 100, ExpireDays: 1},
+			expected: &FundResponse{Created: true, Player: &Player{Id: "fund_new", Points: 100, Currency: entity.DefaultCurrency}},
+		},
+		{
+			name:     "fund: existing player",
+			req:      &FundRequest{PlayerId: "fund_player", Points: 100},
+			expected: &FundResponse{},
+		},
+		{
+			name:          "fund: negative points",
+			req:           &FundRequest{PlayerId: "fund_player", Points: -100},
+			expectedCode:  codes.InvalidArgument,
+			expectedError: &errdetails.ErrorInfo{Reason: string(errors.NegativePointsNumberError), Domain: errorDomain, Metadata: map[string]string{"info": "-100"}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := client.Fund(context.Background(), tc.req)
+			if tc.expectedError != nil {
+				st := status.Convert(err)
+				assert.Equal(t, tc.expectedCode, st.Code())
+				assert.Equal(t, "fund: negative points", st.Message())
+				if assert.Len(t, st.Details(), 1) {
+					assert.True(t, proto.Equal(tc.expectedError, st.Details()[0].(*errdetails.ErrorInfo)))
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(tc.expected, res), res.String())
+		})
+	}
+}
+
+func TestServer_Tournaments(t *testing.T) {
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	closed := created.Add(time.Hour)
+	tour := entity.Tournament{ID: "rpc_tour", Deposit: 100, Currency: entity.DefaultCurrency, Prize: 200, Participants: []string{"rpc_1", "rpc_2"},
+		Winner: entity.Winner{ID: "rpc_1", Points: 300, Prize: 200, Entry: 1}, MaxEntries: 1, Entries: []entity.Entry{{PlayerID: "rpc_1", Number: 1}, {PlayerID: "rpc_2", Number: 1}},
+		Created: created, Closed: &closed}
+	controller.On("AnnounceTournament", "rpc_tour", "", 100, 1).Return(nil)
+	controller.On("AnnounceTournament", "rpc_dup", "", 100, 1).Return(errors.Error{Code: errors.DuplicatedIDError, Message: "announce: duplicated id"})
+	controller.On("AnnounceSatellite", "rpc_sat", 10, "rpc_tour", 2).Return(nil)
+	controller.On("JoinTeam", "rpc_team_tour", "rpc_team", true).Return(nil)
+	controller.On("JoinTournament", "rpc_closed", "rpc_1").Return(errors.Error{Code: errors.ClosedTournamentError, Message: "join: tournament is closed"})
+	controller.On("Tournament", "rpc_tour").Return(tour, nil)
+	controller.On("Tournament", "rpc_fake").Return(entity.Tournament{}, errors.Error{Code: errors.NotFoundError, Message: "tournament: not found"})
+	controller.On("Results", "rpc_tour").Return(entity.Winners{Winners: []entity.Winner{tour.Winner}}, nil)
+	controller.On("Results", "rpc_empty").Return(entity.Winners{}, errors.Error{Code: errors.NoneParticipantsError, Message: "results: none participants"})
+	ctx := context.Background()
+	tt := []struct {
+		name         string
+		call         func() (proto.Message, error)
+		expected     proto.Message
+		expectedCode codes.Code
+	}{
+		{
+			name: "announce: ok",
+			call: func() (proto.Message, error) {
+				return client.AnnounceTournament(ctx, &AnnounceRequest{TournamentId: "rpc_tour", Deposit: 100})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "announce: duplicated id",
+			call: func() (proto.Message, error) {
+				return client.AnnounceTournament(ctx, &AnnounceRequest{TournamentId: "rpc_dup", Deposit: 100})
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "announce: satellite",
+			call: func() (proto.Message, error) {
+				return client.AnnounceTournament(ctx, &AnnounceRequest{TournamentId: "rpc_sat", Deposit: 10, TargetId: "rpc_tour", Seats: 2})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "join: team",
+			call: func() (proto.Message, error) {
+				return client.JoinTournament(ctx, &JoinRequest{TournamentId: "rpc_team_tour", TeamId: "rpc_team", CaptainPays: true})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "join: closed tournament",
+			call: func() (proto.Message, error) {
+				return client.JoinTournament(ctx, &JoinRequest{TournamentId: "rpc_closed", PlayerId: "rpc_1"})
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "tournament: ok",
+			call: func() (proto.Message, error) {
+				return client.GetTournament(ctx, &TournamentRequest{TournamentId: "rpc_tour"})
+			},
+			expected: &Tournament{Id: "rpc_tour", Deposit: 100, Currency: entity.DefaultCurrency, Prize: 200, Participants: []string{"rpc_1", "rpc_2"},
+				Winner: &Winner{Id: "rpc_1", Points: 300, Prize: 200, Entry: 1}, MaxEntries: 1, Entries: []*Entry{{PlayerId: "rpc_1", Number: 1}, {PlayerId: "rpc_2", Number: 1}},
+				Created: timestamppb.New(created), Closed: timestamppb.New(closed)},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "tournament: not found",
+			call: func() (proto.Message, error) {
+				return client.GetTournament(ctx, &TournamentRequest{TournamentId: "rpc_fake"})
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "results: ok",
+			call: func() (proto.Message, error) {
+				return client.Results(ctx, &TournamentRequest{TournamentId: "rpc_tour"})
+			},
+			expected:     &Winners{Winners: []*Winner{{Id: "rpc_1", Points: 300, Prize: 200, Entry: 1}}},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "results: none participants",
+			call: func() (proto.Message, error) {
+				return client.Results(ctx, &TournamentRequest{TournamentId: "rpc_empty"})
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.call()
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expected != nil {
+				assert.True(t, proto.Equal(tc.expected, res))
+			}
+		})
+	}
+}
+
+// keyStore authenticates api keys, which it keeps in memory
+type keyStore map[string]entity.APIKey
+
+func (k keyStore) Authenticate(key string) (entity.APIKey, error) {
+	apiKey, ok := k[key]
+	if !ok {
+		return entity.APIKey{}, errors.Error{Code: errors.UnauthorizedError, Message: "authenticate: api key is not valid"}
+	}
+	return apiKey, nil
+}
+
+func TestServer_Authenticate(t *testing.T) {
+	secret := []byte("rpc_secret")
+	sign := func(subject string, expires time.Time) string {
+		claims := jwt.RegisteredClaims{Subject: subject, Audience: jwt.ClaimStrings{"tournament"}, ExpiresAt: jwt.NewNumericDate(expires)}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		assert.Nil(t, err)
+		return token
+	}
+	keys := keyStore{
+		"admin_key":  {ID: "rpc_admin", Role: entity.RoleAdmin},
+		"client_key": {ID: "rpc_client", Role: entity.RoleClient},
+		"read_key":   {ID: "rpc_read", Role: entity.RoleReadOnly},
+	}
+	authClient, stop := serve(Server{Controller: controller, Auth: keys, Tokens: auth.Verifier{HMACKey: secret, Audience: "tournament"}})
+	defer stop()
+	controller.On("Fund", "rpc_auth", "", 100, time.Duration(0)).Return(entity.Player{}, nil)
+	controller.On("Balance", "rpc_auth", "").Return(entity.Balance{ID: "rpc_auth", Points: 100, Currency: entity.DefaultCurrency}, nil)
+	controller.On("JoinTournament", "rpc_auth_tour", "rpc_auth").Return(nil)
+	player := sign("rpc_auth", time.Now().Add(time.Hour))
+	tt := []struct {
+		name         string
+		metadata     []string
+		call         func(ctx context.Context) error
+		expectedCode codes.Code
+	}{
+		{
+			name: "auth: fund without key",
+			call: func(ctx context.Context) error {
+				_, err := authClient.Fund(ctx, &FundRequest{PlayerId: "rpc_auth", Points: 100})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:     "auth: invalid key",
+			metadata: []string{APIKeyMetadata, "fake_key"},
+			call: func(ctx context.Context) error {
+				_, err := authClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_auth"})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:     "auth: client cannot fund",
+			metadata: []string{APIKeyMetadata, "client_key"},
+			call: func(ctx context.Context) error {
+				_, err := authClient.Fund(ctx, &FundRequest{PlayerId: "rpc_auth", Points: 100})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:     "auth: client cannot take",
+			metadata: []string{APIKeyMetadata, "client_key"},
+			call: func(ctx context.Context) error {
+				_, err := authClient.Take(ctx, &TakeRequest{PlayerId: "rpc_auth", Points: 100})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:     "auth: read-only cannot join",
+			metadata: []string{APIKeyMetadata, "read_key"},
+			call: func(ctx context.Context) error {
+				_, err := authClient.JoinTournament(ctx, &JoinRequest{TournamentId: "rpc_auth_tour", PlayerId: "rpc_auth"})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:     "auth: read-only reads balance",
+			metadata: []string{APIKeyMetadata, "read_key"},
+			call: func(ctx context.Context) error {
+				_, err := authClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_auth"})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:     "auth: client joins",
+			metadata: []string{APIKeyMetadata, "client_key"},
+			call: func(ctx context.Context) error {
+				_, err := authClient.JoinTournament(ctx, &JoinRequest{TournamentId: "rpc_auth_tour", PlayerId: "rpc_auth"})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:     "auth: admin funds",
+			metadata: []string{APIKeyMetadata, "admin_key"},
+			call: func(ctx context.Context) error {
+				_, err := authClient.Fund(ctx, &FundRequest{PlayerId: "rpc_auth", Points: 100})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:     "token: own balance",
+			metadata: []string{AuthorizationMetadata, "Bearer " + player},
+			call: func(ctx context.Context) error {
+				_, err := authClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_auth"})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:     "token: balance of other player",
+			metadata: []string{AuthorizationMetadata, "Bearer " + player},
+			call: func(ctx context.Context) error {
+				_, err := authClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_other"})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:     "token: joins other team",
+			metadata: []string{AuthorizationMetadata, "Bearer " + player},
+			call: func(ctx context.Context) error {
+				_, err := authClient.JoinTournament(ctx, &JoinRequest{TournamentId: "rpc_auth_tour", PlayerId: "rpc_auth", TeamId: "rpc_other_team", CaptainPays: true})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:     "token: cannot fund",
+			metadata: []string{AuthorizationMetadata, "Bearer " + player},
+			call: func(ctx context.Context) error {
+				_, err := authClient.Fund(ctx, &FundRequest{PlayerId: "rpc_auth", Points: 100})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:     "token: expired",
+			metadata: []string{AuthorizationMetadata, "Bearer " + sign("rpc_auth", time.Now().Add(-time.Hour))},
+			call: func(ctx context.Context) error {
+				_, err := authClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_auth"})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), tc.metadata...)
+			assert.Equal(t, tc.expectedCode, status.Code(tc.call(ctx)))
+		})
+	}
+}
+
+func TestServer_LimitRate(t *testing.T) {
+	limiter := ratelimit.Limiter{Store: ratelimit.NewMemory(), Rules: map[string]ratelimit.Rule{ratelimit.ClassJoin: {Rate: 0.5, Burst: 1}}}
+	limitClient, stop := serve(Server{Controller: controller, RateLimit: limiter})
+	defer stop()
+	controller.On("JoinTournament", "rpc_limit_tour", "rpc_limit").Return(nil)
+	controller.On("Balance", "rpc_limit", "").Return(entity.Balance{ID: "rpc_limit", Currency: entity.DefaultCurrency}, nil)
+	ctx := context.Background()
+
+	_, err := limitClient.JoinTournament(ctx, &JoinRequest{TournamentId: "rpc_limit_tour", PlayerId: "rpc_limit"})
+	assert.Nil(t, err)
+	var header metadata.MD
+	_, err = limitClient.JoinTournament(ctx, &JoinRequest{TournamentId: "rpc_limit_tour", PlayerId: "rpc_limit"}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"2"}, header.Get(RetryAfterMetadata))
+	// reads are not limited
+	for i := 0; i < 3; i++ {
+		_, err = limitClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_limit"})
+		assert.Nil(t, err)
+	}
+}
+
+func TestServer_LimitIP(t *testing.T) {
+	limiter := ratelimit.Limiter{Store: ratelimit.NewMemory(), Rules: map[string]ratelimit.Rule{ratelimit.ClassIP: {Rate: 0.5, Burst: 3}}}
+	limitClient, stop := serve(Server{Controller: controller, Auth: keyStore{}, RateLimit: limiter})
+	defer stop()
+	// calls with invalid key are limited before they are authenticated
+	for i, expected := range []codes.Code{codes.Unauthenticated, codes.Unauthenticated, codes.Unauthenticated, codes.ResourceExhausted} {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadata, "fake_key")
+		_, err := limitClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_ip"})
+		assert.Equal(t, expected, status.Code(err), "call %v", i)
+	}
+
+	// default rule does not limit ip class
+	limiter = ratelimit.Limiter{Store: ratelimit.NewMemory(), Rules: map[string]ratelimit.Rule{ratelimit.DefaultClass: {Rate: 0.5, Burst: 1}}}
+	defaultClient, stopDefault := serve(Server{Controller: controller, Auth: keyStore{}, RateLimit: limiter})
+	defer stopDefault()
+	for i := 0; i < 3; i++ {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadata, "fake_key")
+		_, err := defaultClient.GetBalance(ctx, &BalanceRequest{PlayerId: "rpc_ip"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), "call %v", i)
+	}
+}
+
+func TestServer_Code(t *testing.T) {
+	tt := []struct {
+		code         errors.ErrCode
+		expectedCode codes.Code
+	}{
+		{code: errors.NotNumberError, expectedCode: codes.InvalidArgument},
+		{code: errors.InvalidSplitError, expectedCode: codes.InvalidArgument},
+		{code: errors.NotFoundError, expectedCode: codes.NotFound},
+		{code: errors.DuplicatedIDError, expectedCode: codes.AlreadyExists},
+		{code: errors.OpenEntriesError, expectedCode: codes.FailedPrecondition},
+		{code: errors.InactiveAccountError, expectedCode: codes.PermissionDenied},
+		{code: errors.UnauthorizedError, expectedCode: codes.Unauthenticated},
+		{code: errors.ForbiddenError, expectedCode: codes.PermissionDenied},
+		{code: errors.RateLimitError, expectedCode: codes.ResourceExhausted},
+		{code: errors.DatabasePingError, expectedCode: codes.Unavailable},
+		{code: errors.UnexpectedError, expectedCode: codes.Internal},
+	}
+
+	for _, tc := range tt {
+		t.Run(string(tc.code), func(t *testing.T) {
+			assert.Equal(t, tc.expectedCode, Code(tc.code))
+		})
+	}
+}
@@ -0,0 +1,2301 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: rpc/tournament.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	ExpireDays    int64                  `protobuf:"varint,4,opt,name=expire_days,json=expireDays,proto3" json:"expire_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FundRequest) Reset() {
+	*x = FundRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FundRequest) ProtoMessage() {}
+
+func (x *FundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FundRequest.ProtoReflect.Descriptor instead.
+func (*FundRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FundRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *FundRequest) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *FundRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *FundRequest) GetExpireDays() int64 {
+	if x != nil {
+		return x.ExpireDays
+	}
+	return 0
+}
+
+type FundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       bool                   `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Player        *Player                `protobuf:"bytes,2,opt,name=player,proto3" json:"player,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FundResponse) Reset() {
+	*x = FundResponse{}
+	mi := &file_rpc_tournament_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FundResponse) ProtoMessage() {}
+
+func (x *FundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FundResponse.ProtoReflect.Descriptor instead.
+func (*FundResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FundResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+func (x *FundResponse) GetPlayer() *Player {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+type TakeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TakeRequest) Reset() {
+	*x = TakeRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TakeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TakeRequest) ProtoMessage() {}
+
+func (x *TakeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TakeRequest.ProtoReflect.Descriptor instead.
+func (*TakeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TakeRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *TakeRequest) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *TakeRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type TransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Points        int64                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransferRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type BalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalanceRequest) Reset() {
+	*x = BalanceRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceRequest) ProtoMessage() {}
+
+func (x *BalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceRequest.ProtoReflect.Descriptor instead.
+func (*BalanceRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BalanceRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *BalanceRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type RegisterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RegisterRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type PlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerRequest) Reset() {
+	*x = PlayerRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerRequest) ProtoMessage() {}
+
+func (x *PlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerRequest.ProtoReflect.Descriptor instead.
+func (*PlayerRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PlayerRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+type CloseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Withdraw      bool                   `protobuf:"varint,2,opt,name=withdraw,proto3" json:"withdraw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseRequest) Reset() {
+	*x = CloseRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseRequest) ProtoMessage() {}
+
+func (x *CloseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseRequest.ProtoReflect.Descriptor instead.
+func (*CloseRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CloseRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *CloseRequest) GetWithdraw() bool {
+	if x != nil {
+		return x.Withdraw
+	}
+	return false
+}
+
+type ListPlayersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	Sort          string                 `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	Desc          bool                   `protobuf:"varint,5,opt,name=desc,proto3" json:"desc,omitempty"`
+	Limit         int64                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPlayersRequest) Reset() {
+	*x = ListPlayersRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlayersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlayersRequest) ProtoMessage() {}
+
+func (x *ListPlayersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlayersRequest.ProtoReflect.Descriptor instead.
+func (*ListPlayersRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListPlayersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListPlayersRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListPlayersRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListPlayersRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListPlayersRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListPlayersRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListPlayersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type AnnounceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TournamentId  string                 `protobuf:"bytes,1,opt,name=tournament_id,json=tournamentId,proto3" json:"tournament_id,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Deposit       int64                  `protobuf:"varint,3,opt,name=deposit,proto3" json:"deposit,omitempty"`
+	MaxEntries    int64                  `protobuf:"varint,4,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	Teams         bool                   `protobuf:"varint,5,opt,name=teams,proto3" json:"teams,omitempty"`
+	TargetId      string                 `protobuf:"bytes,6,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	Seats         int64                  `protobuf:"varint,7,opt,name=seats,proto3" json:"seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnnounceRequest) Reset() {
+	*x = AnnounceRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnnounceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnounceRequest) ProtoMessage() {}
+
+func (x *AnnounceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnounceRequest.ProtoReflect.Descriptor instead.
+func (*AnnounceRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AnnounceRequest) GetTournamentId() string {
+	if x != nil {
+		return x.TournamentId
+	}
+	return ""
+}
+
+func (x *AnnounceRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *AnnounceRequest) GetDeposit() int64 {
+	if x != nil {
+		return x.Deposit
+	}
+	return 0
+}
+
+func (x *AnnounceRequest) GetMaxEntries() int64 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+func (x *AnnounceRequest) GetTeams() bool {
+	if x != nil {
+		return x.Teams
+	}
+	return false
+}
+
+func (x *AnnounceRequest) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *AnnounceRequest) GetSeats() int64 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
+type JoinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TournamentId  string                 `protobuf:"bytes,1,opt,name=tournament_id,json=tournamentId,proto3" json:"tournament_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	TeamId        string                 `protobuf:"bytes,3,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	CaptainPays   bool                   `protobuf:"varint,4,opt,name=captain_pays,json=captainPays,proto3" json:"captain_pays,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinRequest) Reset() {
+	*x = JoinRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinRequest) ProtoMessage() {}
+
+func (x *JoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinRequest.ProtoReflect.Descriptor instead.
+func (*JoinRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *JoinRequest) GetTournamentId() string {
+	if x != nil {
+		return x.TournamentId
+	}
+	return ""
+}
+
+func (x *JoinRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *JoinRequest) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *JoinRequest) GetCaptainPays() bool {
+	if x != nil {
+		return x.CaptainPays
+	}
+	return false
+}
+
+type TournamentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TournamentId  string                 `protobuf:"bytes,1,opt,name=tournament_id,json=tournamentId,proto3" json:"tournament_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TournamentRequest) Reset() {
+	*x = TournamentRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TournamentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TournamentRequest) ProtoMessage() {}
+
+func (x *TournamentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TournamentRequest.ProtoReflect.Descriptor instead.
+func (*TournamentRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TournamentRequest) GetTournamentId() string {
+	if x != nil {
+		return x.TournamentId
+	}
+	return ""
+}
+
+type ListTournamentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	MinDeposit    int64                  `protobuf:"varint,2,opt,name=min_deposit,json=minDeposit,proto3" json:"min_deposit,omitempty"`
+	MaxDeposit    int64                  `protobuf:"varint,3,opt,name=max_deposit,json=maxDeposit,proto3" json:"max_deposit,omitempty"`
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	ParticipantId string                 `protobuf:"bytes,6,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	Sort          string                 `protobuf:"bytes,7,opt,name=sort,proto3" json:"sort,omitempty"`
+	Desc          bool                   `protobuf:"varint,8,opt,name=desc,proto3" json:"desc,omitempty"`
+	Limit         int64                  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,10,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTournamentsRequest) Reset() {
+	*x = ListTournamentsRequest{}
+	mi := &file_rpc_tournament_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTournamentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTournamentsRequest) ProtoMessage() {}
+
+func (x *ListTournamentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTournamentsRequest.ProtoReflect.Descriptor instead.
+func (*ListTournamentsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListTournamentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListTournamentsRequest) GetMinDeposit() int64 {
+	if x != nil {
+		return x.MinDeposit
+	}
+	return 0
+}
+
+func (x *ListTournamentsRequest) GetMaxDeposit() int64 {
+	if x != nil {
+		return x.MaxDeposit
+	}
+	return 0
+}
+
+func (x *ListTournamentsRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListTournamentsRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListTournamentsRequest) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *ListTournamentsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListTournamentsRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListTournamentsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTournamentsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type Player struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_rpc_tournament_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Player) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Player) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *Player) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Hold struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Points        int64                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Expires       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires,proto3" json:"expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hold) Reset() {
+	*x = Hold{}
+	mi := &file_rpc_tournament_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hold) ProtoMessage() {}
+
+func (x *Hold) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hold.ProtoReflect.Descriptor instead.
+func (*Hold) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Hold) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Hold) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *Hold) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *Hold) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Hold) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+type Lot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Points        int64                  `protobuf:"varint,4,opt,name=points,proto3" json:"points,omitempty"`
+	Created       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	Expires       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires,proto3" json:"expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lot) Reset() {
+	*x = Lot{}
+	mi := &file_rpc_tournament_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lot) ProtoMessage() {}
+
+func (x *Lot) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lot.ProtoReflect.Descriptor instead.
+func (*Lot) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Lot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Lot) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *Lot) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Lot) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *Lot) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *Lot) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Available     int64                  `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	Holds         []*Hold                `protobuf:"bytes,5,rep,name=holds,proto3" json:"holds,omitempty"`
+	Expiring      []*Lot                 `protobuf:"bytes,6,rep,name=expiring,proto3" json:"expiring,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_rpc_tournament_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Balance) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Balance) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *Balance) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Balance) GetAvailable() int64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *Balance) GetHolds() []*Hold {
+	if x != nil {
+		return x.Holds
+	}
+	return nil
+}
+
+func (x *Balance) GetExpiring() []*Lot {
+	if x != nil {
+		return x.Expiring
+	}
+	return nil
+}
+
+type Account struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Created       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created,proto3" json:"created,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_rpc_tournament_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Account) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Account) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Account) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *Account) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Account) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type Closure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Withdrawn     []string               `protobuf:"bytes,2,rep,name=withdrawn,proto3" json:"withdrawn,omitempty"`
+	Payouts       []*Player              `protobuf:"bytes,3,rep,name=payouts,proto3" json:"payouts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Closure) Reset() {
+	*x = Closure{}
+	mi := &file_rpc_tournament_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Closure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Closure) ProtoMessage() {}
+
+func (x *Closure) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Closure.ProtoReflect.Descriptor instead.
+func (*Closure) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Closure) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *Closure) GetWithdrawn() []string {
+	if x != nil {
+		return x.Withdrawn
+	}
+	return nil
+}
+
+func (x *Closure) GetPayouts() []*Player {
+	if x != nil {
+		return x.Payouts
+	}
+	return nil
+}
+
+type PlayerPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Players       []*Account             `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Next          string                 `protobuf:"bytes,2,opt,name=next,proto3" json:"next,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerPage) Reset() {
+	*x = PlayerPage{}
+	mi := &file_rpc_tournament_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerPage) ProtoMessage() {}
+
+func (x *PlayerPage) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerPage.ProtoReflect.Descriptor instead.
+func (*PlayerPage) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PlayerPage) GetPlayers() []*Account {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *PlayerPage) GetNext() string {
+	if x != nil {
+		return x.Next
+	}
+	return ""
+}
+
+type Participation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TournamentId  string                 `protobuf:"bytes,1,opt,name=tournament_id,json=tournamentId,proto3" json:"tournament_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	IsOpen        bool                   `protobuf:"varint,4,opt,name=is_open,json=isOpen,proto3" json:"is_open,omitempty"`
+	Paid          int64                  `protobuf:"varint,5,opt,name=paid,proto3" json:"paid,omitempty"`
+	Placing       int64                  `protobuf:"varint,6,opt,name=placing,proto3" json:"placing,omitempty"`
+	Prize         int64                  `protobuf:"varint,7,opt,name=prize,proto3" json:"prize,omitempty"`
+	Joined        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=joined,proto3" json:"joined,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Participation) Reset() {
+	*x = Participation{}
+	mi := &file_rpc_tournament_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Participation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Participation) ProtoMessage() {}
+
+func (x *Participation) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Participation.ProtoReflect.Descriptor instead.
+func (*Participation) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Participation) GetTournamentId() string {
+	if x != nil {
+		return x.TournamentId
+	}
+	return ""
+}
+
+func (x *Participation) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *Participation) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Participation) GetIsOpen() bool {
+	if x != nil {
+		return x.IsOpen
+	}
+	return false
+}
+
+func (x *Participation) GetPaid() int64 {
+	if x != nil {
+		return x.Paid
+	}
+	return 0
+}
+
+func (x *Participation) GetPlacing() int64 {
+	if x != nil {
+		return x.Placing
+	}
+	return 0
+}
+
+func (x *Participation) GetPrize() int64 {
+	if x != nil {
+		return x.Prize
+	}
+	return 0
+}
+
+func (x *Participation) GetJoined() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Joined
+	}
+	return nil
+}
+
+type History struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Tournaments   []*Participation       `protobuf:"bytes,2,rep,name=tournaments,proto3" json:"tournaments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *History) Reset() {
+	*x = History{}
+	mi := &file_rpc_tournament_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *History) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*History) ProtoMessage() {}
+
+func (x *History) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use History.ProtoReflect.Descriptor instead.
+func (*History) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *History) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *History) GetTournaments() []*Participation {
+	if x != nil {
+		return x.Tournaments
+	}
+	return nil
+}
+
+type Winner struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	Prize         int64                  `protobuf:"varint,3,opt,name=prize,proto3" json:"prize,omitempty"`
+	Seat          string                 `protobuf:"bytes,4,opt,name=seat,proto3" json:"seat,omitempty"`
+	Entry         int64                  `protobuf:"varint,5,opt,name=entry,proto3" json:"entry,omitempty"`
+	Team          string                 `protobuf:"bytes,6,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Winner) Reset() {
+	*x = Winner{}
+	mi := &file_rpc_tournament_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Winner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Winner) ProtoMessage() {}
+
+func (x *Winner) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Winner.ProtoReflect.Descriptor instead.
+func (*Winner) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Winner) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Winner) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *Winner) GetPrize() int64 {
+	if x != nil {
+		return x.Prize
+	}
+	return 0
+}
+
+func (x *Winner) GetSeat() string {
+	if x != nil {
+		return x.Seat
+	}
+	return ""
+}
+
+func (x *Winner) GetEntry() int64 {
+	if x != nil {
+		return x.Entry
+	}
+	return 0
+}
+
+func (x *Winner) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+type Winners struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Winners       []*Winner              `protobuf:"bytes,1,rep,name=winners,proto3" json:"winners,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Winners) Reset() {
+	*x = Winners{}
+	mi := &file_rpc_tournament_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Winners) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Winners) ProtoMessage() {}
+
+func (x *Winners) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Winners.ProtoReflect.Descriptor instead.
+func (*Winners) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Winners) GetWinners() []*Winner {
+	if x != nil {
+		return x.Winners
+	}
+	return nil
+}
+
+type Entry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Number        int64                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Entry) Reset() {
+	*x = Entry{}
+	mi := &file_rpc_tournament_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry) ProtoMessage() {}
+
+func (x *Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry.ProtoReflect.Descriptor instead.
+func (*Entry) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Entry) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *Entry) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type Satellite struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetId      string                 `protobuf:"bytes,1,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	Seats         int64                  `protobuf:"varint,2,opt,name=seats,proto3" json:"seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Satellite) Reset() {
+	*x = Satellite{}
+	mi := &file_rpc_tournament_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Satellite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Satellite) ProtoMessage() {}
+
+func (x *Satellite) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Satellite.ProtoReflect.Descriptor instead.
+func (*Satellite) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Satellite) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *Satellite) GetSeats() int64 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
+type Tournament struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Deposit       int64                  `protobuf:"varint,2,opt,name=deposit,proto3" json:"deposit,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Prize         int64                  `protobuf:"varint,4,opt,name=prize,proto3" json:"prize,omitempty"`
+	Participants  []string               `protobuf:"bytes,5,rep,name=participants,proto3" json:"participants,omitempty"`
+	Winner        *Winner                `protobuf:"bytes,6,opt,name=winner,proto3" json:"winner,omitempty"`
+	Winners       []*Winner              `protobuf:"bytes,7,rep,name=winners,proto3" json:"winners,omitempty"`
+	IsOpen        bool                   `protobuf:"varint,8,opt,name=is_open,json=isOpen,proto3" json:"is_open,omitempty"`
+	Satellite     *Satellite             `protobuf:"bytes,9,opt,name=satellite,proto3" json:"satellite,omitempty"`
+	MaxEntries    int64                  `protobuf:"varint,10,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	Entries       []*Entry               `protobuf:"bytes,11,rep,name=entries,proto3" json:"entries,omitempty"`
+	IsTeam        bool                   `protobuf:"varint,12,opt,name=is_team,json=isTeam,proto3" json:"is_team,omitempty"`
+	Teams         []string               `protobuf:"bytes,13,rep,name=teams,proto3" json:"teams,omitempty"`
+	Created       *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created,proto3" json:"created,omitempty"`
+	Closed        *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=closed,proto3" json:"closed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tournament) Reset() {
+	*x = Tournament{}
+	mi := &file_rpc_tournament_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tournament) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tournament) ProtoMessage() {}
+
+func (x *Tournament) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tournament.ProtoReflect.Descriptor instead.
+func (*Tournament) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Tournament) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tournament) GetDeposit() int64 {
+	if x != nil {
+		return x.Deposit
+	}
+	return 0
+}
+
+func (x *Tournament) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Tournament) GetPrize() int64 {
+	if x != nil {
+		return x.Prize
+	}
+	return 0
+}
+
+func (x *Tournament) GetParticipants() []string {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *Tournament) GetWinner() *Winner {
+	if x != nil {
+		return x.Winner
+	}
+	return nil
+}
+
+func (x *Tournament) GetWinners() []*Winner {
+	if x != nil {
+		return x.Winners
+	}
+	return nil
+}
+
+func (x *Tournament) GetIsOpen() bool {
+	if x != nil {
+		return x.IsOpen
+	}
+	return false
+}
+
+func (x *Tournament) GetSatellite() *Satellite {
+	if x != nil {
+		return x.Satellite
+	}
+	return nil
+}
+
+func (x *Tournament) GetMaxEntries() int64 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+func (x *Tournament) GetEntries() []*Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *Tournament) GetIsTeam() bool {
+	if x != nil {
+		return x.IsTeam
+	}
+	return false
+}
+
+func (x *Tournament) GetTeams() []string {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+func (x *Tournament) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *Tournament) GetClosed() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Closed
+	}
+	return nil
+}
+
+type TourPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tournaments   []*Tournament          `protobuf:"bytes,1,rep,name=tournaments,proto3" json:"tournaments,omitempty"`
+	Next          string                 `protobuf:"bytes,2,opt,name=next,proto3" json:"next,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TourPage) Reset() {
+	*x = TourPage{}
+	mi := &file_rpc_tournament_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TourPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TourPage) ProtoMessage() {}
+
+func (x *TourPage) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_tournament_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TourPage.ProtoReflect.Descriptor instead.
+func (*TourPage) Descriptor() ([]byte, []int) {
+	return file_rpc_tournament_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TourPage) GetTournaments() []*Tournament {
+	if x != nil {
+		return x.Tournaments
+	}
+	return nil
+}
+
+func (x *TourPage) GetNext() string {
+	if x != nil {
+		return x.Next
+	}
+	return ""
+}
+
+var File_rpc_tournament_proto protoreflect.FileDescriptor
+
+const file_rpc_tournament_proto_rawDesc = "" +
+	"\n" +
+	"\x14rpc/tournament.proto\x12\rtournament.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x7f\n" +
+	"\vFundRequest\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x1f\n" +
+	"\vexpire_days\x18\x04 \x01(\x03R\n" +
+	"expireDays\"W\n" +
+	"\fFundResponse\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\bR\acreated\x12-\n" +
+	"\x06player\x18\x02 \x01(\v2\x15.tournament.v1.PlayerR\x06player\"^\n" +
+	"\vTakeRequest\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"i\n" +
+	"\x0fTransferRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x03R\x06points\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"I\n" +
+	"\x0eBalanceRequest\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xc9\x01\n" +
+	"\x0fRegisterRequest\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12H\n" +
+	"\bmetadata\x18\x03 \x03(\v2,.tournament.v1.RegisterRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\",\n" +
+	"\rPlayerRequest\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\"G\n" +
+	"\fCloseRequest\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x1a\n" +
+	"\bwithdraw\x18\x02 \x01(\bR\bwithdraw\"\xfc\x01\n" +
+	"\x12ListPlayersRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12=\n" +
+	"\fcreated_from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x12\n" +
+	"\x04desc\x18\x05 \x01(\bR\x04desc\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\"\xd6\x01\n" +
+	"\x0fAnnounceRequest\x12#\n" +
+	"\rtournament_id\x18\x01 \x01(\tR\ftournamentId\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x18\n" +
+	"\adeposit\x18\x03 \x01(\x03R\adeposit\x12\x1f\n" +
+	"\vmax_entries\x18\x04 \x01(\x03R\n" +
+	"maxEntries\x12\x14\n" +
+	"\x05teams\x18\x05 \x01(\bR\x05teams\x12\x1b\n" +
+	"\ttarget_id\x18\x06 \x01(\tR\btargetId\x12\x14\n" +
+	"\x05seats\x18\a \x01(\x03R\x05seats\"\x8b\x01\n" +
+	"\vJoinRequest\x12#\n" +
+	"\rtournament_id\x18\x01 \x01(\tR\ftournamentId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x17\n" +
+	"\ateam_id\x18\x03 \x01(\tR\x06teamId\x12!\n" +
+	"\fcaptain_pays\x18\x04 \x01(\bR\vcaptainPays\"8\n" +
+	"\x11TournamentRequest\x12#\n" +
+	"\rtournament_id\x18\x01 \x01(\tR\ftournamentId\"\xe9\x02\n" +
+	"\x16ListTournamentsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1f\n" +
+	"\vmin_deposit\x18\x02 \x01(\x03R\n" +
+	"minDeposit\x12\x1f\n" +
+	"\vmax_deposit\x18\x03 \x01(\x03R\n" +
+	"maxDeposit\x12=\n" +
+	"\fcreated_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12%\n" +
+	"\x0eparticipant_id\x18\x06 \x01(\tR\rparticipantId\x12\x12\n" +
+	"\x04sort\x18\a \x01(\tR\x04sort\x12\x12\n" +
+	"\x04desc\x18\b \x01(\bR\x04desc\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\n" +
+	" \x01(\tR\x06cursor\"L\n" +
+	"\x06Player\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\x9d\x01\n" +
+	"\x04Hold\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x03R\x06points\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x124\n" +
+	"\aexpires\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\"\xd2\x01\n" +
+	"\x03Lot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06points\x18\x04 \x01(\x03R\x06points\x124\n" +
+	"\acreated\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x124\n" +
+	"\aexpires\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\"\xc6\x01\n" +
+	"\aBalance\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x1c\n" +
+	"\tavailable\x18\x04 \x01(\x03R\tavailable\x12)\n" +
+	"\x05holds\x18\x05 \x03(\v2\x13.tournament.v1.HoldR\x05holds\x12.\n" +
+	"\bexpiring\x18\x06 \x03(\v2\x12.tournament.v1.LotR\bexpiring\"\xfa\x01\n" +
+	"\aAccount\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x124\n" +
+	"\acreated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x12@\n" +
+	"\bmetadata\x18\x04 \x03(\v2$.tournament.v1.Account.MetadataEntryR\bmetadata\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"u\n" +
+	"\aClosure\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x1c\n" +
+	"\twithdrawn\x18\x02 \x03(\tR\twithdrawn\x12/\n" +
+	"\apayouts\x18\x03 \x03(\v2\x15.tournament.v1.PlayerR\apayouts\"R\n" +
+	"\n" +
+	"PlayerPage\x120\n" +
+	"\aplayers\x18\x01 \x03(\v2\x16.tournament.v1.AccountR\aplayers\x12\x12\n" +
+	"\x04next\x18\x02 \x01(\tR\x04next\"\xfe\x01\n" +
+	"\rParticipation\x12#\n" +
+	"\rtournament_id\x18\x01 \x01(\tR\ftournamentId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x17\n" +
+	"\ais_open\x18\x04 \x01(\bR\x06isOpen\x12\x12\n" +
+	"\x04paid\x18\x05 \x01(\x03R\x04paid\x12\x18\n" +
+	"\aplacing\x18\x06 \x01(\x03R\aplacing\x12\x14\n" +
+	"\x05prize\x18\a \x01(\x03R\x05prize\x122\n" +
+	"\x06joined\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x06joined\"f\n" +
+	"\aHistory\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12>\n" +
+	"\vtournaments\x18\x02 \x03(\v2\x1c.tournament.v1.ParticipationR\vtournaments\"\x84\x01\n" +
+	"\x06Winner\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x14\n" +
+	"\x05prize\x18\x03 \x01(\x03R\x05prize\x12\x12\n" +
+	"\x04seat\x18\x04 \x01(\tR\x04seat\x12\x14\n" +
+	"\x05entry\x18\x05 \x01(\x03R\x05entry\x12\x12\n" +
+	"\x04team\x18\x06 \x01(\tR\x04team\":\n" +
+	"\aWinners\x12/\n" +
+	"\awinners\x18\x01 \x03(\v2\x15.tournament.v1.WinnerR\awinners\"<\n" +
+	"\x05Entry\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\x03R\x06number\">\n" +
+	"\tSatellite\x12\x1b\n" +
+	"\ttarget_id\x18\x01 \x01(\tR\btargetId\x12\x14\n" +
+	"\x05seats\x18\x02 \x01(\x03R\x05seats\"\xa7\x04\n" +
+	"\n" +
+	"Tournament\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\adeposit\x18\x02 \x01(\x03R\adeposit\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x14\n" +
+	"\x05prize\x18\x04 \x01(\x03R\x05prize\x12\"\n" +
+	"\fparticipants\x18\x05 \x03(\tR\fparticipants\x12-\n" +
+	"\x06winner\x18\x06 \x01(\v2\x15.tournament.v1.WinnerR\x06winner\x12/\n" +
+	"\awinners\x18\a \x03(\v2\x15.tournament.v1.WinnerR\awinners\x12\x17\n" +
+	"\ais_open\x18\b \x01(\bR\x06isOpen\x126\n" +
+	"\tsatellite\x18\t \x01(\v2\x18.tournament.v1.SatelliteR\tsatellite\x12\x1f\n" +
+	"\vmax_entries\x18\n" +
+	" \x01(\x03R\n" +
+	"maxEntries\x12.\n" +
+	"\aentries\x18\v \x03(\v2\x14.tournament.v1.EntryR\aentries\x12\x17\n" +
+	"\ais_team\x18\f \x01(\bR\x06isTeam\x12\x14\n" +
+	"\x05teams\x18\r \x03(\tR\x05teams\x124\n" +
+	"\acreated\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x122\n" +
+	"\x06closed\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\x06closed\"[\n" +
+	"\bTourPage\x12;\n" +
+	"\vtournaments\x18\x01 \x03(\v2\x19.tournament.v1.TournamentR\vtournaments\x12\x12\n" +
+	"\x04next\x18\x02 \x01(\tR\x04next2\xf5\b\n" +
+	"\x11TournamentService\x12?\n" +
+	"\x04Fund\x12\x1a.tournament.v1.FundRequest\x1a\x1b.tournament.v1.FundResponse\x12:\n" +
+	"\x04Take\x12\x1a.tournament.v1.TakeRequest\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"\bTransfer\x12\x1e.tournament.v1.TransferRequest\x1a\x16.google.protobuf.Empty\x12C\n" +
+	"\n" +
+	"GetBalance\x12\x1d.tournament.v1.BalanceRequest\x1a\x16.tournament.v1.Balance\x12B\n" +
+	"\bRegister\x12\x1e.tournament.v1.RegisterRequest\x1a\x16.tournament.v1.Account\x12B\n" +
+	"\n" +
+	"GetAccount\x12\x1c.tournament.v1.PlayerRequest\x1a\x16.tournament.v1.Account\x12?\n" +
+	"\aSuspend\x12\x1c.tournament.v1.PlayerRequest\x1a\x16.tournament.v1.Account\x12A\n" +
+	"\tReinstate\x12\x1c.tournament.v1.PlayerRequest\x1a\x16.tournament.v1.Account\x12C\n" +
+	"\fCloseAccount\x12\x1b.tournament.v1.CloseRequest\x1a\x16.tournament.v1.Closure\x12K\n" +
+	"\vListPlayers\x12!.tournament.v1.ListPlayersRequest\x1a\x19.tournament.v1.PlayerPage\x12B\n" +
+	"\n" +
+	"GetHistory\x12\x1c.tournament.v1.PlayerRequest\x1a\x16.tournament.v1.History\x12L\n" +
+	"\x12AnnounceTournament\x12\x1e.tournament.v1.AnnounceRequest\x1a\x16.google.protobuf.Empty\x12D\n" +
+	"\x0eJoinTournament\x12\x1a.tournament.v1.JoinRequest\x1a\x16.google.protobuf.Empty\x12L\n" +
+	"\rGetTournament\x12 .tournament.v1.TournamentRequest\x1a\x19.tournament.v1.Tournament\x12Q\n" +
+	"\x0fListTournaments\x12%.tournament.v1.ListTournamentsRequest\x1a\x17.tournament.v1.TourPage\x12C\n" +
+	"\aResults\x12 .tournament.v1.TournamentRequest\x1a\x16.tournament.v1.WinnersB,Z*github.com/dmitriyomelyusik/Tournament/rpcb\x06proto3"
+
+var (
+	file_rpc_tournament_proto_rawDescOnce sync.Once
+	file_rpc_tournament_proto_rawDescData []byte
+)
+
+func file_rpc_tournament_proto_rawDescGZIP() []byte {
+	file_rpc_tournament_proto_rawDescOnce.Do(func() {
+		file_rpc_tournament_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_tournament_proto_rawDesc), len(file_rpc_tournament_proto_rawDesc)))
+	})
+	return file_rpc_tournament_proto_rawDescData
+}
+
+var file_rpc_tournament_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_rpc_tournament_proto_goTypes = []any{
+	(*FundRequest)(nil),            // 0: tournament.v1.FundRequest
+	(*FundResponse)(nil),           // 1: tournament.v1.FundResponse
+	(*TakeRequest)(nil),            // 2: tournament.v1.TakeRequest
+	(*TransferRequest)(nil),        // 3: tournament.v1.TransferRequest
+	(*BalanceRequest)(nil),         // 4: tournament.v1.BalanceRequest
+	(*RegisterRequest)(nil),        // 5: tournament.v1.RegisterRequest
+	(*PlayerRequest)(nil),          // 6: tournament.v1.PlayerRequest
+	(*CloseRequest)(nil),           // 7: tournament.v1.CloseRequest
+	(*ListPlayersRequest)(nil),     // 8: tournament.v1.ListPlayersRequest
+	(*AnnounceRequest)(nil),        // 9: tournament.v1.AnnounceRequest
+	(*JoinRequest)(nil),            // 10: tournament.v1.JoinRequest
+	(*TournamentRequest)(nil),      // 11: tournament.v1.TournamentRequest
+	(*ListTournamentsRequest)(nil), // 12: tournament.v1.ListTournamentsRequest
+	(*Player)(nil),                 // 13: tournament.v1.Player
+	(*Hold)(nil),                   // 14: tournament.v1.Hold
+	(*Lot)(nil),                    // 15: tournament.v1.Lot
+	(*Balance)(nil),                // 16: tournament.v1.Balance
+	(*Account)(nil),                // 17: tournament.v1.Account
+	(*Closure)(nil),                // 18: tournament.v1.Closure
+	(*PlayerPage)(nil),             // 19: tournament.v1.PlayerPage
+	(*Participation)(nil),          // 20: tournament.v1.Participation
+	(*History)(nil),                // 21: tournament.v1.History
+	(*Winner)(nil),                 // 22: tournament.v1.Winner
+	(*Winners)(nil),                // 23: tournament.v1.Winners
+	(*Entry)(nil),                  // 24: tournament.v1.Entry
+	(*Satellite)(nil),              // 25: tournament.v1.Satellite
+	(*Tournament)(nil),             // 26: tournament.v1.Tournament
+	(*TourPage)(nil),               // 27: tournament.v1.TourPage
+	nil,                            // 28: tournament.v1.RegisterRequest.MetadataEntry
+	nil,                            // 29: tournament.v1.Account.MetadataEntry
+	(*timestamppb.Timestamp)(nil),  // 30: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),          // 31: google.protobuf.Empty
+}
+var file_rpc_tournament_proto_depIdxs = []int32{
+	13, // 0: tournament.v1.FundResponse.player:type_name -> tournament.v1.Player
+	28, // 1: tournament.v1.RegisterRequest.metadata:type_name -> tournament.v1.RegisterRequest.MetadataEntry
+	30, // 2: tournament.v1.ListPlayersRequest.created_from:type_name -> google.protobuf.Timestamp
+	30, // 3: tournament.v1.ListPlayersRequest.created_to:type_name -> google.protobuf.Timestamp
+	30, // 4: tournament.v1.ListTournamentsRequest.created_from:type_name -> google.protobuf.Timestamp
+	30, // 5: tournament.v1.ListTournamentsRequest.created_to:type_name -> google.protobuf.Timestamp
+	30, // 6: tournament.v1.Hold.expires:type_name -> google.protobuf.Timestamp
+	30, // 7: tournament.v1.Lot.created:type_name -> google.protobuf.Timestamp
+	30, // 8: tournament.v1.Lot.expires:type_name -> google.protobuf.Timestamp
+	14, // 9: tournament.v1.Balance.holds:type_name -> tournament.v1.Hold
+	15, // 10: tournament.v1.Balance.expiring:type_name -> tournament.v1.Lot
+	30, // 11: tournament.v1.Account.created:type_name -> google.protobuf.Timestamp
+	29, // 12: tournament.v1.Account.metadata:type_name -> tournament.v1.Account.MetadataEntry
+	13, // 13: tournament.v1.Closure.payouts:type_name -> tournament.v1.Player
+	17, // 14: tournament.v1.PlayerPage.players:type_name -> tournament.v1.Account
+	30, // 15: tournament.v1.Participation.joined:type_name -> google.protobuf.Timestamp
+	20, // 16: tournament.v1.History.tournaments:type_name -> tournament.v1.Participation
+	22, // 17: tournament.v1.Winners.winners:type_name -> tournament.v1.Winner
+	22, // 18: tournament.v1.Tournament.winner:type_name -> tournament.v1.Winner
+	22, // 19: tournament.v1.Tournament.winners:type_name -> tournament.v1.Winner
+	25, // 20: tournament.v1.Tournament.satellite:type_name -> tournament.v1.Satellite
+	24, // 21: tournament.v1.Tournament.entries:type_name -> tournament.v1.Entry
+	30, // 22: tournament.v1.Tournament.created:type_name -> google.protobuf.Timestamp
+	30, // 23: tournament.v1.Tournament.closed:type_name -> google.protobuf.Timestamp
+	26, // 24: tournament.v1.TourPage.tournaments:type_name -> tournament.v1.Tournament
+	0,  // 25: tournament.v1.TournamentService.Fund:input_type -> tournament.v1.FundRequest
+	2,  // 26: tournament.v1.TournamentService.Take:input_type -> tournament.v1.TakeRequest
+	3,  // 27: tournament.v1.TournamentService.Transfer:input_type -> tournament.v1.TransferRequest
+	4,  // 28: tournament.v1.TournamentService.GetBalance:input_type -> tournament.v1.BalanceRequest
+	5,  // 29: tournament.v1.TournamentService.Register:input_type -> tournament.v1.RegisterRequest
+	6,  // 30: tournament.v1.TournamentService.GetAccount:input_type -> tournament.v1.PlayerRequest
+	6,  // 31: tournament.v1.TournamentService.Suspend:input_type -> tournament.v1.PlayerRequest
+	6,  // 32: tournament.v1.TournamentService.Reinstate:input_type -> tournament.v1.PlayerRequest
+	7,  // 33: tournament.v1.TournamentService.CloseAccount:input_type -> tournament.v1.CloseRequest
+	8,  // 34: tournament.v1.TournamentService.ListPlayers:input_type -> tournament.v1.ListPlayersRequest
+	6,  // 35: tournament.v1.TournamentService.GetHistory:input_type -> tournament.v1.PlayerRequest
+	9,  // 36: tournament.v1.TournamentService.AnnounceTournament:input_type -> tournament.v1.AnnounceRequest
+	10, // 37: tournament.v1.TournamentService.JoinTournament:input_type -> tournament.v1.JoinRequest
+	11, // 38: tournament.v1.TournamentService.GetTournament:input_type -> tournament.v1.TournamentRequest
+	12, // 39: tournament.v1.TournamentService.ListTournaments:input_type -> tournament.v1.ListTournamentsRequest
+	11, // 40: tournament.v1.TournamentService.Results:input_type -> tournament.v1.TournamentRequest
+	1,  // 41: tournament.v1.TournamentService.Fund:output_type -> tournament.v1.FundResponse
+	31, // 42: tournament.v1.TournamentService.Take:output_type -> google.protobuf.Empty
+	31, // 43: tournament.v1.TournamentService.Transfer:output_type -> google.protobuf.Empty
+	16, // 44: tournament.v1.TournamentService.GetBalance:output_type -> tournament.v1.Balance
+	17, // 45: tournament.v1.TournamentService.Register:output_type -> tournament.v1.Account
+	17, // 46: tournament.v1.TournamentService.GetAccount:output_type -> tournament.v1.Account
+	17, // 47: tournament.v1.TournamentService.Suspend:output_type -> tournament.v1.Account
+	17, // 48: tournament.v1.TournamentService.Reinstate:output_type -> tournament.v1.Account
+	18, // 49: tournament.v1.TournamentService.CloseAccount:output_type -> tournament.v1.Closure
+	19, // 50: tournament.v1.TournamentService.ListPlayers:output_type -> tournament.v1.PlayerPage
+	21, // 51: tournament.v1.TournamentService.GetHistory:output_type -> tournament.v1.History
+	31, // 52: tournament.v1.TournamentService.AnnounceTournament:output_type -> google.protobuf.Empty
+	31, // 53: tournament.v1.TournamentService.JoinTournament:output_type -> google.protobuf.Empty
+	26, // 54: tournament.v1.TournamentService.GetTournament:output_type -> tournament.v1.Tournament
+	27, // 55: tournament.v1.TournamentService.ListTournaments:output_type -> tournament.v1.TourPage
+	23, // 56: tournament.v1.TournamentService.Results:output_type -> tournament.v1.Winners
+	41, // [41:57] is the sub-list for method output_type
+	25, // [25:41] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_rpc_tournament_proto_init() }
+func file_rpc_tournament_proto_init() {
+	if File_rpc_tournament_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_tournament_proto_rawDesc), len(file_rpc_tournament_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_tournament_proto_goTypes,
+		DependencyIndexes: file_rpc_tournament_proto_depIdxs,
+		MessageInfos:      file_rpc_tournament_proto_msgTypes,
+	}.Build()
+	File_rpc_tournament_proto = out.File
+	file_rpc_tournament_proto_goTypes = nil
+	file_rpc_tournament_proto_depIdxs = nil
+}
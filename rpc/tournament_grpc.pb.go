@@ -0,0 +1,708 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: rpc/tournament.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TournamentService_Fund_FullMethodName               = "/tournament.v1.TournamentService/Fund"
+	TournamentService_Take_FullMethodName               = "/tournament.v1.TournamentService/Take"
+	TournamentService_Transfer_FullMethodName           = "/tournament.v1.TournamentService/Transfer"
+	TournamentService_GetBalance_FullMethodName         = "/tournament.v1.TournamentService/GetBalance"
+	TournamentService_Register_FullMethodName           = "/tournament.v1.TournamentService/Register"
+	TournamentService_GetAccount_FullMethodName         = "/tournament.v1.TournamentService/GetAccount"
+	TournamentService_Suspend_FullMethodName            = "/tournament.v1.TournamentService/Suspend"
+	TournamentService_Reinstate_FullMethodName          = "/tournament.v1.TournamentService/Reinstate"
+	TournamentService_CloseAccount_FullMethodName       = "/tournament.v1.TournamentService/CloseAccount"
+	TournamentService_ListPlayers_FullMethodName        = "/tournament.v1.TournamentService/ListPlayers"
+	TournamentService_GetHistory_FullMethodName         = "/tournament.v1.TournamentService/GetHistory"
+	TournamentService_AnnounceTournament_FullMethodName = "/tournament.v1.TournamentService/AnnounceTournament"
+	TournamentService_JoinTournament_FullMethodName     = "/tournament.v1.TournamentService/JoinTournament"
+	TournamentService_GetTournament_FullMethodName      = "/tournament.v1.TournamentService/GetTournament"
+	TournamentService_ListTournaments_FullMethodName    = "/tournament.v1.TournamentService/ListTournaments"
+	TournamentService_Results_FullMethodName            = "/tournament.v1.TournamentService/Results"
+)
+
+// TournamentServiceClient is the client API for TournamentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TournamentService serves players, tournaments and results like HTTP API does
+type TournamentServiceClient interface {
+	// Fund funds player, new player is returned with created set
+	Fund(ctx context.Context, in *FundRequest, opts ...grpc.CallOption) (*FundResponse, error)
+	Take(ctx context.Context, in *TakeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*Balance, error)
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*Account, error)
+	GetAccount(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Account, error)
+	Suspend(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Account, error)
+	Reinstate(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Account, error)
+	// CloseAccount closes account, with withdraw player is withdrawn from open tournaments
+	CloseAccount(ctx context.Context, in *CloseRequest, opts ...grpc.CallOption) (*Closure, error)
+	ListPlayers(ctx context.Context, in *ListPlayersRequest, opts ...grpc.CallOption) (*PlayerPage, error)
+	GetHistory(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*History, error)
+	// AnnounceTournament announces tournament, tournament with target id is satellite,
+	// tournament with teams can be joined by teams only
+	AnnounceTournament(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// JoinTournament joins player or team into tournament
+	JoinTournament(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetTournament(ctx context.Context, in *TournamentRequest, opts ...grpc.CallOption) (*Tournament, error)
+	ListTournaments(ctx context.Context, in *ListTournamentsRequest, opts ...grpc.CallOption) (*TourPage, error)
+	// Results closes tournament and chooses winners
+	Results(ctx context.Context, in *TournamentRequest, opts ...grpc.CallOption) (*Winners, error)
+}
+
+type tournamentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTournamentServiceClient(cc grpc.ClientConnInterface) TournamentServiceClient {
+	return &tournamentServiceClient{cc}
+}
+
+func (c *tournamentServiceClient) Fund(ctx context.Context, in *FundRequest, opts ...grpc.CallOption) (*FundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FundResponse)
+	err := c.cc.Invoke(ctx, TournamentService_Fund_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) Take(ctx context.Context, in *TakeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TournamentService_Take_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TournamentService_Transfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) GetBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*Balance, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Balance)
+	err := c.cc.Invoke(ctx, TournamentService_GetBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TournamentService_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) GetAccount(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TournamentService_GetAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) Suspend(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TournamentService_Suspend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) Reinstate(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TournamentService_Reinstate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) CloseAccount(ctx context.Context, in *CloseRequest, opts ...grpc.CallOption) (*Closure, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Closure)
+	err := c.cc.Invoke(ctx, TournamentService_CloseAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) ListPlayers(ctx context.Context, in *ListPlayersRequest, opts ...grpc.CallOption) (*PlayerPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlayerPage)
+	err := c.cc.Invoke(ctx, TournamentService_ListPlayers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) GetHistory(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*History, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(History)
+	err := c.cc.Invoke(ctx, TournamentService_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) AnnounceTournament(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TournamentService_AnnounceTournament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) JoinTournament(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TournamentService_JoinTournament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) GetTournament(ctx context.Context, in *TournamentRequest, opts ...grpc.CallOption) (*Tournament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tournament)
+	err := c.cc.Invoke(ctx, TournamentService_GetTournament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) ListTournaments(ctx context.Context, in *ListTournamentsRequest, opts ...grpc.CallOption) (*TourPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TourPage)
+	err := c.cc.Invoke(ctx, TournamentService_ListTournaments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) Results(ctx context.Context, in *TournamentRequest, opts ...grpc.CallOption) (*Winners, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Winners)
+	err := c.cc.Invoke(ctx, TournamentService_Results_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TournamentServiceServer is the server API for TournamentService service.
+// All implementations must embed UnimplementedTournamentServiceServer
+// for forward compatibility.
+//
+// TournamentService serves players, tournaments and results like HTTP API does
+type TournamentServiceServer interface {
+	// Fund funds player, new player is returned with created set
+	Fund(context.Context, *FundRequest) (*FundResponse, error)
+	Take(context.Context, *TakeRequest) (*emptypb.Empty, error)
+	Transfer(context.Context, *TransferRequest) (*emptypb.Empty, error)
+	GetBalance(context.Context, *BalanceRequest) (*Balance, error)
+	Register(context.Context, *RegisterRequest) (*Account, error)
+	GetAccount(context.Context, *PlayerRequest) (*Account, error)
+	Suspend(context.Context, *PlayerRequest) (*Account, error)
+	Reinstate(context.Context, *PlayerRequest) (*Account, error)
+	// CloseAccount closes account, with withdraw player is withdrawn from open tournaments
+	CloseAccount(context.Context, *CloseRequest) (*Closure, error)
+	ListPlayers(context.Context, *ListPlayersRequest) (*PlayerPage, error)
+	GetHistory(context.Context, *PlayerRequest) (*History, error)
+	// AnnounceTournament announces tournament, tournament with target id is satellite,
+	// tournament with teams can be joined by teams only
+	AnnounceTournament(context.Context, *AnnounceRequest) (*emptypb.Empty, error)
+	// JoinTournament joins player or team into tournament
+	JoinTournament(context.Context, *JoinRequest) (*emptypb.Empty, error)
+	GetTournament(context.Context, *TournamentRequest) (*Tournament, error)
+	ListTournaments(context.Context, *ListTournamentsRequest) (*TourPage, error)
+	// Results closes tournament and chooses winners
+	Results(context.Context, *TournamentRequest) (*Winners, error)
+	mustEmbedUnimplementedTournamentServiceServer()
+}
+
+// UnimplementedTournamentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTournamentServiceServer struct{}
+
+func (UnimplementedTournamentServiceServer) Fund(context.Context, *FundRequest) (*FundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Fund not implemented")
+}
+func (UnimplementedTournamentServiceServer) Take(context.Context, *TakeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Take not implemented")
+}
+func (UnimplementedTournamentServiceServer) Transfer(context.Context, *TransferRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedTournamentServiceServer) GetBalance(context.Context, *BalanceRequest) (*Balance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedTournamentServiceServer) Register(context.Context, *RegisterRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedTournamentServiceServer) GetAccount(context.Context, *PlayerRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedTournamentServiceServer) Suspend(context.Context, *PlayerRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suspend not implemented")
+}
+func (UnimplementedTournamentServiceServer) Reinstate(context.Context, *PlayerRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reinstate not implemented")
+}
+func (UnimplementedTournamentServiceServer) CloseAccount(context.Context, *CloseRequest) (*Closure, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseAccount not implemented")
+}
+func (UnimplementedTournamentServiceServer) ListPlayers(context.Context, *ListPlayersRequest) (*PlayerPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlayers not implemented")
+}
+func (UnimplementedTournamentServiceServer) GetHistory(context.Context, *PlayerRequest) (*History, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedTournamentServiceServer) AnnounceTournament(context.Context, *AnnounceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnnounceTournament not implemented")
+}
+func (UnimplementedTournamentServiceServer) JoinTournament(context.Context, *JoinRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinTournament not implemented")
+}
+func (UnimplementedTournamentServiceServer) GetTournament(context.Context, *TournamentRequest) (*Tournament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTournament not implemented")
+}
+func (UnimplementedTournamentServiceServer) ListTournaments(context.Context, *ListTournamentsRequest) (*TourPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTournaments not implemented")
+}
+func (UnimplementedTournamentServiceServer) Results(context.Context, *TournamentRequest) (*Winners, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Results not implemented")
+}
+func (UnimplementedTournamentServiceServer) mustEmbedUnimplementedTournamentServiceServer() {}
+func (UnimplementedTournamentServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeTournamentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TournamentServiceServer will
+// result in compilation errors.
+type UnsafeTournamentServiceServer interface {
+	mustEmbedUnimplementedTournamentServiceServer()
+}
+
+func RegisterTournamentServiceServer(s grpc.ServiceRegistrar, srv TournamentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTournamentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TournamentService_ServiceDesc, srv)
+}
+
+func _TournamentService_Fund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).Fund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_Fund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).Fund(ctx, req.(*FundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_Take_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).Take(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_Take_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).Take(ctx, req.(*TakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_GetBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).GetBalance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).GetAccount(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_Suspend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).Suspend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_Suspend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).Suspend(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_Reinstate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).Reinstate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_Reinstate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).Reinstate(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_CloseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).CloseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_CloseAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).CloseAccount(ctx, req.(*CloseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_ListPlayers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPlayersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).ListPlayers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_ListPlayers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).ListPlayers(ctx, req.(*ListPlayersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).GetHistory(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_AnnounceTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).AnnounceTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_AnnounceTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).AnnounceTournament(ctx, req.(*AnnounceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_JoinTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).JoinTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_JoinTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).JoinTournament(ctx, req.(*JoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_GetTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).GetTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_GetTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).GetTournament(ctx, req.(*TournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_ListTournaments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTournamentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).ListTournaments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_ListTournaments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).ListTournaments(ctx, req.(*ListTournamentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_Results_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).Results(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_Results_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).Results(ctx, req.(*TournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TournamentService_ServiceDesc is the grpc.ServiceDesc for TournamentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TournamentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tournament.v1.TournamentService",
+	HandlerType: (*TournamentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Fund",
+			Handler:    _TournamentService_Fund_Handler,
+		},
+		{
+			MethodName: "Take",
+			Handler:    _TournamentService_Take_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _TournamentService_Transfer_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _TournamentService_GetBalance_Handler,
+		},
+		{
+			MethodName: "Register",
+			Handler:    _TournamentService_Register_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _TournamentService_GetAccount_Handler,
+		},
+		{
+			MethodName: "Suspend",
+			Handler:    _TournamentService_Suspend_Handler,
+		},
+		{
+			MethodName: "Reinstate",
+			Handler:    _TournamentService_Reinstate_Handler,
+		},
+		{
+			MethodName: "CloseAccount",
+			Handler:    _TournamentService_CloseAccount_Handler,
+		},
+		{
+			MethodName: "ListPlayers",
+			Handler:    _TournamentService_ListPlayers_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _TournamentService_GetHistory_Handler,
+		},
+		{
+			MethodName: "AnnounceTournament",
+			Handler:    _TournamentService_AnnounceTournament_Handler,
+		},
+		{
+			MethodName: "JoinTournament",
+			Handler:    _TournamentService_JoinTournament_Handler,
+		},
+		{
+			MethodName: "GetTournament",
+			Handler:    _TournamentService_GetTournament_Handler,
+		},
+		{
+			MethodName: "ListTournaments",
+			Handler:    _TournamentService_ListTournaments_Handler,
+		},
+		{
+			MethodName: "Results",
+			Handler:    _TournamentService_Results_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc/tournament.proto",
+}
@@ -0,0 +1,215 @@
+// Package webhooks delivers events from outbox to webhooks. Every request is signed by webhook secret,
+// failed deliveries are attempted again with exponential backoff, until they become dead.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+)
+
+// Block of headers of delivery request
+const (
+	SignatureHeader = "X-Tournament-Signature"
+	EventHeader     = "X-Tournament-Event"
+	DeliveryHeader  = "X-Tournament-Delivery"
+)
+
+// Block of dispatcher defaults, they are used, if dispatcher setting is not set
+const (
+	DefaultMaxAttempts = 8
+	DefaultBackoff     = 30 * time.Second
+	DefaultMaxBackoff  = time.Hour
+	DefaultLease       = time.Minute
+	DefaultBatch       = 100
+	DefaultWorkers     = 10
+	DefaultTimeout     = 10 * time.Second
+)
+
+// DB is an interface for database, that keeps outbox of deliveries
+type DB interface {
+	ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.Delivery, error)
+	CompleteDelivery(id string) error
+	FailDelivery(delivery entity.Delivery) error
+}
+
+// Dispatcher posts deliveries, which are due, to their webhooks, Workers deliveries at once. Claimed delivery
+// is not given to other dispatchers for lease, so delivery of dispatcher, which has stopped, is attempted again
+// after lease. Requests, which have not finished during lease, are canceled, so other dispatcher cannot post
+// delivery at the same time.
+type Dispatcher struct {
+	DB          DB
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Lease       time.Duration
+	Batch       int
+	Workers     int
+}
+
+// Run dispatches deliveries every interval until stop is closed
+func (d Dispatcher) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			n, err := d.Dispatch(time.Now().UTC())
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("dispatch webhooks: %v deliveries sent", n)
+			}
+		}
+	}
+}
+
+// Dispatch posts deliveries, which are due at now, and returns number of successful ones. Deliveries,
+// which have not been posted during lease, are left in outbox as they are, they are claimed again after lease.
+func (d Dispatcher) Dispatch(now time.Time) (int, error) {
+	lease := orDuration(d.Lease, DefaultLease)
+	ctx, cancel := context.WithTimeout(context.Background(), lease)
+	defer cancel()
+	deliveries, err := d.DB.ClaimDeliveries(now, lease, orInt(d.Batch, DefaultBatch))
+	if err != nil {
+		return 0, err
+	}
+	errs := d.postAll(ctx, deliveries)
+	var sent int
+	for i, delivery := range deliveries {
+		err = errs[i]
+		if err == context.DeadlineExceeded {
+			continue
+		}
+		if err == nil {
+			sent++
+			err = d.DB.CompleteDelivery(delivery.ID)
+			if err != nil {
+				log.Println(err)
+			}
+			continue
+		}
+		delivery.Attempts++
+		delivery.LastError = err.Error()
+		delivery.NextAttempt = now.Add(d.backoff(delivery.Attempts))
+		delivery.Dead = delivery.Attempts >= orInt(d.MaxAttempts, DefaultMaxAttempts)
+		err = d.DB.FailDelivery(delivery)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	return sent, nil
+}
+
+// backoff returns delay before next attempt of delivery, which has failed attempts times
+func (d Dispatcher) backoff(attempts int) time.Duration {
+	delay, limit := orDuration(d.Backoff, DefaultBackoff), orDuration(d.MaxBackoff, DefaultMaxBackoff)
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		return limit
+	}
+	return delay
+}
+
+// postAll posts deliveries by workers and returns their errors in order of deliveries. Error of delivery,
+// which has not been posted before ctx is done, is context.DeadlineExceeded.
+func (d Dispatcher) postAll(ctx context.Context, deliveries []entity.Delivery) []error {
+	errs := make([]error, len(deliveries))
+	workers := make(chan struct{}, orInt(d.Workers, DefaultWorkers))
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = context.DeadlineExceeded
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			errs[i] = d.post(ctx, deliveries[i])
+			if ctx.Err() != nil {
+				errs[i] = context.DeadlineExceeded
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// post sends delivery event to webhook, every status other than 2xx fails delivery. Request is signed
+// with time, when it is sent, so receiver can check its age.
+func (d Dispatcher) post(ctx context.Context, delivery entity.Delivery) error {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event.Type)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, Signature(delivery.Secret, time.Now(), body))
+	client := d.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered with status %v", res.StatusCode)
+	}
+	return nil
+}
+
+// Signature returns signature header of body sent at time: t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">.
+// Receiver computes it with webhook secret and rejects requests with other signature or too old time.
+func Signature(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func orInt(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func orDuration(v, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
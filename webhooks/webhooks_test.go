@@ -0,0 +1,185 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dmitriyomelyusik/Tournament/entity"
+)
+
+// outbox is database of deliveries, which keeps them in memory
+type outbox struct {
+	deliveries map[string]entity.Delivery
+	completed  []string
+}
+
+func (o *outbox) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.Delivery, error) {
+	var claimed []entity.Delivery
+	for id, d := range o.deliveries {
+		if d.Dead || d.NextAttempt.After(now) || len(claimed) == limit {
+			continue
+		}
+		d.NextAttempt = now.Add(lease)
+		o.deliveries[id] = d
+		claimed = append(claimed, d)
+	}
+	return claimed, nil
+}
+
+func (o *outbox) CompleteDelivery(id string) error {
+	delete(o.deliveries, id)
+	o.completed = append(o.completed, id)
+	return nil
+}
+
+func (o *outbox) FailDelivery(d entity.Delivery) error {
+	o.deliveries[d.ID] = d
+	return nil
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	event := entity.Event{Type: entity.EventBalanceChanged, PlayerID: "webhooks_1", Currency: entity.DefaultCurrency, Points: 100,
+		Created: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var mu sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	db := &outbox{deliveries: map[string]entity.Delivery{
+		"1": {ID: "1", WebhookID: "ok", URL: receiver.URL + "/ok", Secret: "secret", Event: event},
+		"2": {ID: "2", WebhookID: "fail", URL: receiver.URL + "/fail", Secret: "secret", Event: event},
+	}}
+	d := Dispatcher{DB: db, Client: receiver.Client(), MaxAttempts: 3, Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sent, err := d.Dispatch(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"1"}, db.completed)
+	if assert.Len(t, received, 2) {
+		for i, r := range received {
+			assert.Equal(t, entity.EventBalanceChanged, r.Header.Get(EventHeader))
+			// request is signed with time, when it has been sent, not with time of dispatch
+			signature := r.Header.Get(SignatureHeader)
+			var sentAt int64
+			_, err := fmt.Sscanf(signature, "t=%d,", &sentAt)
+			assert.Nil(t, err)
+			assert.WithinDuration(t, time.Now(), time.Unix(sentAt, 0), time.Minute)
+			assert.Equal(t, Signature("secret", time.Unix(sentAt, 0), bodies[i]), signature)
+			var e entity.Event
+			assert.Nil(t, json.Unmarshal(bodies[i], &e))
+			assert.Equal(t, event, e)
+		}
+	}
+	failed := db.deliveries["2"]
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, now.Add(time.Second), failed.NextAttempt)
+	assert.Equal(t, "webhook answered with status 500", failed.LastError)
+	assert.False(t, failed.Dead)
+
+	// delivery is not due before backoff has passed
+	sent, err = d.Dispatch(now.Add(500 * time.Millisecond))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, received, 2)
+
+	now = now.Add(time.Second)
+	_, err = d.Dispatch(now)
+	assert.Nil(t, err)
+	failed = db.deliveries["2"]
+	assert.Equal(t, 2, failed.Attempts)
+	assert.Equal(t, now.Add(2*time.Second), failed.NextAttempt)
+
+	now = now.Add(2 * time.Second)
+	_, err = d.Dispatch(now)
+	assert.Nil(t, err)
+	failed = db.deliveries["2"]
+	assert.Equal(t, 3, failed.Attempts)
+	assert.True(t, failed.Dead)
+
+	// dead delivery is not attempted any more
+	_, err = d.Dispatch(now.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Len(t, received, 4)
+}
+
+func TestDispatcher_DispatchLease(t *testing.T) {
+	release := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			// answers only after test has ended
+			<-release
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	defer close(release)
+	event := entity.Event{Type: entity.EventWinnerChosen}
+	db := &outbox{deliveries: map[string]entity.Delivery{
+		"slow": {ID: "slow", URL: receiver.URL + "/slow", Event: event},
+		"ok1":  {ID: "ok1", URL: receiver.URL + "/ok", Event: event},
+		"ok2":  {ID: "ok2", URL: receiver.URL + "/ok", Event: event},
+	}}
+	lease := 200 * time.Millisecond
+	d := Dispatcher{DB: db, Client: receiver.Client(), Lease: lease, Workers: 2}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	start := time.Now()
+	sent, err := d.Dispatch(now)
+	assert.Nil(t, err)
+	// slow delivery does not hold up others and is canceled, when lease ends
+	assert.Equal(t, 2, sent)
+	assert.ElementsMatch(t, []string{"ok1", "ok2"}, db.completed)
+	assert.Less(t, time.Since(start), lease+time.Second)
+	// delivery, which has not been posted during lease, is neither failed nor completed
+	slow := db.deliveries["slow"]
+	assert.Equal(t, 0, slow.Attempts)
+	assert.Equal(t, now.Add(lease), slow.NextAttempt)
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := Dispatcher{Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	tt := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Second},
+		{attempts: 2, expected: 2 * time.Second},
+		{attempts: 4, expected: 8 * time.Second},
+		{attempts: 5, expected: 10 * time.Second},
+		{attempts: 100, expected: 10 * time.Second},
+	}
+
+	for _, tc := range tt {
+		assert.Equal(t, tc.expected, d.backoff(tc.attempts))
+	}
+	assert.Equal(t, DefaultBackoff, Dispatcher{}.backoff(1))
+}
+
+func TestSignature(t *testing.T) {
+	// HMAC-SHA256 of "1893456000.{}" with key "secret"
+	signature := Signature("secret", time.Unix(1893456000, 0), []byte("{}"))
+	assert.Equal(t, "t=1893456000,v1=0dc4b21877b8bd555122d892762124a586f19304f1bf1147e6a69b3fd5689845", signature)
+	assert.NotEqual(t, signature, Signature("other", time.Unix(1893456000, 0), []byte("{}")))
+	assert.NotEqual(t, signature, Signature("secret", time.Unix(1893456001, 0), []byte("{}")))
+}